@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	metrics "github.com/victoriacheng15/personal-reading-analytics/internal/metrics"
+)
+
+// Exit codes of the metrics CLI, documented in docs/architecture/analytics.md
+const (
+	exitOK      = 0 // the command succeeded
+	exitFailure = 1 // the command failed: Sheets, AI, file or parse errors
+	exitUsage   = 2 // unknown command, unknown flag or invalid flag value
+	exitInvalid = 3 // validate found problems, or fetch -strict rejected rows
+	exitAI      = 4 // summarize got no AI delta analysis: no API key, or the model call failed
+)
+
+// exitError tags an error with the exit code the process should end with
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// usageErrorf reports a mistake in how the command was invoked
+func usageErrorf(format string, args ...any) error {
+	return &exitError{code: exitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// invalidErrorf reports snapshots or sheet data that failed a check
+func invalidErrorf(format string, args ...any) error {
+	return &exitError{code: exitInvalid, err: fmt.Errorf(format, args...)}
+}
+
+// exitCode maps a command's error to the process exit code
+func exitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitFailure
+}
+
+// command is one subcommand of the metrics CLI
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, out io.Writer) error
+}
+
+// commands lists every subcommand in the order the usage text shows them
+var commands = []command{
+	{"fetch", "Fetch a snapshot from Google Sheets and save it", runFetchCommand},
+	{"summarize", "Add the AI delta analysis to a saved snapshot", runSummarize},
+	{"diff", "Compare two snapshots", runDiff},
+	{"validate", "Check snapshots for internal consistency", runValidate},
+	{"compact", "Fold loose snapshots into the compressed archive", runCompact},
+	{"export", "Write every snapshot as CSV tables", runExport},
+	{"report", "Write a monthly or annual digest", runReport},
+	{"query", "Answer a question about the snapshot history", runQuery},
+	{"warehouse", "Load snapshots into the SQLite warehouse", runWarehouse},
+	{"backfill", "Reconstruct missing weekly snapshots", runBackfill},
+	{"classify", "Report topic classification coverage", runClassifyCommand},
+}
+
+// printUsage lists the subcommands and exit codes
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: metrics <command> [flags]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nRun 'metrics <command> -h' for the flags of a command.")
+	fmt.Fprintln(w, "\nExit codes: 0 success, 1 failure, 2 usage error, 3 validation failed, 4 AI analysis unavailable.")
+}
+
+// run dispatches args (without the program name) to a subcommand and returns the exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+	if args[0] == "-h" || args[0] == "-help" || args[0] == "--help" || args[0] == "help" {
+		printUsage(stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			err := cmd.run(ctx, args[1:], stdout)
+			if err != nil && !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintf(stderr, "metrics %s: %v\n", cmd.name, err)
+			}
+			return exitCode(err)
+		}
+	}
+
+	fmt.Fprintf(stderr, "metrics: unknown command %q\n\n", args[0])
+	printUsage(stderr)
+	return exitUsage
+}
+
+// newFlagSet creates a subcommand's flag set; parse errors are reported as usage errors
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+// parseFlags parses a subcommand's flags, tagging mistakes with the usage exit code
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &exitError{code: exitUsage, err: err}
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf("unexpected arguments: %v", fs.Args())
+	}
+	return nil
+}
+
+// metricsDirFlag registers the shared -metrics-dir flag
+func metricsDirFlag(fs *flag.FlagSet) *string {
+	return fs.String("metrics-dir", metrics.MetricsDirFromEnv(), "Directory or s3://bucket/prefix snapshots are read from and written to (default from METRICS_DIR)")
+}
+
+// dateFlag registers the shared -date flag; its meaning depends on the command
+func dateFlag(fs *flag.FlagSet, usage string) *string {
+	return fs.String("date", "", usage+" (YYYY-MM-DD)")
+}
+
+// dryRunFlag registers the shared -dry-run flag
+func dryRunFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("dry-run", false, "Report what would be written without writing anything")
+}
+
+// parseDateFlag parses a -date value; an empty value is the zero time
+func parseDateFlag(value string) (time.Time, error) {
+	date, err := metrics.ParseAsOf(value)
+	if err != nil {
+		return time.Time{}, &exitError{code: exitUsage, err: err}
+	}
+	return date, nil
+}
@@ -3,15 +3,22 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"flag"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 
 	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+	"github.com/victoriacheng15/personal-reading-analytics/internal/ai"
 	metrics "github.com/victoriacheng15/personal-reading-analytics/internal/metrics"
+	"github.com/victoriacheng15/personal-reading-analytics/internal/warehouse"
 )
 
 // MetricsFetcher defines the interface for fetching metrics
@@ -20,34 +27,36 @@ type MetricsFetcher interface {
 }
 
 // DefaultMetricsFetcher implements MetricsFetcher
-type DefaultMetricsFetcher struct{}
+type DefaultMetricsFetcher struct {
+	AsOf time.Time // snapshot date; zero means now
+}
 
 // fetchMetricsFunc is a package-level variable that can be mocked in tests
-var fetchMetricsFunc = metrics.FetchMetricsFromSheets
+var fetchMetricsFunc = metrics.FetchMetricsFromSheetsAsOf
+
+// fetchArticlesFunc is a package-level variable that can be mocked in tests
+var fetchArticlesFunc = metrics.FetchArticlesFromSheets
+
+// backfillSnapshotsFunc is a package-level variable that can be mocked in tests
+var backfillSnapshotsFunc = metrics.BackfillSnapshotsFromSheets
 
-// logFatalf is a package-level variable that can be mocked in tests
-var logFatalf = log.Fatalf
+// generateDeltaAnalysisFunc is a package-level variable that can be mocked in tests
+var generateDeltaAnalysisFunc = metrics.GenerateAndSaveDeltaAnalysis
+
+// maxUntaggedTitles caps how many untagged titles the classify report prints
+const maxUntaggedTitles = 50
 
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Warning: .env file not found, will use environment variables")
 	}
 
-	fetchFlag := flag.Bool("fetch", false, "Only fetch metrics from Google Sheets")
-	summarizeFlag := flag.Bool("summarize", false, "Only generate AI delta analysis for the latest metrics")
-	flag.Parse()
-
-	ctx := context.Background()
-	fetcher := &DefaultMetricsFetcher{}
-
-	if err := execute(ctx, fetcher, *fetchFlag, *summarizeFlag); err != nil {
-		logFatalf("%v", err)
-	}
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
 }
 
 // FetchMetrics fetches metrics from Google Sheets
 func (d *DefaultMetricsFetcher) FetchMetrics(ctx context.Context, sheetID, credentialsPath string) (schema.Metrics, error) {
-	return fetchMetricsFunc(ctx, sheetID, credentialsPath)
+	return fetchMetricsFunc(ctx, sheetID, credentialsPath, d.AsOf)
 }
 
 // loadConfiguration loads environment variables and returns sheetID and credentialsPath
@@ -65,35 +74,151 @@ func loadConfiguration() (string, string, error) {
 	return sheetID, credentialsPath, nil
 }
 
-// saveMetrics saves metrics to a JSON file
-func saveMetrics(metricsData schema.Metrics) (string, error) {
-	// Create metrics directory
-	if err := os.MkdirAll("metrics", 0755); err != nil {
-		return "", fmt.Errorf("failed to create metrics directory: %w", err)
-	}
+// snapshotNaming controls the filename a snapshot is saved under
+type snapshotNaming struct {
+	Intraday  bool // name the file after the time of day too, so several snapshots can share a date
+	Overwrite bool // replace an existing snapshot of the same name
+}
 
-	// Marshal to JSON
-	metricsJSON, err := json.MarshalIndent(metricsData, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal metrics: %w", err)
+// snapshotExistsError explains how to get past an existing snapshot
+func snapshotExistsError(store metrics.SnapshotStore, filename string) error {
+	return fmt.Errorf("snapshot %s already exists; pass -overwrite to replace it or -intraday to keep both", store.Location(filename))
+}
+
+// checkSnapshotWritable refuses to replace an existing snapshot unless overwriting was asked for
+func checkSnapshotWritable(ctx context.Context, store metrics.SnapshotStore, filename string, naming snapshotNaming) error {
+	if naming.Overwrite {
+		return nil
+	}
+	filenames, err := store.List(ctx)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, existing := range filenames {
+		if existing == filename {
+			return snapshotExistsError(store, filename)
+		}
 	}
+	return nil
+}
 
+// saveMetrics saves metrics as a JSON snapshot in store
+func saveMetrics(ctx context.Context, store metrics.SnapshotStore, metricsData schema.Metrics, naming snapshotNaming) (string, error) {
 	// Generate filename with date
+	dateFilename := snapshotFilename(metricsData, naming)
 
-	dateFilename := metricsData.LastUpdated.Format("2006-01-02") + ".json"
-	metricsFilePath := fmt.Sprintf("metrics/%s", dateFilename)
-
-	// Write to file
-	if err := os.WriteFile(metricsFilePath, metricsJSON, 0644); err != nil {
+	err := store.Save(ctx, dateFilename, metricsData, naming.Overwrite)
+	if errors.Is(err, metrics.ErrSnapshotExists) {
+		return "", snapshotExistsError(store, dateFilename)
+	}
+	if err != nil {
 		return "", fmt.Errorf("failed to write metrics file: %w", err)
 	}
 
-	log.Printf("✅ Metrics saved to metrics/%s\n", dateFilename)
+	log.Printf("✅ Metrics saved to %s\n", store.Location(dateFilename))
 	return dateFilename, nil
 }
 
-// runFetch executes the fetch logic
-func runFetch(ctx context.Context, fetcher MetricsFetcher) (string, *schema.Metrics, error) {
+// snapshotFilename returns the dated (or timestamped) filename a snapshot is saved under
+func snapshotFilename(metricsData schema.Metrics, naming snapshotNaming) string {
+	return metrics.SnapshotFilename(metricsData.LastUpdated, naming.Intraday)
+}
+
+// saveQualityReport writes the data-quality report next to its snapshot
+func saveQualityReport(ctx context.Context, store metrics.SnapshotStore, report *schema.QualityReport, snapshotFile string) (string, error) {
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal quality report: %w", err)
+	}
+
+	reportFilename := metrics.QualityReportFilename(snapshotFile)
+	if err := store.WriteFile(ctx, reportFilename, reportJSON); err != nil {
+		return "", fmt.Errorf("failed to write quality report: %w", err)
+	}
+
+	log.Printf("✅ Data-quality report saved to %s\n", store.Location(reportFilename))
+	return reportFilename, nil
+}
+
+// printQualitySummary writes row counts and issue counts per kind
+func printQualitySummary(w io.Writer, report *schema.QualityReport) {
+	fmt.Fprintf(w, "Data quality: %d rows scanned, %d rejected, %d findings\n", report.RowsScanned, report.RowsRejected, len(report.Findings))
+	if len(report.Duplicates) > 0 {
+		fmt.Fprintf(w, "  %d duplicate rows merged (see duplicates in the quality report)\n", len(report.Duplicates))
+	}
+
+	kinds := make([]string, 0, len(report.ByKind))
+	for kind := range report.ByKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "  %-22s %d\n", kind, report.ByKind[kind])
+	}
+}
+
+// fetchOptions controls how a fetched snapshot is checked and enriched before it is saved
+type fetchOptions struct {
+	Out        io.Writer             // receives the quality summary and the outcome; nil discards them
+	Store      metrics.SnapshotStore // where the snapshot and its quality report are written
+	Naming     snapshotNaming        // daily or intra-day filename, and whether an existing snapshot may be replaced
+	DryRun     bool                  // fetch and check, but write nothing
+	Strict     bool                  // fail when the data-quality report rejects any row
+	TargetDate time.Time             // backlog forecast target; zero means the end of the snapshot's year
+	Goals      []metrics.Goal
+	SourceMap  map[string]string // lines earlier snapshots up under the canonical source names
+}
+
+// runFetchCommand fetches a snapshot from Google Sheets, checks it and saves it with its quality report
+func runFetchCommand(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("fetch")
+	metricsDir := metricsDirFlag(fs)
+	dateValue := dateFlag(fs, "Build the snapshot as of this date instead of today")
+	dryRun := dryRunFlag(fs)
+	intradayFlag := fs.Bool("intraday", false, "Name the snapshot YYYY-MM-DDTHHMMSS.json so it does not replace today's snapshot")
+	overwriteFlag := fs.Bool("overwrite", false, "Replace an existing snapshot with the same name")
+	strictFlag := fs.Bool("strict", false, "Fail the fetch when the data-quality report rejects any row")
+	goalsPath := fs.String("goals", metrics.DefaultGoalsPath, "Path to the reading goals YAML file")
+	targetDateFlag := fs.String("target-date", "", "Date the backlog forecast aims to clear unread articles by (YYYY-MM-DD, default: end of year)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	asOf, err := parseDateFlag(*dateValue)
+	if err != nil {
+		return err
+	}
+	targetDate, err := parseDateFlag(*targetDateFlag)
+	if err != nil {
+		return err
+	}
+
+	// Goals are optional; without them snapshots simply carry no goal progress
+	goals, err := metrics.LoadGoals(*goalsPath)
+	if err != nil {
+		log.Printf("Warning: No reading goals evaluated: %v\n", err)
+	}
+
+	store, err := metrics.OpenSnapshotStore(*metricsDir)
+	if err != nil {
+		return err
+	}
+
+	naming := snapshotNaming{Intraday: *intradayFlag, Overwrite: *overwriteFlag}
+	opts := fetchOptions{Out: out, Store: store, Naming: naming, DryRun: *dryRun, Strict: *strictFlag, TargetDate: targetDate, Goals: goals, SourceMap: loadSourceMap()}
+	_, _, err = runFetch(ctx, &DefaultMetricsFetcher{AsOf: asOf}, opts)
+	return err
+}
+
+// runFetch executes the fetch logic. In strict mode a rejected row fails the run before the
+// snapshot is saved; the quality report is still written for inspection. Informational findings
+// never fail the run.
+func runFetch(ctx context.Context, fetcher MetricsFetcher, opts fetchOptions) (string, *schema.Metrics, error) {
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+
 	// Load configuration
 	sheetID, credentialsPath, err := loadConfiguration()
 	if err != nil {
@@ -105,79 +230,686 @@ func runFetch(ctx context.Context, fetcher MetricsFetcher) (string, *schema.Metr
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to fetch metrics: %w", err)
 	}
+	filename := snapshotFilename(metricsData, opts.Naming)
+
+	// Refuse before writing anything, including the quality report, when the snapshot exists
+	if err := checkSnapshotWritable(ctx, opts.Store, filename, opts.Naming); err != nil {
+		return "", nil, err
+	}
+
+	// Report rejected and suspicious rows next to the snapshot
+	if report := metricsData.DataQuality; report != nil {
+		printQualitySummary(opts.Out, report)
+
+		reportFilename := metrics.QualityReportFilename(filename)
+		if !opts.DryRun {
+			if reportFilename, err = saveQualityReport(ctx, opts.Store, report, filename); err != nil {
+				return "", nil, err
+			}
+		}
+
+		if opts.Strict && report.RowsRejected > 0 {
+			return "", nil, invalidErrorf("strict mode: %d rows rejected, see %s", report.RowsRejected, opts.Store.Location(reportFilename))
+		}
+	}
+
+	// Forecast, goals and cadence need earlier snapshots; without them those figures are skipped
+	historyOpts := metrics.HistoryOptions{TargetDate: opts.TargetDate, Goals: opts.Goals, SourceMap: opts.SourceMap}
+	if err := metrics.AttachHistoryAnalytics(ctx, opts.Store, &metricsData, historyOpts); err != nil {
+		log.Printf("Warning: Skipping history analytics: %v\n", err)
+	}
+
+	if opts.DryRun {
+		fmt.Fprintf(opts.Out, "Dry run: would save %d articles to %s\n", metricsData.TotalArticles, opts.Store.Location(filename))
+		return filename, &metricsData, nil
+	}
 
 	// Save metrics
-	filename, err := saveMetrics(metricsData)
-	if err != nil {
+	if _, err := saveMetrics(ctx, opts.Store, metricsData, opts.Naming); err != nil {
 		return "", nil, err
 	}
 
-	log.Println("✅ Successfully generated metrics from Google Sheets")
+	fmt.Fprintln(opts.Out, "✅ Successfully generated metrics from Google Sheets")
 	return filename, &metricsData, nil
 }
 
+// selectSnapshot returns the filename of the latest snapshot dated date in store (intra-day
+// snapshots included), or of the latest snapshot when date is zero
+func selectSnapshot(ctx context.Context, store metrics.SnapshotStore, date time.Time) (string, error) {
+	if date.IsZero() {
+		return store.Latest(ctx)
+	}
+	filenames, err := store.List(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	want := date.Format(metrics.SnapshotDateLayout)
+	for i := len(filenames) - 1; i >= 0; i-- {
+		if metrics.SnapshotFileDate(filenames[i]) == want {
+			return filenames[i], nil
+		}
+	}
+	return "", fmt.Errorf("no snapshot dated %s in %s", date.Format("2006-01-02"), store.Location(""))
+}
+
+// runSummarize writes the AI delta analysis of a saved snapshot, the one dated -date or the latest,
+// to its analysis sidecar. Failures of the AI step end with exitAI, unless -skip-without-key turns
+// a missing API key into a skipped run.
+func runSummarize(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("summarize")
+	metricsDir := metricsDirFlag(fs)
+	dateValue := dateFlag(fs, "Snapshot to analyze (default: the latest)")
+	dryRun := dryRunFlag(fs)
+	skipWithoutKey := fs.Bool("skip-without-key", false, "Succeed without writing an analysis when GEMINI_API_KEY is not set")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	date, err := parseDateFlag(*dateValue)
+	if err != nil {
+		return err
+	}
+	store, err := metrics.OpenSnapshotStore(*metricsDir)
+	if err != nil {
+		return err
+	}
+	filename, err := selectSnapshot(ctx, store, date)
+	if err != nil {
+		return err
+	}
+	snapshot, err := store.Load(ctx, filename)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Fprintf(out, "Dry run: would write the AI delta analysis of %s to %s\n", store.Location(filename), store.Location(metrics.AnalysisFilename(filename)))
+		return nil
+	}
+	err = runDeltaAnalysis(ctx, store, filename, &snapshot, out)
+	switch {
+	case *skipWithoutKey && errors.Is(err, ai.ErrMissingAPIKey):
+		fmt.Fprintf(out, "Skipping AI delta analysis of %s: %v\n", store.Location(filename), ai.ErrMissingAPIKey)
+		return nil
+	case errors.Is(err, metrics.ErrDeltaAnalysisUnavailable):
+		return &exitError{code: exitAI, err: err}
+	}
+	return err
+}
+
 // runDeltaAnalysis executes the AI delta analysis logic
-func runDeltaAnalysis(ctx context.Context, filename string, metricsData *schema.Metrics) error {
+func runDeltaAnalysis(ctx context.Context, store metrics.SnapshotStore, filename string, metricsData *schema.Metrics, out io.Writer) error {
 	if filename == "" || metricsData == nil {
 		return fmt.Errorf("metrics data not provided for delta analysis")
 	}
 
 	// Generate AI Delta Analysis
-	if err := metrics.GenerateAndSaveDeltaAnalysis(ctx, "metrics", filename, metricsData); err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating AI delta analysis: %v\n", err)
+	if err := generateDeltaAnalysisFunc(ctx, store, filename, metricsData); err != nil {
+		return fmt.Errorf("AI delta analysis of %s failed: %w", store.Location(filename), err)
 	}
-	log.Println("✅ AI Delta Analysis generated and saved.")
+	fmt.Fprintln(out, "✅ AI Delta Analysis generated and saved.")
 	return nil
 }
 
-// execute runs the application logic based on flags
-func execute(ctx context.Context, fetcher MetricsFetcher, fetchFlag, summarizeFlag bool) error {
-	// Default behavior: Run both
-	runBoth := !fetchFlag && !summarizeFlag
+// runDiff prints the changes between two snapshots: -date (default: the latest) against
+// -against (default: the snapshot before it)
+func runDiff(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("diff")
+	metricsDir := metricsDirFlag(fs)
+	dateValue := dateFlag(fs, "Newer snapshot (default: the latest)")
+	againstValue := fs.String("against", "", "Older snapshot (YYYY-MM-DD, default: the one before -date)")
+	formatFlag := fs.String("format", metrics.QueryFormatTable, "Output format: table or json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
 
-	var filename string
-	var metricsData *schema.Metrics
-	var err error
+	date, err := parseDateFlag(*dateValue)
+	if err != nil {
+		return err
+	}
+	against, err := parseDateFlag(*againstValue)
+	if err != nil {
+		return err
+	}
 
-	if runBoth || fetchFlag {
-		filename, metricsData, err = runFetch(ctx, fetcher)
+	store, err := metrics.OpenSnapshotStore(*metricsDir)
+	if err != nil {
+		return err
+	}
+	toFile, err := selectSnapshot(ctx, store, date)
+	if err != nil {
+		return err
+	}
+	var fromFile string
+	if against.IsZero() {
+		fromFile, err = store.Previous(ctx, toFile)
+		if errors.Is(err, metrics.ErrNoPreviousSnapshot) {
+			return fmt.Errorf("no snapshot before %s to compare with", toFile)
+		}
 		if err != nil {
-			return fmt.Errorf("Error fetching metrics: %w", err)
-		}
-	}
-
-	if runBoth || summarizeFlag {
-		if summarizeFlag && filename == "" {
-			// Standalone mode: Find latest file in metrics/ dir
-			entries, err := os.ReadDir("metrics")
-			if err == nil {
-				// Find last one
-				var lastFile string
-				for _, e := range entries {
-					if !e.IsDir() {
-						lastFile = e.Name()
-					}
-				}
-				if lastFile != "" {
-					filename = lastFile
-					// Load it
-					bytes, _ := os.ReadFile("metrics/" + lastFile)
-					var m schema.Metrics
-					if json.Unmarshal(bytes, &m) == nil {
-						metricsData = &m
-					}
-				}
-			}
+			return fmt.Errorf("unable to find the snapshot before %s: %w", toFile, err)
 		}
+	} else if fromFile, err = selectSnapshot(ctx, store, against); err != nil {
+		return err
+	}
 
-		if metricsData != nil {
-			if err := runDeltaAnalysis(ctx, filename, metricsData); err != nil {
-				log.Printf("Warning: AI delta analysis failed: %v", err)
-				// Don't error here, as the primary metrics are safe
-			}
+	canonicalize := canonicalizeSourcesFunc()
+	snapshots := make([]schema.Metrics, 2)
+	for i, filename := range []string{fromFile, toFile} {
+		if snapshots[i], err = store.Load(ctx, filename); err != nil {
+			return err
+		}
+		canonicalize(&snapshots[i])
+	}
+
+	if *formatFlag == metrics.QueryFormatTable {
+		fmt.Fprintf(out, "%s → %s\n\n", strings.TrimSuffix(fromFile, ".json"), strings.TrimSuffix(toFile, ".json"))
+	}
+	return metrics.DiffSnapshots(snapshots[0], snapshots[1]).Write(out, *formatFlag)
+}
+
+// runValidate checks every snapshot in the metrics directory (or the one dated -date) for
+// internal consistency and fails with the validation exit code when any problem is found
+func runValidate(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("validate")
+	metricsDir := metricsDirFlag(fs)
+	dateValue := dateFlag(fs, "Only validate this snapshot (default: all)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	date, err := parseDateFlag(*dateValue)
+	if err != nil {
+		return err
+	}
+
+	store, err := metrics.OpenSnapshotStore(*metricsDir)
+	if err != nil {
+		return err
+	}
+
+	var filenames []string
+	if date.IsZero() {
+		if filenames, err = store.List(ctx); err != nil {
+			return err
+		}
+		if len(filenames) == 0 {
+			return fmt.Errorf("no snapshots in %s", store.Location(""))
+		}
+	} else {
+		filename, err := selectSnapshot(ctx, store, date)
+		if err != nil {
+			return err
+		}
+		filenames = []string{filename}
+	}
+
+	problems, invalid := 0, 0
+	for _, filename := range filenames {
+		var found []string
+		snapshot, err := store.Load(ctx, filename)
+		if err != nil {
+			found = []string{err.Error()}
+		} else {
+			found = metrics.ValidateSnapshot(filename, snapshot)
+		}
+
+		for _, problem := range found {
+			fmt.Fprintf(out, "%s: %s\n", store.Location(filename), problem)
+		}
+		if len(found) > 0 {
+			problems += len(found)
+			invalid++
+		}
+	}
+
+	fmt.Fprintf(out, "Validated %d snapshots: %d problems\n", len(filenames), problems)
+	if problems > 0 {
+		return invalidErrorf("%d problems in %d of %d snapshots", problems, invalid, len(filenames))
+	}
+	return nil
+}
+
+// runCompact folds the loose snapshots in the metrics directory into its compressed archive and
+// removes them; later commands read archived snapshots transparently
+func runCompact(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("compact")
+	metricsDir := metricsDirFlag(fs)
+	dryRun := dryRunFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	store, err := metrics.OpenSnapshotStore(*metricsDir)
+	if err != nil {
+		return err
+	}
+
+	result, err := metrics.CompactSnapshots(ctx, store, *dryRun)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Fprintf(out, "Dry run: would compact %d snapshots (%d loose, %d bytes of JSON) into %s (%d bytes)\n",
+			result.Snapshots, result.Added, result.JSONBytes, store.Location(metrics.ArchiveFilename), result.ArchiveBytes)
+		return nil
+	}
+	fmt.Fprintf(out, "✅ Compacted %d snapshots (%d loose, %d bytes of JSON) into %s (%d bytes)\n",
+		result.Snapshots, result.Added, result.JSONBytes, store.Location(metrics.ArchiveFilename), result.ArchiveBytes)
+	return nil
+}
+
+// runBackfill reconstructs weekly snapshots between -from and -to from the article ledger
+// and writes those missing from the metrics directory. Existing snapshots are never overwritten.
+func runBackfill(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("backfill")
+	metricsDir := metricsDirFlag(fs)
+	dryRun := dryRunFlag(fs)
+	fromFlag := fs.String("from", "", "First snapshot date to reconstruct (YYYY-MM-DD)")
+	toFlag := fs.String("to", "", "Last snapshot date to reconstruct (YYYY-MM-DD)")
+	goalsPath := fs.String("goals", metrics.DefaultGoalsPath, "Path to the reading goals YAML file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *fromFlag == "" || *toFlag == "" {
+		return usageErrorf("backfill requires -from and -to dates")
+	}
+	from, err := parseDateFlag(*fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseDateFlag(*toFlag)
+	if err != nil {
+		return err
+	}
+	if to.Before(from) {
+		return usageErrorf("backfill -to %s is before -from %s", *toFlag, *fromFlag)
+	}
+
+	store, err := metrics.OpenSnapshotStore(*metricsDir)
+	if err != nil {
+		return err
+	}
+
+	// Only reconstruct dates that have no snapshot yet, daily or intra-day
+	existing := make(map[string]bool)
+	filenames, err := store.List(ctx)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, filename := range filenames {
+		existing[metrics.SnapshotFileDate(filename)] = true
+	}
+
+	// Reconstructed dates follow the weekday of the latest real snapshot
+	var anchor time.Time
+	if len(filenames) > 0 {
+		anchor, _ = time.Parse(metrics.SnapshotDateLayout, metrics.SnapshotFileDate(filenames[len(filenames)-1]))
+	}
+
+	var missing []time.Time
+	for _, date := range metrics.BackfillDates(from, to, anchor) {
+		if existing[date.Format("2006-01-02")] {
+			fmt.Fprintf(out, "Skipping %s: snapshot already exists\n", date.Format("2006-01-02"))
+			continue
+		}
+		missing = append(missing, date)
+	}
+	if len(missing) == 0 {
+		fmt.Fprintln(out, "No missing snapshots to backfill.")
+		return nil
+	}
+	if *dryRun {
+		for _, date := range missing {
+			fmt.Fprintf(out, "Dry run: would reconstruct %s\n", store.Location(metrics.SnapshotFilename(date, false)))
+		}
+		return nil
+	}
+
+	sheetID, credentialsPath, err := loadConfiguration()
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := backfillSnapshotsFunc(ctx, sheetID, credentialsPath, missing)
+	if err != nil {
+		return fmt.Errorf("failed to reconstruct snapshots: %w", err)
+	}
+
+	// Goals are optional, as for fetch
+	goals, err := metrics.LoadGoals(*goalsPath)
+	if err != nil {
+		log.Printf("Warning: No reading goals evaluated: %v\n", err)
+	}
+	historyOpts := metrics.HistoryOptions{Goals: goals, SourceMap: loadSourceMap()}
+
+	// Oldest first, so each snapshot's forecast, cadence, goals and trends see the ones saved before it
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].LastUpdated.Before(snapshots[j].LastUpdated) })
+	for i := range snapshots {
+		if err := metrics.AttachHistoryAnalytics(ctx, store, &snapshots[i], historyOpts); err != nil {
+			log.Printf("Warning: Skipping history analytics for %s: %v\n", snapshots[i].LastUpdated.Format("2006-01-02"), err)
+		}
+		if _, err := saveMetrics(ctx, store, snapshots[i], snapshotNaming{}); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "✅ Backfilled %d reconstructed snapshots\n", len(snapshots))
+	return nil
+}
+
+// loadCanonicalHistory loads every snapshot at location, oldest first, with renamed sources lined
+// up under their canonical names. It fails when there are no snapshots.
+func loadCanonicalHistory(ctx context.Context, location string) ([]schema.Metrics, error) {
+	store, err := metrics.OpenSnapshotStore(location)
+	if err != nil {
+		return nil, err
+	}
+	history, err := metrics.LoadSnapshotHistory(ctx, store, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no snapshots in %s", store.Location(""))
+	}
+
+	canonicalize := canonicalizeSourcesFunc()
+	for i := range history {
+		canonicalize(&history[i])
+	}
+	return history, nil
+}
+
+// loadSourceMap builds the source normalization map from config/sources.yml; without the config
+// sources keep their names
+func loadSourceMap() map[string]string {
+	aliases, err := metrics.LoadSourceAliases(metrics.DefaultSourceAliasesPath)
+	if err != nil {
+		log.Printf("Warning: Source aliases disabled: %v\n", err)
+	}
+	return metrics.BuildSourceMap(nil, aliases)
+}
+
+// canonicalizeSourcesFunc returns a function that lines renamed sources up under the canonical
+// names from the source aliases file
+func canonicalizeSourcesFunc() func(*schema.Metrics) {
+	sourceMap := loadSourceMap()
+	return func(m *schema.Metrics) {
+		metrics.CanonicalizeSources(m, sourceMap)
+	}
+}
+
+// runWarehouse loads the snapshots in the metrics directory into the SQLite warehouse, skipping files whose
+// content is unchanged since the last run. With -articles the article ledger is reloaded too.
+func runWarehouse(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("warehouse")
+	metricsDir := metricsDirFlag(fs)
+	dbPath := fs.String("db", warehouse.DefaultPath, "Path of the SQLite database")
+	rebuildFlag := fs.Bool("rebuild", false, "Reload every snapshot, e.g. after editing config/sources.yml")
+	articlesFlag := fs.Bool("articles", false, "Also load the article ledger from Google Sheets")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	store, err := metrics.OpenSnapshotStore(*metricsDir)
+	if err != nil {
+		return err
+	}
+
+	w, err := warehouse.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	stats, err := w.Refresh(ctx, store, canonicalizeSourcesFunc(), *rebuildFlag)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✅ Warehouse %s refreshed: %d snapshots loaded, %d unchanged, %d removed\n", *dbPath, stats.Loaded, stats.Unchanged, stats.Removed)
+
+	if !*articlesFlag {
+		return nil
+	}
+
+	sheetID, credentialsPath, err := loadConfiguration()
+	if err != nil {
+		return err
+	}
+	articles, err := fetchArticlesFunc(ctx, sheetID, credentialsPath)
+	if err != nil {
+		return fmt.Errorf("failed to fetch articles: %w", err)
+	}
+	if err := w.LoadArticles(articles); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✅ Loaded %d articles into %s\n", len(articles), *dbPath)
+	return nil
+}
+
+// runExport flattens every snapshot in the metrics directory into CSV tables: breakdowns.csv in long format
+// and summary.csv with one row per snapshot
+func runExport(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("export")
+	metricsDir := metricsDirFlag(fs)
+	dryRun := dryRunFlag(fs)
+	outDir := fs.String("out", "exports", "Directory the CSV files are written to")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	history, err := loadCanonicalHistory(ctx, *metricsDir)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Fprintf(out, "Dry run: would export %d snapshots to %s/breakdowns.csv and %s/summary.csv\n", len(history), *outDir, *outDir)
+		return nil
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	tables := []struct {
+		name  string
+		write func(io.Writer, []schema.Metrics) error
+	}{
+		{"breakdowns.csv", metrics.WriteBreakdownCSV},
+		{"summary.csv", metrics.WriteSummaryCSV},
+	}
+	for _, table := range tables {
+		path := filepath.Join(*outDir, table.name)
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		err = table.write(f, history)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", path, err)
+		}
+	}
+
+	fmt.Fprintf(out, "✅ Exported %d snapshots to %s/breakdowns.csv and %s/summary.csv\n", len(history), *outDir, *outDir)
+	return nil
+}
+
+// queryNames lists the questions the query subcommand answers
+var queryNames = []string{"unread", "readrate", "top-unread"}
+
+// runQuery answers one question about the snapshots in the metrics directory and prints the answer as a
+// table or JSON. args start with the query name followed by its flags, e.g.
+// `unread --source GitHub --since 2026-01-01`.
+func runQuery(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "" || args[0][0] == '-' {
+		return usageErrorf("query requires one of: %s", strings.Join(queryNames, ", "))
+	}
+	name := args[0]
+
+	fs := newFlagSet("query " + name)
+	metricsDir := metricsDirFlag(fs)
+	formatFlag := fs.String("format", metrics.QueryFormatTable, "Output format: table or json")
+	sourceFlag := fs.String("source", "", "Only this source (unread, top-unread)")
+	sinceFlag := fs.String("since", "", "First snapshot date to include (YYYY-MM-DD, unread)")
+	byFlag := fs.String("by", "", "Group by source, year, month or category (readrate, default: overall)")
+	weeksFlag := fs.Int("weeks", 12, "Number of recent weeks (readrate)")
+	nFlag := fs.Int("n", 10, "Number of articles (top-unread)")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+
+	history, err := loadCanonicalHistory(ctx, *metricsDir)
+	if err != nil {
+		return err
+	}
+
+	var result metrics.QueryResult
+	switch name {
+	case "unread":
+		var since time.Time
+		if since, err = parseDateFlag(*sinceFlag); err != nil {
+			return err
+		}
+		result, err = metrics.QueryUnread(history, *sourceFlag, since)
+	case "readrate":
+		result, err = metrics.QueryReadRate(history, *byFlag, *weeksFlag)
+	case "top-unread":
+		result, err = metrics.QueryTopUnread(history, *sourceFlag, *nFlag)
+	default:
+		return usageErrorf("unknown query %q (use one of: %s)", name, strings.Join(queryNames, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	return result.Write(out, *formatFlag)
+}
+
+// runReport writes a monthly or annual digest built from the snapshots in the metrics directory to
+// reports/<period>.md and reports/<period>.html
+func runReport(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("report")
+	metricsDir := metricsDirFlag(fs)
+	dryRun := dryRunFlag(fs)
+	periodFlag := fs.String("period", metrics.DigestMonthly, "Digest period: monthly or annual")
+	forFlag := fs.String("for", "", "Month (YYYY-MM) or year (YYYY) to report on (default: that of the latest snapshot)")
+	outDir := fs.String("out", "reports", "Directory the digest files are written to")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	history, err := loadCanonicalHistory(ctx, *metricsDir)
+	if err != nil {
+		return err
+	}
+
+	value := *forFlag
+	if value == "" {
+		latest := history[len(history)-1].LastUpdated
+		if *periodFlag == metrics.DigestAnnual {
+			value = latest.Format("2006")
 		} else {
-			log.Println("No metrics data available to perform delta analysis.")
+			value = latest.Format("2006-01")
+		}
+	}
+	start, err := metrics.DigestPeriodStart(*periodFlag, value)
+	if err != nil {
+		return &exitError{code: exitUsage, err: err}
+	}
+
+	digest, err := metrics.BuildDigest(history, *periodFlag, start)
+	if err != nil {
+		return err
+	}
+
+	markdown, err := metrics.RenderDigestMarkdown(digest)
+	if err != nil {
+		return err
+	}
+	html, err := metrics.RenderDigestHTML(digest)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Fprintf(out, "Dry run: would write the %s digest for %s to %s/%s.{md,html}\n", *periodFlag, digest.Label, *outDir, digest.Key)
+		return nil
+	}
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create reports directory: %w", err)
+	}
+	for ext, content := range map[string]string{".md": markdown, ".html": html} {
+		path := filepath.Join(*outDir, digest.Key+ext)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write digest %s: %w", path, err)
 		}
 	}
+
+	fmt.Fprintf(out, "✅ %s digest for %s written to %s/%s.{md,html}\n", *periodFlag, digest.Label, *outDir, digest.Key)
+	return nil
+}
+
+// runClassifyCommand prints the topic classification report for the rules file given by -rules
+func runClassifyCommand(ctx context.Context, args []string, out io.Writer) error {
+	fs := newFlagSet("classify")
+	rulesPath := fs.String("rules", metrics.DefaultTopicRulesPath, "Path to the topic rules YAML file")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	return runClassify(ctx, *rulesPath, out)
+}
+
+// runClassify tags every article with the topic rules and prints a coverage report
+func runClassify(ctx context.Context, rulesPath string, w io.Writer) error {
+	classifier, err := metrics.LoadClassifier(rulesPath)
+	if err != nil {
+		return fmt.Errorf("failed to load topic rules: %w", err)
+	}
+
+	sheetID, credentialsPath, err := loadConfiguration()
+	if err != nil {
+		return err
+	}
+
+	articles, err := fetchArticlesFunc(ctx, sheetID, credentialsPath)
+	if err != nil {
+		return fmt.Errorf("failed to fetch articles: %w", err)
+	}
+
+	report := metrics.BuildClassificationReport(articles, classifier)
+	printClassificationReport(w, report, maxUntaggedTitles)
 	return nil
 }
+
+// printClassificationReport writes coverage, per-topic counts and a sample of untagged titles
+func printClassificationReport(w io.Writer, report metrics.ClassificationReport, maxUntagged int) {
+	fmt.Fprintf(w, "Topic coverage: %d/%d articles tagged (%.1f%%)\n", report.Tagged, report.Total, report.Coverage)
+
+	topics := make([]string, 0, len(report.ByTopic))
+	for topic := range report.ByTopic {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if report.ByTopic[topics[i]] != report.ByTopic[topics[j]] {
+			return report.ByTopic[topics[i]] > report.ByTopic[topics[j]]
+		}
+		return topics[i] < topics[j]
+	})
+
+	fmt.Fprintln(w, "\nArticles per topic:")
+	for _, topic := range topics {
+		fmt.Fprintf(w, "  %-30s %d\n", topic, report.ByTopic[topic])
+	}
+
+	fmt.Fprintf(w, "\nUntagged titles (%d):\n", len(report.Untagged))
+	for i, article := range report.Untagged {
+		if i >= maxUntagged {
+			fmt.Fprintf(w, "  ... and %d more\n", len(report.Untagged)-maxUntagged)
+			break
+		}
+		fmt.Fprintf(w, "  %s [%s] %s\n", article.Date, article.Category, article.Title)
+	}
+}
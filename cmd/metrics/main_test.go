@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +11,9 @@ import (
 	"time"
 
 	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+	"github.com/victoriacheng15/personal-reading-analytics/internal/ai"
+	metrics "github.com/victoriacheng15/personal-reading-analytics/internal/metrics"
+	"github.com/victoriacheng15/personal-reading-analytics/internal/warehouse"
 )
 
 // MockMetricsFetcher implements MetricsFetcher for testing
@@ -129,17 +134,43 @@ func TestSaveMetrics(t *testing.T) {
 	mockMetrics := createMockMetrics(lastUpdated)
 
 	tests := []struct {
-		name        string
-		setup       func(dir string) error
-		metrics     schema.Metrics
-		expectError bool
-		errorSubstr string
+		name             string
+		setup            func(dir string) error
+		metrics          schema.Metrics
+		naming           snapshotNaming
+		expectError      bool
+		errorSubstr      string
+		expectedFilename string
 	}{
 		{
-			name:        "Success",
-			setup:       func(dir string) error { return nil },
+			name:             "Success",
+			setup:            func(dir string) error { return nil },
+			metrics:          mockMetrics,
+			expectError:      false,
+			expectedFilename: "2025-12-21.json",
+		},
+		{
+			name:        "Existing snapshot is not replaced",
+			setup:       writeExistingSnapshot,
 			metrics:     mockMetrics,
-			expectError: false,
+			expectError: true,
+			errorSubstr: "already exists",
+		},
+		{
+			name:             "Existing snapshot replaced with overwrite",
+			setup:            writeExistingSnapshot,
+			metrics:          mockMetrics,
+			naming:           snapshotNaming{Overwrite: true},
+			expectError:      false,
+			expectedFilename: "2025-12-21.json",
+		},
+		{
+			name:             "Intraday snapshot kept next to the daily one",
+			setup:            writeExistingSnapshot,
+			metrics:          mockMetrics,
+			naming:           snapshotNaming{Intraday: true},
+			expectError:      false,
+			expectedFilename: "2025-12-21T103000.json",
 		},
 		{
 			name: "Write Error (Directory blocked)",
@@ -159,6 +190,7 @@ func TestSaveMetrics(t *testing.T) {
 				return os.Mkdir(filepath.Join("metrics", "2025-12-21.json"), 0755)
 			},
 			metrics:     mockMetrics,
+			naming:      snapshotNaming{Overwrite: true},
 			expectError: true,
 			errorSubstr: "failed to write metrics file",
 		},
@@ -180,7 +212,7 @@ func TestSaveMetrics(t *testing.T) {
 				t.Fatalf("Setup failed: %v", err)
 			}
 
-			filename, err := saveMetrics(tt.metrics)
+			filename, err := saveMetrics(context.Background(), metrics.NewFileStore("metrics"), tt.metrics, tt.naming)
 
 			if tt.expectError {
 				if err == nil {
@@ -192,9 +224,8 @@ func TestSaveMetrics(t *testing.T) {
 				if err != nil {
 					t.Errorf("Unexpected error: %v", err)
 				}
-				expectedFilename := "2025-12-21.json"
-				if filename != expectedFilename {
-					t.Errorf("Expected filename %q, got %q", expectedFilename, filename)
+				if filename != tt.expectedFilename {
+					t.Errorf("Expected filename %q, got %q", tt.expectedFilename, filename)
 				}
 				// Verify file exists
 				if _, err := os.Stat(filepath.Join("metrics", filename)); err != nil {
@@ -205,6 +236,14 @@ func TestSaveMetrics(t *testing.T) {
 	}
 }
 
+// writeExistingSnapshot seeds metrics/2025-12-21.json so saving on that date collides
+func writeExistingSnapshot(dir string) error {
+	if err := os.MkdirAll("metrics", 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join("metrics", "2025-12-21.json"), []byte("{}"), 0644)
+}
+
 // TestRunFetch tests the runFetch function
 func TestRunFetch(t *testing.T) {
 	lastUpdated := time.Date(2025, 12, 21, 10, 30, 0, 0, time.UTC)
@@ -270,7 +309,7 @@ func TestRunFetch(t *testing.T) {
 			}
 			os.Setenv("CREDENTIALS_PATH", "dummy.json")
 
-			filename, metrics, err := runFetch(context.Background(), tt.fetcher)
+			filename, metricsData, err := runFetch(context.Background(), tt.fetcher, fetchOptions{Store: metrics.NewFileStore("metrics")})
 
 			if tt.expectError {
 				if err == nil {
@@ -285,7 +324,7 @@ func TestRunFetch(t *testing.T) {
 				if filename == "" {
 					t.Error("Expected filename to be returned")
 				}
-				if metrics == nil {
+				if metricsData == nil {
 					t.Error("Expected metrics to be returned")
 				}
 			}
@@ -293,36 +332,84 @@ func TestRunFetch(t *testing.T) {
 	}
 }
 
-// TestMainBehavior tests the main execution logic via execute()
+// TestMainBehavior tests subcommand dispatch and exit codes via run()
 func TestMainBehavior(t *testing.T) {
 	tests := []struct {
 		name              string
+		args              []string
 		sheetID           string
 		fetchSuccess      bool
-		expectError       bool
+		expectCode        int
 		expectFileCreated bool
 	}{
 		{
-			name:              "Success path",
+			name:              "Fetch success",
+			args:              []string{"fetch"},
 			sheetID:           "test-sheet-123",
 			fetchSuccess:      true,
-			expectError:       false,
+			expectCode:        exitOK,
 			expectFileCreated: true,
 		},
 		{
-			name:              "Config failure",
-			sheetID:           "",
-			fetchSuccess:      false, // Irrelevant
-			expectError:       true,
+			name:              "Fetch into another directory",
+			args:              []string{"fetch", "-metrics-dir", "snapshots"},
+			sheetID:           "test-sheet-123",
+			fetchSuccess:      true,
+			expectCode:        exitOK,
 			expectFileCreated: false,
 		},
 		{
-			name:              "Fetch failure",
+			name:              "Fetch dry run",
+			args:              []string{"fetch", "-dry-run"},
 			sheetID:           "test-sheet-123",
-			fetchSuccess:      false,
-			expectError:       true,
+			fetchSuccess:      true,
+			expectCode:        exitOK,
 			expectFileCreated: false,
 		},
+		{
+			name:       "Config failure",
+			args:       []string{"fetch"},
+			sheetID:    "",
+			expectCode: exitFailure,
+		},
+		{
+			name:         "Fetch failure",
+			args:         []string{"fetch"},
+			sheetID:      "test-sheet-123",
+			fetchSuccess: false,
+			expectCode:   exitFailure,
+		},
+		{
+			name:       "No command",
+			args:       nil,
+			expectCode: exitUsage,
+		},
+		{
+			name:       "Unknown command",
+			args:       []string{"publish"},
+			expectCode: exitUsage,
+		},
+		{
+			name:       "Legacy flag",
+			args:       []string{"-fetch"},
+			expectCode: exitUsage,
+		},
+		{
+			name:       "Unknown flag",
+			args:       []string{"fetch", "-verbose"},
+			expectCode: exitUsage,
+		},
+		{
+			name:       "Invalid date",
+			args:       []string{"fetch", "-date", "12/21/2025"},
+			sheetID:    "test-sheet-123",
+			expectCode: exitUsage,
+		},
+		{
+			name:       "Help",
+			args:       []string{"help"},
+			expectCode: exitOK,
+		},
 	}
 
 	for _, tt := range tests {
@@ -355,26 +442,16 @@ func TestMainBehavior(t *testing.T) {
 			os.Setenv("CREDENTIALS_PATH", "creds.json")
 
 			// Mock FetchMetrics
-			fetchMetricsFunc = func(ctx context.Context, sheetID, credentialsPath string) (schema.Metrics, error) {
+			fetchMetricsFunc = func(ctx context.Context, sheetID, credentialsPath string, asOf time.Time) (schema.Metrics, error) {
 				if tt.fetchSuccess {
 					return createMockMetrics(time.Date(2025, 12, 21, 10, 30, 0, 0, time.UTC)), nil
 				}
 				return schema.Metrics{}, fmt.Errorf("fetch failed")
 			}
 
-			// Call execute() directly instead of main() to avoid flag redefinition
-			fetcher := &DefaultMetricsFetcher{}
-			// Default flags: fetch=false, summarize=false -> runs both
-			err = execute(context.Background(), fetcher, false, false)
-
-			if tt.expectError {
-				if err == nil {
-					t.Error("Expected error but got none")
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Unexpected error: %v", err)
-				}
+			var stdout, stderr bytes.Buffer
+			if code := run(context.Background(), tt.args, &stdout, &stderr); code != tt.expectCode {
+				t.Errorf("Exit code %d, expected %d (stderr: %s)", code, tt.expectCode, stderr.String())
 			}
 
 			// Check file existence
@@ -402,3 +479,890 @@ func searchString(s, substr string) bool {
 	}
 	return false
 }
+
+// TestRunClassify tests the topic classification report command
+func TestRunClassify(t *testing.T) {
+	tmpDir := t.TempDir()
+	rulesPath := filepath.Join(tmpDir, "topics.yml")
+	rules := "rules:\n  - topic: Go\n    priority: 10\n    keywords: [\"golang\"]\n"
+	if err := os.WriteFile(rulesPath, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	originalSheetID := os.Getenv("SHEET_ID")
+	originalFetchArticlesFunc := fetchArticlesFunc
+	defer func() {
+		os.Setenv("SHEET_ID", originalSheetID)
+		fetchArticlesFunc = originalFetchArticlesFunc
+	}()
+	os.Setenv("SHEET_ID", "test-sheet-123")
+
+	fetchArticlesFunc = func(ctx context.Context, sheetID, credentialsPath string) ([]schema.ArticleMeta, error) {
+		return []schema.ArticleMeta{
+			{Date: "2025-12-01", Title: "Golang tips", Category: "Substack"},
+			{Date: "2025-12-02", Title: "Weekly notes", Category: "GitHub"},
+		}, nil
+	}
+
+	t.Run("Prints coverage and untagged titles", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := runClassify(context.Background(), rulesPath, &out); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		output := out.String()
+		if !contains(output, "1/2 articles tagged (50.0%)") {
+			t.Errorf("Expected coverage line, got %q", output)
+		}
+		if !contains(output, "Weekly notes") {
+			t.Errorf("Expected untagged title in output, got %q", output)
+		}
+	})
+
+	t.Run("Missing rules file", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runClassify(context.Background(), filepath.Join(tmpDir, "missing.yml"), &out)
+		if err == nil || !contains(err.Error(), "failed to load topic rules") {
+			t.Errorf("Expected rules error, got %v", err)
+		}
+	})
+
+	t.Run("Fetch error", func(t *testing.T) {
+		fetchArticlesFunc = func(ctx context.Context, sheetID, credentialsPath string) ([]schema.ArticleMeta, error) {
+			return nil, fmt.Errorf("API error")
+		}
+		var out bytes.Buffer
+		err := runClassify(context.Background(), rulesPath, &out)
+		if err == nil || !contains(err.Error(), "failed to fetch articles") {
+			t.Errorf("Expected fetch error, got %v", err)
+		}
+	})
+}
+
+// TestRunFetchQualityReport tests that the data-quality report is saved and strict mode fails the run
+func TestRunFetchQualityReport(t *testing.T) {
+	report := &schema.QualityReport{
+		SnapshotDate: "2025-12-21",
+		RowsScanned:  10,
+		RowsRejected: 1,
+		ByKind:       map[string]int{"invalid_date": 1},
+		Issues:       []schema.QualityIssue{{Row: 4, Kind: "invalid_date", Detail: "invalid date format: 12/03", Rejected: true}},
+	}
+	findingsOnly := &schema.QualityReport{
+		SnapshotDate: "2025-12-21",
+		RowsScanned:  10,
+		ByKind:       map[string]int{"duplicate_link": 2},
+		Findings:     []schema.QualityIssue{{Row: 5, Kind: "duplicate_link"}, {Row: 7, Kind: "duplicate_link"}},
+	}
+
+	tests := []struct {
+		name             string
+		strict           bool
+		dryRun           bool
+		report           *schema.QualityReport
+		expectError      bool
+		expectSnapshot   bool
+		expectReportFile bool
+	}{
+		{
+			name:             "Issues reported without strict",
+			strict:           false,
+			report:           report,
+			expectError:      false,
+			expectSnapshot:   true,
+			expectReportFile: true,
+		},
+		{
+			name:             "Strict fails on issues",
+			strict:           true,
+			report:           report,
+			expectError:      true,
+			expectSnapshot:   false,
+			expectReportFile: true,
+		},
+		{
+			name:             "Strict ignores informational findings",
+			strict:           true,
+			report:           findingsOnly,
+			expectError:      false,
+			expectSnapshot:   true,
+			expectReportFile: true,
+		},
+		{
+			name:             "Strict passes clean data",
+			strict:           true,
+			report:           &schema.QualityReport{RowsScanned: 10, ByKind: map[string]int{}},
+			expectError:      false,
+			expectSnapshot:   true,
+			expectReportFile: true,
+		},
+		{
+			name:             "Dry run writes nothing",
+			dryRun:           true,
+			report:           report,
+			expectError:      false,
+			expectSnapshot:   false,
+			expectReportFile: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			if err := os.Chdir(tmpDir); err != nil {
+				t.Fatalf("failed to change to temp directory: %v", err)
+			}
+			defer os.Chdir(originalDir)
+
+			originalSheetID := os.Getenv("SHEET_ID")
+			defer os.Setenv("SHEET_ID", originalSheetID)
+			os.Setenv("SHEET_ID", "test-sheet")
+
+			mockMetrics := createMockMetrics(time.Date(2025, 12, 21, 10, 30, 0, 0, time.UTC))
+			mockMetrics.DataQuality = tt.report
+
+			var out bytes.Buffer
+			_, _, err := runFetch(context.Background(), &MockMetricsFetcher{mockMetrics: mockMetrics}, fetchOptions{Out: &out, Store: metrics.NewFileStore("metrics"), Strict: tt.strict, DryRun: tt.dryRun})
+			if (err != nil) != tt.expectError {
+				t.Errorf("runFetch() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !contains(out.String(), "Data quality: ") {
+				t.Errorf("expected the quality summary on the command output, got:\n%s", out.String())
+			}
+			if tt.expectError && exitCode(err) != exitInvalid {
+				t.Errorf("expected exit code %d for failed data-quality checks, got %d", exitInvalid, exitCode(err))
+			}
+
+			if _, err := os.Stat(filepath.Join("metrics", "2025-12-21.json")); (err == nil) != tt.expectSnapshot {
+				t.Errorf("snapshot exists = %v, want %v", err == nil, tt.expectSnapshot)
+			}
+			if _, err := os.Stat(filepath.Join("metrics", "2025-12-21.quality.json")); (err == nil) != tt.expectReportFile {
+				t.Errorf("quality report exists = %v, want %v", err == nil, tt.expectReportFile)
+			}
+		})
+	}
+}
+
+// TestRunFetchExistingSnapshot tests that a second fetch on the same day needs -overwrite or -intraday
+func TestRunFetchExistingSnapshot(t *testing.T) {
+	tests := []struct {
+		name             string
+		naming           snapshotNaming
+		expectError      bool
+		expectedFilename string
+		expectedReport   string
+	}{
+		{name: "Refused without overwrite", expectError: true},
+		{name: "Replaced with overwrite", naming: snapshotNaming{Overwrite: true}, expectedFilename: "2025-12-21.json", expectedReport: "2025-12-21.quality.json"},
+		{name: "Kept with intraday", naming: snapshotNaming{Intraday: true}, expectedFilename: "2025-12-21T103000.json", expectedReport: "2025-12-21T103000.quality.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			if err := os.Chdir(tmpDir); err != nil {
+				t.Fatalf("failed to change to temp directory: %v", err)
+			}
+			defer os.Chdir(originalDir)
+
+			originalSheetID := os.Getenv("SHEET_ID")
+			defer os.Setenv("SHEET_ID", originalSheetID)
+			os.Setenv("SHEET_ID", "test-sheet")
+
+			if err := writeExistingSnapshot(tmpDir); err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+
+			mockMetrics := createMockMetrics(time.Date(2025, 12, 21, 10, 30, 0, 0, time.UTC))
+			mockMetrics.DataQuality = &schema.QualityReport{RowsScanned: 10, ByKind: map[string]int{}}
+			filename, _, err := runFetch(context.Background(), &MockMetricsFetcher{mockMetrics: mockMetrics}, fetchOptions{Store: metrics.NewFileStore("metrics"), Naming: tt.naming})
+
+			if tt.expectError {
+				if err == nil || !contains(err.Error(), "-overwrite") {
+					t.Errorf("expected an error pointing at -overwrite, got %v", err)
+				}
+				if _, err := os.Stat(filepath.Join("metrics", "2025-12-21.quality.json")); err == nil {
+					t.Error("expected no quality report to be written for a refused snapshot")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if filename != tt.expectedFilename {
+				t.Errorf("expected filename %q, got %q", tt.expectedFilename, filename)
+			}
+			if _, err := os.Stat(filepath.Join("metrics", tt.expectedReport)); err != nil {
+				t.Errorf("expected quality report %s: %v", tt.expectedReport, err)
+			}
+		})
+	}
+}
+
+// TestRunFetchBacklogForecast tests that the forecast is built from earlier snapshots
+func TestRunFetchBacklogForecast(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	originalSheetID := os.Getenv("SHEET_ID")
+	defer os.Setenv("SHEET_ID", originalSheetID)
+	os.Setenv("SHEET_ID", "test-sheet")
+
+	previous := createMockMetrics(time.Date(2025, 12, 14, 10, 30, 0, 0, time.UTC))
+	previous.UnreadCount = 10
+	if _, err := saveMetrics(context.Background(), metrics.NewFileStore("metrics"), previous, snapshotNaming{}); err != nil {
+		t.Fatalf("failed to seed previous snapshot: %v", err)
+	}
+
+	mockMetrics := createMockMetrics(time.Date(2025, 12, 21, 10, 30, 0, 0, time.UTC))
+	targetDate := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	_, metricsData, err := runFetch(context.Background(), &MockMetricsFetcher{mockMetrics: mockMetrics}, fetchOptions{Store: metrics.NewFileStore("metrics"), TargetDate: targetDate})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	forecast := metricsData.BacklogForecast
+	if forecast == nil {
+		t.Fatal("expected a backlog forecast")
+	}
+	if forecast.TargetDate != "2026-06-30" || forecast.AvgWeeklyNetChange != -4 {
+		t.Errorf("unexpected forecast: %+v", forecast)
+	}
+	if forecast.ProjectedZeroDate != "2025-12-31" {
+		t.Errorf("expected zero date 2025-12-31, got %s", forecast.ProjectedZeroDate)
+	}
+}
+
+// TestPrintQualitySummary tests the CLI summary of the data-quality report
+func TestPrintQualitySummary(t *testing.T) {
+	report := &schema.QualityReport{
+		RowsScanned:  120,
+		RowsRejected: 2,
+		ByKind:       map[string]int{"invalid_date": 2, "duplicate_link": 3},
+		Issues:       make([]schema.QualityIssue, 2),
+		Findings:     make([]schema.QualityIssue, 3),
+		Duplicates:   make([]schema.DuplicateMerge, 4),
+	}
+
+	var buf bytes.Buffer
+	printQualitySummary(&buf, report)
+	output := buf.String()
+
+	for _, expected := range []string{"120 rows scanned, 2 rejected, 3 findings", "4 duplicate rows merged", "duplicate_link", "invalid_date"} {
+		if !contains(output, expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+// TestDefaultMetricsFetcherAsOf tests that the as-of date reaches the sheet fetch
+func TestDefaultMetricsFetcherAsOf(t *testing.T) {
+	originalFetchMetricsFunc := fetchMetricsFunc
+	defer func() { fetchMetricsFunc = originalFetchMetricsFunc }()
+
+	var received time.Time
+	fetchMetricsFunc = func(ctx context.Context, sheetID, credentialsPath string, asOf time.Time) (schema.Metrics, error) {
+		received = asOf
+		return createMockMetrics(asOf), nil
+	}
+
+	asOf := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	fetcher := &DefaultMetricsFetcher{AsOf: asOf}
+	metricsData, err := fetcher.FetchMetrics(context.Background(), "sheet", "creds.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !received.Equal(asOf) {
+		t.Errorf("expected as-of %v to be passed through, got %v", asOf, received)
+	}
+	if filename := snapshotFilename(metricsData, snapshotNaming{}); filename != "2025-06-30.json" {
+		t.Errorf("expected snapshot named after the as-of date, got %s", filename)
+	}
+}
+
+// TestRunFetchCommandAsOfStrict tests that -strict still fails an as-of fetch on rejected rows
+func TestRunFetchCommandAsOfStrict(t *testing.T) {
+	originalFetchMetricsFunc := fetchMetricsFunc
+	defer func() { fetchMetricsFunc = originalFetchMetricsFunc }()
+
+	fetchMetricsFunc = func(ctx context.Context, sheetID, credentialsPath string, asOf time.Time) (schema.Metrics, error) {
+		metricsData := createMockMetrics(asOf)
+		metricsData.DataQuality = &schema.QualityReport{
+			SnapshotDate: asOf.Format("2006-01-02"),
+			RowsScanned:  3,
+			RowsRejected: 1,
+			ByKind:       map[string]int{"incomplete_row": 1},
+			Issues:       []schema.QualityIssue{{Row: 3, Kind: "incomplete_row", Rejected: true}},
+		}
+		return metricsData, nil
+	}
+
+	originalSheetID := os.Getenv("SHEET_ID")
+	defer os.Setenv("SHEET_ID", originalSheetID)
+	os.Setenv("SHEET_ID", "test-sheet")
+
+	metricsDir := t.TempDir()
+	var out bytes.Buffer
+	err := runFetchCommand(context.Background(), []string{"-metrics-dir", metricsDir, "-date", "2025-03-15", "-strict", "-goals", filepath.Join(metricsDir, "missing.yml")}, &out)
+	if exitCode(err) != exitInvalid {
+		t.Fatalf("expected exit code %d, got %d (%v)", exitInvalid, exitCode(err), err)
+	}
+
+	if _, err := os.Stat(filepath.Join(metricsDir, "2025-03-15.json")); err == nil {
+		t.Error("expected no snapshot to be saved")
+	}
+	if _, err := os.Stat(filepath.Join(metricsDir, "2025-03-15.quality.json")); err != nil {
+		t.Errorf("expected the quality report to be saved: %v", err)
+	}
+}
+
+// TestRunBackfill tests the backfill subcommand
+func TestRunBackfill(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectError    bool
+		expectedDates  []string
+		expectedFiles  []string
+		errorSubstring string
+	}{
+		{
+			name:          "Writes only missing snapshots",
+			args:          []string{"-from", "2024-01-05", "-to", "2024-01-19"},
+			expectedDates: []string{"2024-01-05", "2024-01-19"},
+			expectedFiles: []string{"2024-01-05.json", "2024-01-12.json", "2024-01-19.json"},
+		},
+		{
+			name:          "Skips dates with an intra-day snapshot",
+			args:          []string{"-from", "2024-01-19", "-to", "2024-02-02"},
+			expectedDates: []string{"2024-01-19", "2024-02-02"},
+			expectedFiles: []string{"2024-01-19.json", "2024-01-26T090000.json", "2024-02-02.json"},
+		},
+		{
+			name:          "Follows the weekday of the existing snapshots",
+			args:          []string{"-from", "2024-01-01", "-to", "2024-01-10"},
+			expectedDates: []string{"2024-01-05"},
+			expectedFiles: []string{"2024-01-05.json"},
+		},
+		{
+			name:           "Missing dates",
+			args:           []string{"-from", "2024-01-05"},
+			expectError:    true,
+			errorSubstring: "-from and -to",
+		},
+		{
+			name:           "Inverted range",
+			args:           []string{"-from", "2024-02-01", "-to", "2024-01-01"},
+			expectError:    true,
+			errorSubstring: "before",
+		},
+		{
+			name:           "Invalid date",
+			args:           []string{"-from", "01/05/2024", "-to", "2024-01-19"},
+			expectError:    true,
+			errorSubstring: "YYYY-MM-DD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			if err := os.Chdir(tmpDir); err != nil {
+				t.Fatalf("failed to change to temp directory: %v", err)
+			}
+			defer os.Chdir(originalDir)
+
+			// An existing real snapshot must be left alone
+			os.MkdirAll("metrics", 0755)
+			os.WriteFile(filepath.Join("metrics", "2024-01-12.json"), []byte(`{"total_articles": 99}`), 0644)
+			os.WriteFile(filepath.Join("metrics", "2024-01-26T090000.json"), []byte(`{"total_articles": 99}`), 0644)
+
+			originalSheetID := os.Getenv("SHEET_ID")
+			originalBackfillFunc := backfillSnapshotsFunc
+			defer func() {
+				os.Setenv("SHEET_ID", originalSheetID)
+				backfillSnapshotsFunc = originalBackfillFunc
+			}()
+			os.Setenv("SHEET_ID", "test-sheet")
+
+			var requested []string
+			backfillSnapshotsFunc = func(ctx context.Context, sheetID, credentialsPath string, dates []time.Time) ([]schema.Metrics, error) {
+				var snapshots []schema.Metrics
+				for _, date := range dates {
+					requested = append(requested, date.Format("2006-01-02"))
+					snapshot := createMockMetrics(date)
+					snapshot.Reconstructed = &schema.Reconstruction{ReadDatesAssumed: 36}
+					snapshots = append(snapshots, snapshot)
+				}
+				return snapshots, nil
+			}
+
+			err := runBackfill(context.Background(), tt.args, &bytes.Buffer{})
+			if tt.expectError {
+				if err == nil || !contains(err.Error(), tt.errorSubstring) {
+					t.Errorf("expected error containing %q, got %v", tt.errorSubstring, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fmt.Sprint(requested) != fmt.Sprint(tt.expectedDates) {
+				t.Errorf("expected dates %v to be reconstructed, got %v", tt.expectedDates, requested)
+			}
+			for _, name := range tt.expectedFiles {
+				if _, err := os.Stat(filepath.Join("metrics", name)); err != nil {
+					t.Errorf("expected %s to exist: %v", name, err)
+				}
+			}
+
+			content, _ := os.ReadFile(filepath.Join("metrics", "2024-01-12.json"))
+			if string(content) != `{"total_articles": 99}` {
+				t.Errorf("existing snapshot was overwritten: %s", content)
+			}
+			content, _ = os.ReadFile(filepath.Join("metrics", tt.expectedDates[0]+".json"))
+			if !contains(string(content), `"reconstructed"`) {
+				t.Errorf("expected backfilled snapshot to be marked reconstructed: %s", content)
+			}
+			// The later snapshots are built with the earlier ones, reconstructed or real, as history
+			if last := tt.expectedDates[len(tt.expectedDates)-1]; len(tt.expectedDates) > 1 {
+				content, _ = os.ReadFile(filepath.Join("metrics", last+".json"))
+				if !contains(string(content), `"backlog_forecast"`) || !contains(string(content), `"cadence"`) {
+					t.Errorf("expected %s to carry history analytics: %s", last, content)
+				}
+			}
+		})
+	}
+}
+
+// ============================================================================
+// runReport: Writes monthly and annual digests
+// ============================================================================
+
+func TestRunReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedFiles  []string
+		expectedText   string
+		errorSubstring string
+	}{
+		{
+			name:          "Defaults to the month of the latest snapshot",
+			args:          []string{},
+			expectedFiles: []string{"2025-12.md", "2025-12.html"},
+			expectedText:  "# Reading Digest: December 2025",
+		},
+		{
+			name:          "Annual digest into a custom directory",
+			args:          []string{"-period", "annual", "-for", "2025", "-out", "digests"},
+			expectedFiles: []string{"2025.md", "2025.html"},
+			expectedText:  "| Read | 36 | +6 |",
+		},
+		{
+			name:           "Period without snapshots",
+			args:           []string{"-for", "2024-03"},
+			errorSubstring: "no snapshots in March 2024",
+		},
+		{
+			name:           "Unknown period",
+			args:           []string{"-period", "weekly"},
+			errorSubstring: "unknown digest period",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			if err := os.Chdir(tmpDir); err != nil {
+				t.Fatalf("failed to change to temp directory: %v", err)
+			}
+			defer os.Chdir(originalDir)
+
+			os.MkdirAll("metrics", 0755)
+			earlier := createMockMetrics(time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC))
+			earlier.ReadCount = 30
+			for _, snapshot := range []schema.Metrics{earlier, createMockMetrics(time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC))} {
+				content, _ := json.Marshal(snapshot)
+				os.WriteFile(filepath.Join("metrics", snapshot.LastUpdated.Format("2006-01-02")+".json"), content, 0644)
+			}
+
+			err := runReport(context.Background(), tt.args, &bytes.Buffer{})
+			if tt.errorSubstring != "" {
+				if err == nil || !contains(err.Error(), tt.errorSubstring) {
+					t.Errorf("expected error containing %q, got %v", tt.errorSubstring, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			outDir := "reports"
+			if len(tt.args) > 0 {
+				outDir = tt.args[len(tt.args)-1]
+			}
+			for _, name := range tt.expectedFiles {
+				if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+					t.Errorf("expected %s to exist: %v", name, err)
+				}
+			}
+			content, _ := os.ReadFile(filepath.Join(outDir, tt.expectedFiles[0]))
+			if !contains(string(content), tt.expectedText) {
+				t.Errorf("expected digest to contain %q, got:\n%s", tt.expectedText, content)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// runExport: Flattens snapshots into CSV tables
+// ============================================================================
+
+func TestRunExport(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	if err := runExport(context.Background(), nil, &bytes.Buffer{}); err == nil || !contains(err.Error(), "unable to read metrics directory") {
+		t.Errorf("expected an error without a metrics directory, got %v", err)
+	}
+
+	os.MkdirAll("metrics", 0755)
+	if err := runExport(context.Background(), nil, &bytes.Buffer{}); err == nil || !contains(err.Error(), "no snapshots") {
+		t.Errorf("expected an error without snapshots, got %v", err)
+	}
+
+	for _, day := range []int{5, 12} {
+		snapshot := createMockMetrics(time.Date(2025, 12, day, 0, 0, 0, 0, time.UTC))
+		content, _ := json.Marshal(snapshot)
+		os.WriteFile(filepath.Join("metrics", snapshot.LastUpdated.Format("2006-01-02")+".json"), content, 0644)
+	}
+
+	if err := runExport(context.Background(), []string{"-out", "csv"}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	summary, err := os.ReadFile(filepath.Join("csv", "summary.csv"))
+	if err != nil {
+		t.Fatalf("summary.csv was not written: %v", err)
+	}
+	if !contains(string(summary), "2025-12-05,42,36,6") || !contains(string(summary), "2025-12-12,42,36,6") {
+		t.Errorf("expected one summary row per snapshot, got:\n%s", summary)
+	}
+
+	breakdowns, err := os.ReadFile(filepath.Join("csv", "breakdowns.csv"))
+	if err != nil {
+		t.Fatalf("breakdowns.csv was not written: %v", err)
+	}
+	if !contains(string(breakdowns), "2025-12-12,source,GitHub,8,2") {
+		t.Errorf("expected per-source rows, got:\n%s", breakdowns)
+	}
+}
+
+func TestRunWarehouse(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	os.MkdirAll("metrics", 0755)
+	for _, day := range []int{5, 12} {
+		snapshot := createMockMetrics(time.Date(2025, 12, day, 0, 0, 0, 0, time.UTC))
+		content, _ := json.Marshal(snapshot)
+		os.WriteFile(filepath.Join("metrics", snapshot.LastUpdated.Format("2006-01-02")+".json"), content, 0644)
+	}
+
+	originalFetch := fetchArticlesFunc
+	defer func() { fetchArticlesFunc = originalFetch }()
+	fetchArticlesFunc = func(ctx context.Context, sheetID, credentialsPath string) ([]schema.ArticleMeta, error) {
+		return []schema.ArticleMeta{{Date: "2025-11-01", Title: "Go tips", Link: "https://a.example/1", Category: "GitHub", Read: true}}, nil
+	}
+	t.Setenv("SHEET_ID", "sheet")
+
+	dbPath := filepath.Join("db", "reading.db")
+	if err := runWarehouse(context.Background(), []string{"-db", dbPath, "-articles"}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A second run finds nothing to reload
+	if err := runWarehouse(context.Background(), []string{"-db", dbPath}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("unexpected error on refresh: %v", err)
+	}
+
+	w, err := warehouse.Open(dbPath)
+	if err != nil {
+		t.Fatalf("failed to reopen warehouse: %v", err)
+	}
+	defer w.Close()
+
+	var snapshots, articles, githubRead int
+	w.DB().QueryRow("SELECT COUNT(*) FROM snapshots").Scan(&snapshots)
+	w.DB().QueryRow("SELECT COUNT(*) FROM articles").Scan(&articles)
+	w.DB().QueryRow("SELECT read FROM source_history WHERE snapshot_date = '2025-12-12' AND source = 'GitHub'").Scan(&githubRead)
+	if snapshots != 2 || articles != 1 || githubRead != 8 {
+		t.Errorf("unexpected warehouse contents: %d snapshots, %d articles, GitHub read %d", snapshots, articles, githubRead)
+	}
+}
+
+func TestRunQuery(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	os.MkdirAll("metrics", 0755)
+	for _, day := range []int{5, 12} {
+		snapshot := createMockMetrics(time.Date(2025, 12, day, 0, 0, 0, 0, time.UTC))
+		content, _ := json.Marshal(snapshot)
+		os.WriteFile(filepath.Join("metrics", snapshot.LastUpdated.Format("2006-01-02")+".json"), content, 0644)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		errMsg   string
+	}{
+		{name: "unread for a source", args: []string{"unread", "--source", "GitHub", "--since", "2025-12-10"}, expected: "2025-12-12     GitHub  2"},
+		{name: "read rate as JSON", args: []string{"readrate", "--weeks", "1", "-format", "json"}, expected: `"read_rate": 85.71`},
+		{name: "missing query name", args: nil, errMsg: "query requires one of"},
+		{name: "unknown query", args: []string{"backlog"}, errMsg: "unknown query"},
+		{name: "invalid since", args: []string{"unread", "--since", "yesterday"}, errMsg: "invalid as-of date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runQuery(context.Background(), tt.args, &out)
+			if tt.errMsg != "" {
+				if err == nil || !contains(err.Error(), tt.errMsg) {
+					t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !contains(out.String(), tt.expected) {
+				t.Errorf("expected output to contain %q, got:\n%s", tt.expected, out.String())
+			}
+		})
+	}
+}
+
+// writeMockSnapshots writes createMockMetrics snapshots for the given December 2025 days
+func writeMockSnapshots(t *testing.T, dir string, days ...int) {
+	t.Helper()
+	os.MkdirAll(dir, 0755)
+	for _, day := range days {
+		snapshot := createMockMetrics(time.Date(2025, 12, day, 0, 0, 0, 0, time.UTC))
+		content, _ := json.Marshal(snapshot)
+		if err := os.WriteFile(filepath.Join(dir, snapshot.LastUpdated.Format("2006-01-02")+".json"), content, 0644); err != nil {
+			t.Fatalf("failed to write snapshot: %v", err)
+		}
+	}
+}
+
+func TestRunSummarize(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	writeMockSnapshots(t, "snapshots", 5, 12)
+	// Non-snapshot files sort after the snapshots and must never be picked
+	os.WriteFile(filepath.Join("snapshots", ".gitkeep"), nil, 0644)
+	os.WriteFile(filepath.Join("snapshots", "2025-12-12.quality.json"), []byte("{}"), 0644)
+
+	originalGenerate := generateDeltaAnalysisFunc
+	defer func() { generateDeltaAnalysisFunc = originalGenerate }()
+
+	var analyzed string
+	var generateErr error
+	generateDeltaAnalysisFunc = func(ctx context.Context, store metrics.SnapshotStore, filename string, m *schema.Metrics) error {
+		analyzed = store.Location(filename)
+		return generateErr
+	}
+
+	tests := []struct {
+		name           string
+		args           []string
+		generateErr    error
+		expectAnalyzed string
+		expectCode     int
+		errMsg         string
+	}{
+		{name: "Latest snapshot", args: []string{"-metrics-dir", "snapshots"}, expectAnalyzed: filepath.Join("snapshots", "2025-12-12.json")},
+		{name: "Snapshot by date", args: []string{"-metrics-dir", "snapshots", "-date", "2025-12-05"}, expectAnalyzed: filepath.Join("snapshots", "2025-12-05.json")},
+		{name: "Dry run", args: []string{"-metrics-dir", "snapshots", "-dry-run"}},
+		{name: "Missing date", args: []string{"-metrics-dir", "snapshots", "-date", "2025-12-06"}, expectCode: exitFailure, errMsg: "no snapshot dated 2025-12-06"},
+		{name: "Empty directory", args: nil, expectCode: exitFailure, errMsg: "unable to read metrics directory"},
+		{name: "Save failure is reported", args: []string{"-metrics-dir", "snapshots"}, generateErr: fmt.Errorf("disk full"), expectAnalyzed: filepath.Join("snapshots", "2025-12-12.json"), expectCode: exitFailure, errMsg: "disk full"},
+		{name: "Generation failure", args: []string{"-metrics-dir", "snapshots"}, generateErr: fmt.Errorf("%w: quota exceeded", metrics.ErrDeltaAnalysisUnavailable), expectAnalyzed: filepath.Join("snapshots", "2025-12-12.json"), expectCode: exitAI, errMsg: "quota exceeded"},
+		{name: "Missing API key", args: []string{"-metrics-dir", "snapshots"}, generateErr: fmt.Errorf("%w: %w", metrics.ErrDeltaAnalysisUnavailable, ai.ErrMissingAPIKey), expectAnalyzed: filepath.Join("snapshots", "2025-12-12.json"), expectCode: exitAI, errMsg: "GEMINI_API_KEY"},
+		{name: "Missing API key skipped", args: []string{"-metrics-dir", "snapshots", "-skip-without-key"}, generateErr: fmt.Errorf("%w: %w", metrics.ErrDeltaAnalysisUnavailable, ai.ErrMissingAPIKey), expectAnalyzed: filepath.Join("snapshots", "2025-12-12.json")},
+		{name: "Skip flag keeps other failures", args: []string{"-metrics-dir", "snapshots", "-skip-without-key"}, generateErr: fmt.Errorf("%w: quota exceeded", metrics.ErrDeltaAnalysisUnavailable), expectAnalyzed: filepath.Join("snapshots", "2025-12-12.json"), expectCode: exitAI, errMsg: "quota exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzed, generateErr = "", tt.generateErr
+			err := runSummarize(context.Background(), tt.args, &bytes.Buffer{})
+			if exitCode(err) != tt.expectCode || (tt.errMsg != "" && (err == nil || !contains(err.Error(), tt.errMsg))) {
+				t.Errorf("unexpected error %v (exit code %d)", err, exitCode(err))
+			}
+			if analyzed != tt.expectAnalyzed {
+				t.Errorf("analyzed %q, expected %q", analyzed, tt.expectAnalyzed)
+			}
+		})
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	writeMockSnapshots(t, "metrics", 5, 12)
+	// Two more GitHub articles were read by the later snapshot
+	later := createMockMetrics(time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC))
+	later.ReadCount, later.UnreadCount = 38, 4
+	later.BySourceReadStatus["GitHub"] = [2]int{10, 0}
+	content, _ := json.Marshal(later)
+	os.WriteFile(filepath.Join("metrics", "2025-12-19.json"), content, 0644)
+
+	var out bytes.Buffer
+	if err := runDiff(context.Background(), nil, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{"2025-12-12 → 2025-12-19", "read            36     38     2", "GitHub unread   2      0      -2"} {
+		if !contains(out.String(), expected) {
+			t.Errorf("expected diff to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+	if contains(out.String(), "Substack read") {
+		t.Errorf("expected unchanged sources to be left out, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runDiff(context.Background(), []string{"-date", "2025-12-12", "-against", "2025-12-05", "-format", "json"}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(out.String(), `"change": 0`) || contains(out.String(), "→") {
+		t.Errorf("expected a JSON diff without changes, got:\n%s", out.String())
+	}
+
+	if err := runDiff(context.Background(), []string{"-date", "2025-12-05"}, &out); err == nil || !contains(err.Error(), "no snapshot before") {
+		t.Errorf("expected an error without an earlier snapshot, got %v", err)
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	writeMockSnapshots(t, "metrics", 5, 12)
+
+	var out bytes.Buffer
+	if err := runValidate(context.Background(), nil, &out); err != nil {
+		t.Fatalf("expected consistent snapshots to pass, got %v:\n%s", err, out.String())
+	}
+	if !contains(out.String(), "Validated 2 snapshots: 0 problems") {
+		t.Errorf("unexpected summary:\n%s", out.String())
+	}
+
+	broken := createMockMetrics(time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC))
+	broken.ReadCount = 40
+	content, _ := json.Marshal(broken)
+	os.WriteFile(filepath.Join("metrics", "2025-12-19.json"), content, 0644)
+	os.WriteFile(filepath.Join("metrics", "2025-12-26.json"), []byte(`{"total_articles":`), 0644)
+
+	out.Reset()
+	err := runValidate(context.Background(), nil, &out)
+	if exitCode(err) != exitInvalid {
+		t.Errorf("expected exit code %d, got %d (%v)", exitInvalid, exitCode(err), err)
+	}
+	for _, expected := range []string{"2025-12-19.json: read 40 + unread 6 does not equal total 42", "2025-12-26.json: unable to parse snapshot", "Validated 4 snapshots"} {
+		if !contains(out.String(), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := runValidate(context.Background(), []string{"-date", "2025-12-12"}, &out); err != nil || !contains(out.String(), "Validated 1 snapshots") {
+		t.Errorf("expected only the dated snapshot validated, got %v:\n%s", err, out.String())
+	}
+}
+
+func TestRunCompact(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	writeMockSnapshots(t, "metrics", 5, 12, 19)
+
+	var dryRunOut bytes.Buffer
+	if err := runCompact(context.Background(), []string{"-dry-run"}, &dryRunOut); err != nil {
+		t.Fatalf("unexpected error on dry run: %v", err)
+	}
+	if !contains(dryRunOut.String(), "Dry run: would compact 3 snapshots") {
+		t.Errorf("expected the dry run on the command output, got:\n%s", dryRunOut.String())
+	}
+	if _, err := os.Stat(filepath.Join("metrics", metrics.ArchiveFilename)); !os.IsNotExist(err) {
+		t.Error("expected no archive after a dry run")
+	}
+
+	if err := runCompact(context.Background(), nil, &bytes.Buffer{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names, _ := metrics.ListSnapshotFiles("metrics"); len(names) != 0 {
+		t.Errorf("expected the loose snapshots removed, got %v", names)
+	}
+
+	// Every command keeps reading the archived snapshots
+	var out bytes.Buffer
+	if err := runValidate(context.Background(), nil, &out); err != nil || !contains(out.String(), "Validated 3 snapshots: 0 problems") {
+		t.Errorf("expected the archived snapshots to validate, got %v:\n%s", err, out.String())
+	}
+	out.Reset()
+	if err := runDiff(context.Background(), nil, &out); err != nil || !contains(out.String(), "2025-12-12 → 2025-12-19") {
+		t.Errorf("expected a diff of the archived snapshots, got %v:\n%s", err, out.String())
+	}
+
+	// A snapshot that cannot be parsed stops the compaction before anything is removed
+	os.WriteFile(filepath.Join("metrics", "2025-12-26.json"), []byte(`{"total_articles":`), 0644)
+	if err := runCompact(context.Background(), nil, &bytes.Buffer{}); err == nil || !contains(err.Error(), "2025-12-26.json") {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("metrics", "2025-12-26.json")); err != nil {
+		t.Errorf("expected the broken snapshot kept: %v", err)
+	}
+}
@@ -1,34 +1,96 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"net/http"
 	"path/filepath"
 
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+	metrics "github.com/victoriacheng15/personal-reading-analytics/internal/metrics"
 	web "github.com/victoriacheng15/personal-reading-analytics/internal/web"
 )
 
 func main() {
-	// 1. Get all available metrics dates
-	dates, err := web.GetMetricsDates()
+	asOfFlag := flag.String("date", "", "Build the site as it was on this date (YYYY-MM-DD), ignoring later snapshots")
+	// -as-of is the old name of -date, kept so existing scripts keep working
+	flag.StringVar(asOfFlag, "as-of", "", "Deprecated: use -date")
+	metricsDir := flag.String("metrics-dir", metrics.MetricsDirFromEnv(), "Directory or s3://bucket/prefix the snapshots are read from (default from METRICS_DIR)")
+	serveAddr := flag.String("serve", "", "After building, serve dist/ and live OpenMetrics at /metrics on this address (e.g. :8080)")
+	flag.Parse()
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "as-of" {
+			log.Println("⚠️ Warning: -as-of is deprecated, use -date")
+		}
+	})
+
+	asOf, err := metrics.ParseAsOf(*asOfFlag)
 	if err != nil {
-		log.Fatalf("Failed to discover metrics: %v", err)
+		log.Fatalf("%v", err)
 	}
 
-	// 2. Initialize Analytics Service
-	service := web.NewAnalyticsService("dist")
+	ctx := context.Background()
+	store, err := metrics.OpenSnapshotStore(*metricsDir)
+	if err != nil {
+		log.Fatalf("Failed to open snapshot store: %v", err)
+	}
+
+	// 1. Get all available metrics dates up to the as-of date
+	dates, err := web.GetMetricsDates(ctx, store)
+	if err != nil {
+		log.Fatalf("Failed to discover metrics: %v", err)
+	}
+	dates = web.FilterDatesAsOf(dates, asOf)
+	if len(dates) == 0 && asOf.IsZero() {
+		log.Fatalf("No metrics snapshots found")
+	}
+	if len(dates) == 0 {
+		log.Fatalf("No metrics snapshots on or before %s", *asOfFlag)
+	}
 
-	log.Printf("Generating reports for %d dates...\n", len(dates))
+	// 2. Load source aliases so historic snapshots line up under canonical names
+	aliases, err := metrics.LoadSourceAliases(metrics.DefaultSourceAliasesPath)
+	if err != nil {
+		log.Printf("⚠️ Warning: Source aliases disabled: %v\n", err)
+	}
+	sourceMap := metrics.BuildSourceMap(nil, aliases)
+	retiredSources := aliases.RetiredSources()
 
-	// 3. Multi-pass generation
-	for i, date := range dates {
-		metrics, err := web.LoadMetricsByDate(date)
+	// 3. Load every snapshot up front; source pages chart read rates across all of them
+	type datedSnapshot struct {
+		date     string
+		snapshot schema.Metrics
+	}
+	var snapshots []datedSnapshot
+	var allSnapshots []schema.Metrics
+	for _, date := range dates {
+		snapshot, err := web.LoadMetricsByDate(ctx, store, date)
 		if err != nil {
 			log.Printf("⚠️ Warning: Skipping %s: %v\n", date, err)
 			continue
 		}
+		metrics.CanonicalizeSources(&snapshot, sourceMap)
+		metrics.MarkRetiredSources(&snapshot, retiredSources)
+		snapshots = append(snapshots, datedSnapshot{date: date, snapshot: snapshot})
+		allSnapshots = append(allSnapshots, snapshot)
+	}
+	sourceHistory := web.BuildSourceReadRateHistory(allSnapshots)
+
+	// 4. Initialize Analytics Service
+	service := web.NewAnalyticsService("dist")
+
+	log.Printf("Generating reports for %d dates...\n", len(snapshots))
+
+	// 5. Multi-pass generation
+	var feedEntries []web.FeedEntry
+	for i, entry := range snapshots {
+		date, snapshot := entry.date, entry.snapshot
+		feedEntries = append(feedEntries, web.PrepareFeedEntry(date, snapshot))
 
 		// Historical: ONLY analytics.html in dist/history/YYYY-MM-DD
-		err = service.GenerateAnalyticsOnly(metrics, web.GenConfig{
+		err = service.GenerateAnalyticsOnly(snapshot, web.GenConfig{
 			OutputDir:    filepath.Join("dist", "history", date),
 			BaseURL:      "../../",
 			IsHistorical: true,
@@ -39,14 +101,16 @@ func main() {
 			log.Printf("⚠️ Warning: Failed historical generation for %s: %v\n", date, err)
 		}
 
-		// Latest (root): ALL pages in dist/
+		// Latest (root): ALL pages in dist/, with "today" pinned to the as-of date when given
 		if i == 0 {
-			err = service.GenerateFullSite(metrics, web.GenConfig{
-				OutputDir:    "dist",
-				BaseURL:      "./",
-				IsHistorical: false,
-				HistoryDates: dates,
-				ReportDate:   date,
+			err = service.GenerateFullSite(snapshot, web.GenConfig{
+				OutputDir:     "dist",
+				BaseURL:       "./",
+				IsHistorical:  false,
+				HistoryDates:  dates,
+				ReportDate:    date,
+				AsOf:          asOf,
+				SourceHistory: sourceHistory,
 			})
 			if err != nil {
 				log.Fatalf("Failed to generate latest site: %v", err)
@@ -54,5 +118,43 @@ func main() {
 		}
 	}
 
+	// 6. Atom and JSON feeds announce each snapshot's report
+	if err := service.GenerateFeeds(feedEntries); err != nil {
+		log.Printf("⚠️ Warning: Skipping feeds: %v\n", err)
+	}
+
 	log.Println("✅ Successfully generated all historical and latest analytics")
+
+	if *serveAddr == "" {
+		return
+	}
+
+	// Serve mode: /metrics re-reads the newest snapshot on every scrape. Each scrape opens its own
+	// store, since a store keeps its decoded archive and would miss a compact run by another process.
+	latest := func() (schema.Metrics, error) {
+		store, err := metrics.OpenSnapshotStore(*metricsDir)
+		if err != nil {
+			return schema.Metrics{}, err
+		}
+		dates, err := web.GetMetricsDates(ctx, store)
+		if err != nil {
+			return schema.Metrics{}, err
+		}
+		dates = web.FilterDatesAsOf(dates, asOf)
+		if len(dates) == 0 {
+			return schema.Metrics{}, fmt.Errorf("no metrics snapshots available")
+		}
+		snapshot, err := web.LoadMetricsByDate(ctx, store, dates[0])
+		if err != nil {
+			return schema.Metrics{}, err
+		}
+		metrics.CanonicalizeSources(&snapshot, sourceMap)
+		metrics.MarkRetiredSources(&snapshot, retiredSources)
+		return snapshot, nil
+	}
+
+	log.Printf("Serving dist/ and /metrics on %s\n", *serveAddr)
+	if err := http.ListenAndServe(*serveAddr, web.NewSiteHandler("dist", latest)); err != nil {
+		log.Fatalf("Server stopped: %v", err)
+	}
 }
@@ -2,12 +2,16 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"google.golang.org/genai"
 )
 
+// ErrMissingAPIKey is returned by NewClient when GEMINI_API_KEY is not set
+var ErrMissingAPIKey = errors.New("GEMINI_API_KEY environment variable not set")
+
 type Client struct {
 	client *genai.Client
 	model  string
@@ -16,7 +20,7 @@ type Client struct {
 func NewClient(ctx context.Context) (*Client, error) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
+		return nil, ErrMissingAPIKey
 	}
 
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
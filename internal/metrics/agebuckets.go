@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// DefaultAgeBucketsPath is where the unread age bucket scheme is read from
+const DefaultAgeBucketsPath = "config/age_buckets.yml"
+
+// Day lengths used to convert age units; months and years are averages
+const (
+	daysPerWeek  = 7.0
+	daysPerMonth = 30.44
+	daysPerYear  = 365.25
+)
+
+// AgeBucketConfig is the top-level structure of the age buckets YAML file
+type AgeBucketConfig struct {
+	Buckets []schema.AgeBucket `yaml:"buckets"`
+}
+
+// DefaultAgeBuckets returns the original five-bucket scheme, used when no config is present
+// and for snapshots written before buckets were declared.
+func DefaultAgeBuckets() []schema.AgeBucket {
+	return []schema.AgeBucket{
+		{Key: "less_than_1_month", Label: "Less than 1 month"},
+		{Key: "1_to_3_months", Label: "1-3 months", MinAge: "1m"},
+		{Key: "3_to_6_months", Label: "3-6 months", MinAge: "3m"},
+		{Key: "6_to_12_months", Label: "6-12 months", MinAge: "6m"},
+		{Key: "older_than_1year", Label: "Older than 1 year", MinAge: "1y"},
+	}
+}
+
+// parseAgeDays converts an age such as "10d", "2w", "3m" or "1y" to days.
+// An empty age is zero.
+func parseAgeDays(age string) (float64, error) {
+	age = strings.TrimSpace(strings.ToLower(age))
+	if age == "" {
+		return 0, nil
+	}
+
+	unitDays := map[byte]float64{'d': 1, 'w': daysPerWeek, 'm': daysPerMonth, 'y': daysPerYear}
+	unit, ok := unitDays[age[len(age)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid age %q: unit must be d, w, m or y", age)
+	}
+
+	value, err := strconv.ParseFloat(age[:len(age)-1], 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid age %q: expected a non-negative number before the unit", age)
+	}
+	return value * unit, nil
+}
+
+// AgeBucketScheme assigns unread articles to ordered age buckets
+type AgeBucketScheme struct {
+	buckets []schema.AgeBucket
+	minDays []float64
+}
+
+// NewAgeBucketScheme validates buckets ordered from youngest to oldest.
+// Each bucket starts at its min_age; the first bucket must start at zero.
+func NewAgeBucketScheme(buckets []schema.AgeBucket) (*AgeBucketScheme, error) {
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("at least one age bucket is required")
+	}
+
+	scheme := &AgeBucketScheme{buckets: buckets}
+	seen := make(map[string]bool)
+	for i, bucket := range buckets {
+		if strings.TrimSpace(bucket.Key) == "" {
+			return nil, fmt.Errorf("age bucket %d has no key", i)
+		}
+		if seen[bucket.Key] {
+			return nil, fmt.Errorf("age bucket %q is declared twice", bucket.Key)
+		}
+		seen[bucket.Key] = true
+
+		days, err := parseAgeDays(bucket.MinAge)
+		if err != nil {
+			return nil, fmt.Errorf("age bucket %q: %w", bucket.Key, err)
+		}
+		if i == 0 && days != 0 {
+			return nil, fmt.Errorf("first age bucket %q must not have a min_age", bucket.Key)
+		}
+		if i > 0 && days <= scheme.minDays[i-1] {
+			return nil, fmt.Errorf("age bucket %q must start after %q", bucket.Key, buckets[i-1].Key)
+		}
+		scheme.minDays = append(scheme.minDays, days)
+	}
+
+	return scheme, nil
+}
+
+// LoadAgeBucketScheme reads and validates an age buckets YAML file
+func LoadAgeBucketScheme(path string) (*AgeBucketScheme, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read age buckets %s: %w", path, err)
+	}
+
+	var config AgeBucketConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse age buckets %s: %w", path, err)
+	}
+
+	scheme, err := NewAgeBucketScheme(config.Buckets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid age buckets %s: %w", path, err)
+	}
+	return scheme, nil
+}
+
+// defaultAgeBucketScheme is the built-in scheme; DefaultAgeBuckets is always valid
+var defaultAgeBucketScheme, _ = NewAgeBucketScheme(DefaultAgeBuckets())
+
+// loadDefaultAgeBucketScheme reads the default bucket file, logging and falling back to the built-in scheme
+func loadDefaultAgeBucketScheme() *AgeBucketScheme {
+	scheme, err := LoadAgeBucketScheme(DefaultAgeBucketsPath)
+	if err != nil {
+		log.Printf("Warning: Using default age buckets: %v\n", err)
+		return defaultAgeBucketScheme
+	}
+	return scheme
+}
+
+// Buckets returns the scheme's buckets in display order
+func (s *AgeBucketScheme) Buckets() []schema.AgeBucket {
+	return append([]schema.AgeBucket(nil), s.buckets...)
+}
+
+// Bucket returns the key of the bucket an article dated articleDate falls into on referenceDate.
+// Articles dated after the reference date land in the youngest bucket.
+func (s *AgeBucketScheme) Bucket(articleDate, referenceDate time.Time) string {
+	days := referenceDate.Sub(articleDate).Hours() / 24
+
+	key := s.buckets[0].Key
+	for i, minDays := range s.minDays {
+		if days >= minDays {
+			key = s.buckets[i].Key
+		}
+	}
+	return key
+}
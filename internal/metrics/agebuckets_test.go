@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// ============================================================================
+// AgeBucketScheme: Assigns unread articles to configurable age buckets
+// ============================================================================
+
+func TestParseAgeDays(t *testing.T) {
+	tests := []struct {
+		age         string
+		expected    float64
+		expectError bool
+	}{
+		{"", 0, false},
+		{"10d", 10, false},
+		{"2w", 14, false},
+		{"3m", 3 * daysPerMonth, false},
+		{"2Y", 2 * daysPerYear, false},
+		{"5", 0, true},
+		{"xm", 0, true},
+		{"-1y", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.age, func(t *testing.T) {
+			result, err := parseAgeDays(tt.age)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("parseAgeDays(%q) error = %v, expectError %v", tt.age, err, tt.expectError)
+			}
+			if math.Abs(result-tt.expected) > 1e-9 {
+				t.Errorf("parseAgeDays(%q) = %v, want %v", tt.age, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAgeBucketSchemeBucket(t *testing.T) {
+	scheme, err := NewAgeBucketScheme([]schema.AgeBucket{
+		{Key: "fresh"},
+		{Key: "1_to_2_years", MinAge: "1y"},
+		{Key: "older_than_2_years", MinAge: "2y"},
+	})
+	if err != nil {
+		t.Fatalf("NewAgeBucketScheme() unexpected error: %v", err)
+	}
+
+	reference := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		articleDate time.Time
+		expected    string
+	}{
+		{"future article", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), "fresh"},
+		{"six months", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), "fresh"},
+		{"eighteen months", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), "1_to_2_years"},
+		{"three years", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), "older_than_2_years"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := scheme.Bucket(tt.articleDate, reference); result != tt.expected {
+				t.Errorf("Bucket(%v) = %q, want %q", tt.articleDate, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewAgeBucketSchemeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets []schema.AgeBucket
+	}{
+		{"empty", nil},
+		{"missing key", []schema.AgeBucket{{Label: "No key"}}},
+		{"duplicate key", []schema.AgeBucket{{Key: "a"}, {Key: "a", MinAge: "1m"}}},
+		{"first bucket has min age", []schema.AgeBucket{{Key: "a", MinAge: "1m"}}},
+		{"not increasing", []schema.AgeBucket{{Key: "a"}, {Key: "b", MinAge: "1y"}, {Key: "c", MinAge: "6m"}}},
+		{"invalid age", []schema.AgeBucket{{Key: "a"}, {Key: "b", MinAge: "soon"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAgeBucketScheme(tt.buckets); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadAgeBucketScheme(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	t.Run("parses buckets file", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "age_buckets.yml")
+		content := `
+buckets:
+  - key: recent
+    label: Recent
+  - key: older_than_2_years
+    label: Older than 2 years
+    min_age: 2y
+`
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		scheme, err := LoadAgeBucketScheme(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		buckets := scheme.Buckets()
+		if len(buckets) != 2 || buckets[1].Label != "Older than 2 years" || buckets[1].MinAge != "2y" {
+			t.Errorf("unexpected buckets: %+v", buckets)
+		}
+	})
+
+	t.Run("shipped config keeps the default series", func(t *testing.T) {
+		scheme, err := LoadAgeBucketScheme(filepath.Join("..", "..", DefaultAgeBucketsPath))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(scheme.Buckets(), DefaultAgeBuckets()) {
+			t.Errorf("expected config to match DefaultAgeBuckets, got %+v", scheme.Buckets())
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadAgeBucketScheme(filepath.Join(tmpDir, "missing.yml")); err == nil {
+			t.Error("expected error for missing file, got nil")
+		}
+	})
+}
+
+func TestProcessArticleRowsAgeBuckets(t *testing.T) {
+	scheme, _ := NewAgeBucketScheme([]schema.AgeBucket{
+		{Key: "recent"},
+		{Key: "older_than_2_years", MinAge: "2y"},
+	})
+	rows := [][]interface{}{
+		{"Date", "Title", "Link", "Category", "Read"},
+		{"2023-06-01", "Old", "https://example.com/a", "GitHub", "FALSE"},
+		{"2025-06-01", "Newer", "https://example.com/b", "GitHub", "FALSE"},
+	}
+
+	metrics := schema.Metrics{
+		BySource:                     make(map[string]int),
+		BySourceReadStatus:           make(map[string][2]int),
+		ByYear:                       make(map[string]int),
+		ByMonth:                      make(map[string]int),
+		ByYearAndMonth:               make(map[string]map[string]int),
+		ByMonthAndSource:             make(map[string]map[string][2]int),
+		ByCategory:                   make(map[string][2]int),
+		UnreadByMonth:                make(map[string]int),
+		UnreadByCategory:             make(map[string]int),
+		UnreadBySource:               make(map[string]int),
+		UnreadByYear:                 make(map[string]int),
+		UnreadArticleAgeDistribution: make(map[string]int),
+	}
+
+	// The same rows against two snapshot dates land in different buckets
+	var earliestDate, latestDate time.Time
+	processArticleRows(rows, &metrics, &earliestDate, &latestDate, rowProcessingOptions{
+		ageBuckets: scheme,
+		asOf:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	if metrics.UnreadArticleAgeDistribution["recent"] != 2 || metrics.UnreadArticleAgeDistribution["older_than_2_years"] != 0 {
+		t.Errorf("unexpected distribution at 2025-01-01: %v", metrics.UnreadArticleAgeDistribution)
+	}
+
+	metrics.UnreadArticleAgeDistribution = make(map[string]int)
+	processArticleRows(rows, &metrics, &earliestDate, &latestDate, rowProcessingOptions{
+		ageBuckets: scheme,
+		asOf:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	if metrics.UnreadArticleAgeDistribution["recent"] != 1 || metrics.UnreadArticleAgeDistribution["older_than_2_years"] != 1 {
+		t.Errorf("unexpected distribution at 2026-01-01: %v", metrics.UnreadArticleAgeDistribution)
+	}
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"strings"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// ErrStaleAnalysis is returned when an analysis sidecar was generated for other snapshot content,
+// e.g. before the snapshot was overwritten
+var ErrStaleAnalysis = errors.New("analysis was generated for a different version of the snapshot")
+
+// AnalysisFilename returns the name of the AI analysis sidecar of a snapshot, e.g. 2026-01-02.analysis.json
+func AnalysisFilename(snapshot string) string {
+	return strings.TrimSuffix(snapshot, ".json") + ".analysis.json"
+}
+
+// SnapshotHash returns the sha256 of a snapshot's JSON document. Formatting and key order are
+// ignored, so the hash survives compaction into the archive.
+func SnapshotHash(content []byte) (string, error) {
+	doc, err := decodeArchiveDoc(content)
+	if err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return sha256Hex(canonical), nil
+}
+
+// SaveAnalysis writes the AI delta analysis of snapshot name to its sidecar, linked to the
+// snapshot content as stored. The snapshot itself is not rewritten.
+func SaveAnalysis(ctx context.Context, store SnapshotStore, name, analysis string) error {
+	content, err := store.ReadFile(ctx, name)
+	if err != nil {
+		return err
+	}
+	hash, err := SnapshotHash(content)
+	if err != nil {
+		return fmt.Errorf("unable to parse snapshot %s: %w", name, err)
+	}
+
+	sidecar, err := json.MarshalIndent(schema.SnapshotAnalysis{
+		Snapshot:        name,
+		SnapshotSHA256:  hash,
+		GeneratedAt:     time.Now().UTC(),
+		AIDeltaAnalysis: analysis,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis: %w", err)
+	}
+	return store.WriteFile(ctx, AnalysisFilename(name), sidecar)
+}
+
+// ApplyAnalysis sets m.AIDeltaAnalysis from sidecar, the content of the analysis file of snapshot
+// name, when it was generated for the snapshot content. An empty sidecar leaves m as is; one for
+// other content fails with ErrStaleAnalysis.
+func ApplyAnalysis(name string, content, sidecar []byte, m *schema.Metrics) error {
+	if len(sidecar) == 0 {
+		return nil
+	}
+	var analysis schema.SnapshotAnalysis
+	if err := json.Unmarshal(sidecar, &analysis); err != nil {
+		return fmt.Errorf("unable to parse %s: %w", AnalysisFilename(name), err)
+	}
+	hash, err := SnapshotHash(content)
+	if err != nil {
+		return fmt.Errorf("unable to parse snapshot %s: %w", name, err)
+	}
+	if analysis.SnapshotSHA256 != hash {
+		return fmt.Errorf("%s: %w", AnalysisFilename(name), ErrStaleAnalysis)
+	}
+	m.AIDeltaAnalysis = analysis.AIDeltaAnalysis
+	m.AIDeltaAnalysisGeneratedAt = analysis.GeneratedAt
+	return nil
+}
+
+// ReadAnalysisFile returns the content of the analysis sidecar of snapshot name, or nil when the
+// snapshot has none
+func ReadAnalysisFile(ctx context.Context, store SnapshotStore, name string) ([]byte, error) {
+	sidecar, err := store.ReadFile(ctx, AnalysisFilename(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return sidecar, err
+}
+
+// LoadSnapshotWithAnalysis loads a snapshot and merges its AI analysis sidecar. A sidecar that
+// cannot be read or belongs to another version of the snapshot is skipped with a warning, leaving
+// the analysis stored in the snapshot itself, if any.
+func LoadSnapshotWithAnalysis(ctx context.Context, store SnapshotStore, name string) (schema.Metrics, error) {
+	content, err := store.ReadFile(ctx, name)
+	if err != nil {
+		return schema.Metrics{}, err
+	}
+	snapshot, err := DecodeSnapshot(name, content)
+	if err != nil {
+		return snapshot, err
+	}
+
+	sidecar, err := ReadAnalysisFile(ctx, store, name)
+	if err == nil {
+		err = ApplyAnalysis(name, content, sidecar, &snapshot)
+	}
+	if err != nil {
+		log.Printf("Warning: Skipping AI analysis of %s: %v", store.Location(name), err)
+	}
+	return snapshot, nil
+}
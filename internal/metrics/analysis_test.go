@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+func TestSnapshotHash(t *testing.T) {
+	a, err := SnapshotHash([]byte(`{"total_articles": 10, "read_count": 4}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, _ := SnapshotHash([]byte("{\n  \"read_count\": 4,\n  \"total_articles\": 10\n}")); a != b {
+		t.Error("expected formatting and key order to be ignored")
+	}
+	if c, _ := SnapshotHash([]byte(`{"total_articles": 11, "read_count": 4}`)); a == c {
+		t.Error("expected different content to hash differently")
+	}
+	if _, err := SnapshotHash([]byte(`{"total_articles":`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestLoadSnapshotWithAnalysis(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	// Older snapshots carry the analysis inline and have no sidecar
+	os.WriteFile(filepath.Join(dir, "2026-01-01.json"), []byte(`{"total_articles": 100, "ai_delta_analysis": "inline"}`), 0644)
+	store.Save(ctx, "2026-01-08.json", schema.Metrics{TotalArticles: 110}, false)
+
+	if err := SaveAnalysis(ctx, store, "2026-01-08.json", "Ten more articles."); err != nil {
+		t.Fatalf("SaveAnalysis failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		snapshot string
+		expected string
+	}{
+		{"inline analysis without a sidecar", "2026-01-01.json", "inline"},
+		{"sidecar merged", "2026-01-08.json", "Ten more articles."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := LoadSnapshotWithAnalysis(ctx, store, tt.snapshot)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if m.AIDeltaAnalysis != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, m.AIDeltaAnalysis)
+			}
+			// Only a sidecar records when the analysis was generated
+			if hasSidecar := tt.snapshot == "2026-01-08.json"; m.AIDeltaAnalysisGeneratedAt.IsZero() == hasSidecar {
+				t.Errorf("unexpected generated_at %v", m.AIDeltaAnalysisGeneratedAt)
+			}
+		})
+	}
+
+	t.Run("sidecar survives compaction", func(t *testing.T) {
+		if _, err := CompactSnapshots(ctx, store, false); err != nil {
+			t.Fatalf("compaction failed: %v", err)
+		}
+		if m, _ := LoadSnapshotWithAnalysis(ctx, store, "2026-01-08.json"); m.AIDeltaAnalysis != "Ten more articles." {
+			t.Errorf("expected the sidecar merged into the archived snapshot, got %q", m.AIDeltaAnalysis)
+		}
+	})
+
+	t.Run("stale sidecar skipped", func(t *testing.T) {
+		store.Save(ctx, "2026-01-08.json", schema.Metrics{TotalArticles: 111}, true)
+		m, err := LoadSnapshotWithAnalysis(ctx, store, "2026-01-08.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.TotalArticles != 111 || m.AIDeltaAnalysis != "" {
+			t.Errorf("expected the overwritten snapshot without the old analysis, got %+v", m)
+		}
+
+		content, _ := store.ReadFile(ctx, "2026-01-08.json")
+		sidecar, _ := ReadAnalysisFile(ctx, store, "2026-01-08.json")
+		if err := ApplyAnalysis("2026-01-08.json", content, sidecar, &m); !errors.Is(err, ErrStaleAnalysis) {
+			t.Errorf("expected ErrStaleAnalysis, got %v", err)
+		}
+	})
+
+	t.Run("corrupt sidecar skipped", func(t *testing.T) {
+		os.WriteFile(filepath.Join(dir, "2026-01-08.analysis.json"), []byte(`{"snapshot":`), 0644)
+		if _, err := LoadSnapshotWithAnalysis(ctx, store, "2026-01-08.json"); err != nil {
+			t.Errorf("expected a corrupt sidecar not to fail the load, got %v", err)
+		}
+	})
+}
+
+func TestFileStoreAtomicWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	store.Save(ctx, "2026-01-01.json", schema.Metrics{TotalArticles: 100}, false)
+	if err := store.Save(ctx, "2026-01-01.json", schema.Metrics{TotalArticles: 101}, true); err != nil {
+		t.Fatalf("overwrite failed: %v", err)
+	}
+
+	// A write that cannot be renamed into place leaves the target and no temporary file behind
+	os.Mkdir(filepath.Join(dir, "2026-01-08.json"), 0755)
+	if err := store.WriteFile(ctx, "2026-01-08.json", []byte("{}")); err == nil {
+		t.Error("expected an error when the target is a directory")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("temporary file %s left behind", entry.Name())
+		}
+	}
+	info, err := os.Stat(filepath.Join(dir, "2026-01-01.json"))
+	if err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("expected a 0644 snapshot, got %v, %v", info, err)
+	}
+	if m, _ := store.Load(ctx, "2026-01-01.json"); m.TotalArticles != 101 {
+		t.Errorf("expected the replaced snapshot, got %d articles", m.TotalArticles)
+	}
+}
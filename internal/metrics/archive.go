@@ -0,0 +1,405 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ArchiveFilename is the compacted snapshot archive stored next to the loose snapshot files
+const ArchiveFilename = "snapshots.archive.gz"
+
+// archiveEntry is one line of the archive: a full snapshot (Base) or the changes against the
+// snapshot on the line before it (Patch)
+type archiveEntry struct {
+	Name  string          `json:"name"`
+	Base  json.RawMessage `json:"base,omitempty"`
+	Patch json.RawMessage `json:"patch,omitempty"`
+}
+
+// SnapshotArchive holds snapshots compacted into one gzip-compressed JSON Lines file. Weekly
+// snapshots are mostly identical, so each one is stored as a merge patch in the style of
+// RFC 7386 against the snapshot before it: changed keys carry their new value, removed keys are
+// null and nested objects are patched recursively. A snapshot whose patch would not be smaller,
+// or cannot be expressed (a value changed to null), is stored whole as a new base.
+type SnapshotArchive struct {
+	names    []string
+	contents map[string][]byte
+}
+
+// ArchivedSnapshot is the name and file content of a snapshot to archive
+type ArchivedSnapshot struct {
+	Name    string
+	Content []byte
+}
+
+// Names returns the archived snapshot names, oldest first
+func (a *SnapshotArchive) Names() []string {
+	return a.names
+}
+
+// Content returns the archived snapshot as JSON, or false when it is not in the archive
+func (a *SnapshotArchive) Content(name string) ([]byte, bool) {
+	content, ok := a.contents[name]
+	return content, ok
+}
+
+// EncodeArchive compacts snapshots into the archive format. Snapshots are sorted by name first,
+// so the deltas always run forward in time.
+func EncodeArchive(snapshots []ArchivedSnapshot) ([]byte, error) {
+	sorted := append([]ArchivedSnapshot(nil), snapshots...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+
+	var previous any
+	for i, snapshot := range sorted {
+		if i > 0 && snapshot.Name == sorted[i-1].Name {
+			return nil, fmt.Errorf("snapshot %s listed twice", snapshot.Name)
+		}
+		doc, err := decodeArchiveDoc(snapshot.Content)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse snapshot %s: %w", snapshot.Name, err)
+		}
+
+		entry := archiveEntry{Name: snapshot.Name}
+		base, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode snapshot %s: %w", snapshot.Name, err)
+		}
+		entry.Base = base
+		if previous != nil {
+			if patch, ok := diffArchiveDoc(previous, doc); ok && reflect.DeepEqual(applyArchivePatch(previous, patch), doc) {
+				encoded, err := json.Marshal(patch)
+				if err != nil {
+					return nil, fmt.Errorf("failed to encode snapshot %s: %w", snapshot.Name, err)
+				}
+				if len(encoded) < len(base) {
+					entry.Base, entry.Patch = nil, encoded
+				}
+			}
+		}
+
+		if err := encoder.Encode(entry); err != nil {
+			return nil, fmt.Errorf("failed to write archive: %w", err)
+		}
+		previous = doc
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeArchive rebuilds every snapshot in an archive
+func DecodeArchive(data []byte) (*SnapshotArchive, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read archive: %w", err)
+	}
+	defer zr.Close()
+
+	archive := &SnapshotArchive{contents: make(map[string][]byte)}
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	var previous any
+	for line := 1; scanner.Scan(); line++ {
+		var entry archiveEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("unable to parse archive line %d: %w", line, err)
+		}
+
+		var doc any
+		switch {
+		case entry.Base != nil:
+			doc, err = decodeArchiveDoc(entry.Base)
+		case entry.Patch != nil && previous != nil:
+			var patch any
+			if patch, err = decodeArchiveDoc(entry.Patch); err == nil {
+				doc = applyArchivePatch(previous, patch)
+			}
+		default:
+			err = errors.New("no base snapshot to apply the patch to")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to rebuild %s from archive line %d: %w", entry.Name, line, err)
+		}
+
+		content, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode snapshot %s: %w", entry.Name, err)
+		}
+		if _, ok := archive.contents[entry.Name]; !ok {
+			archive.names = append(archive.names, entry.Name)
+		}
+		archive.contents[entry.Name] = content
+		previous = doc
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read archive: %w", err)
+	}
+
+	sort.Strings(archive.names)
+	return archive, nil
+}
+
+// SameSnapshotContent reports whether two snapshot files hold the same JSON document, regardless
+// of formatting and key order
+func SameSnapshotContent(a, b []byte) bool {
+	docA, errA := decodeArchiveDoc(a)
+	docB, errB := decodeArchiveDoc(b)
+	return errA == nil && errB == nil && reflect.DeepEqual(docA, docB)
+}
+
+// decodeArchiveDoc parses JSON into generic values, keeping numbers as written so the rebuilt
+// snapshot carries the same figures
+func decodeArchiveDoc(content []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected content after the snapshot")
+	}
+	return doc, nil
+}
+
+// diffArchiveDoc returns the patch turning previous into current. It fails when current sets a
+// key to null, which a patch reads as a removal.
+func diffArchiveDoc(previous, current any) (any, bool) {
+	prevObject, prevOK := previous.(map[string]any)
+	curObject, curOK := current.(map[string]any)
+	if !prevOK || !curOK {
+		return current, current != nil
+	}
+
+	patch := make(map[string]any)
+	for key := range prevObject {
+		if _, ok := curObject[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, value := range curObject {
+		prevValue, ok := prevObject[key]
+		if ok && reflect.DeepEqual(prevValue, value) {
+			continue
+		}
+		if value == nil {
+			return nil, false
+		}
+		if _, isObject := prevValue.(map[string]any); ok && isObject {
+			sub, ok := diffArchiveDoc(prevValue, value)
+			if !ok {
+				return nil, false
+			}
+			patch[key] = sub
+			continue
+		}
+		patch[key] = value
+	}
+	return patch, true
+}
+
+// applyArchivePatch applies a patch made by diffArchiveDoc without modifying target
+func applyArchivePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	result := make(map[string]any, len(targetObject))
+	for key, value := range targetObject {
+		result[key] = value
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+		result[key] = applyArchivePatch(result[key], value)
+	}
+	return result
+}
+
+// readArchive loads the archive through read, the store's raw file access; a store without an
+// archive has an empty one
+func readArchive(ctx context.Context, read func(context.Context, string) ([]byte, error)) (*SnapshotArchive, error) {
+	data, err := read(ctx, ArchiveFilename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &SnapshotArchive{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return DecodeArchive(data)
+}
+
+// archiveCache holds a store's decoded archive, so listing and reading archived snapshots decodes
+// it once per store. The store drops it whenever it writes or removes the archive itself; changes
+// made through another store instance are not seen.
+type archiveCache struct {
+	mu      sync.Mutex
+	archive *SnapshotArchive
+}
+
+// load returns the cached archive, reading it through read, the store's raw file access, on
+// first use
+func (c *archiveCache) load(ctx context.Context, read func(context.Context, string) ([]byte, error)) (*SnapshotArchive, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.archive != nil {
+		return c.archive, nil
+	}
+	archive, err := readArchive(ctx, read)
+	if err != nil {
+		return nil, err
+	}
+	c.archive = archive
+	return archive, nil
+}
+
+// invalidate drops the cached archive when name is the archive file
+func (c *archiveCache) invalidate(name string) {
+	if name != ArchiveFilename {
+		return
+	}
+	c.mu.Lock()
+	c.archive = nil
+	c.mu.Unlock()
+}
+
+// list merges the loose snapshot names with the archived ones, oldest first
+func (c *archiveCache) list(ctx context.Context, loose []string, read func(context.Context, string) ([]byte, error)) ([]string, error) {
+	archive, err := c.load(ctx, read)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(loose))
+	names := append([]string(nil), loose...)
+	for _, name := range loose {
+		seen[name] = true
+	}
+	for _, name := range archive.Names() {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// read reads name through read, falling back to the archive for a snapshot that was compacted
+// away. A loose file always wins over its archived copy.
+func (c *archiveCache) read(ctx context.Context, name string, read func(context.Context, string) ([]byte, error)) ([]byte, error) {
+	content, err := read(ctx, name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) || !IsSnapshotFilename(name) {
+		return content, err
+	}
+	archive, archiveErr := c.load(ctx, read)
+	if archiveErr != nil {
+		return nil, archiveErr
+	}
+	if archived, ok := archive.Content(name); ok {
+		return archived, nil
+	}
+	return nil, err
+}
+
+// CompactResult describes a compaction of the snapshot store
+type CompactResult struct {
+	Snapshots    int // snapshots in the archive
+	Added        int // loose snapshots folded into the archive, new or replacing an archived copy
+	JSONBytes    int // size of the snapshots as JSON
+	ArchiveBytes int // size of the archive
+}
+
+// CompactSnapshots folds every loose snapshot in store into the archive and removes the loose
+// files. The archive is read back and every snapshot compared with its source before anything
+// is removed. Quality reports and other files are left alone. With dryRun nothing is written.
+func CompactSnapshots(ctx context.Context, store SnapshotStore, dryRun bool) (CompactResult, error) {
+	var result CompactResult
+	names, err := store.List(ctx)
+	if err != nil {
+		return result, err
+	}
+	if len(names) == 0 {
+		return result, fmt.Errorf("no snapshots in %s", store.Location(""))
+	}
+	previous, err := readArchive(ctx, store.ReadFile)
+	if err != nil {
+		return result, err
+	}
+
+	snapshots := make([]ArchivedSnapshot, 0, len(names))
+	for _, name := range names {
+		content, err := store.ReadFile(ctx, name)
+		if err != nil {
+			return result, err
+		}
+		if archived, ok := previous.Content(name); !ok || !SameSnapshotContent(archived, content) {
+			result.Added++
+		}
+		snapshots = append(snapshots, ArchivedSnapshot{Name: name, Content: content})
+		result.JSONBytes += len(content)
+	}
+
+	data, err := EncodeArchive(snapshots)
+	if err != nil {
+		return result, err
+	}
+	result.Snapshots, result.ArchiveBytes = len(snapshots), len(data)
+	if dryRun {
+		return result, verifyArchive(data, snapshots)
+	}
+
+	if err := store.WriteFile(ctx, ArchiveFilename, data); err != nil {
+		return result, err
+	}
+	written, err := store.ReadFile(ctx, ArchiveFilename)
+	if err != nil {
+		return result, err
+	}
+	if err := verifyArchive(written, snapshots); err != nil {
+		return result, err
+	}
+	for _, snapshot := range snapshots {
+		if err := store.Remove(ctx, snapshot.Name); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// verifyArchive checks that data rebuilds exactly the given snapshots
+func verifyArchive(data []byte, snapshots []ArchivedSnapshot) error {
+	archive, err := DecodeArchive(data)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		content, ok := archive.Content(snapshot.Name)
+		if !ok || !SameSnapshotContent(content, snapshot.Content) {
+			return fmt.Errorf("archive does not rebuild snapshot %s; no loose files were removed", snapshot.Name)
+		}
+	}
+	return nil
+}
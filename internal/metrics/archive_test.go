@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	snapshots := []ArchivedSnapshot{
+		{"2026-01-08.json", []byte(`{"total_articles": 110, "by_source": {"a": 60, "b": 50}, "goals": null, "top": [1, 2, 3]}`)},
+		{"2026-01-01.json", []byte(`{"total_articles": 100, "by_source": {"a": 60, "b": 40}, "read_rate": 66.67, "goals": null}`)},
+		// A key changing to null cannot be patched, so this one is stored whole
+		{"2026-01-15.json", []byte(`{"total_articles": 120, "by_source": null, "top": [1, 2, 3, 4], "note": {"x": null}}`)},
+		{"2026-01-22.json", []byte(`{"total_articles": 121, "by_source": null, "top": [1, 2, 3, 4], "note": {"x": null, "y": 1.50}}`)},
+	}
+
+	data, err := EncodeArchive(snapshots)
+	if err != nil {
+		t.Fatalf("EncodeArchive failed: %v", err)
+	}
+	archive, err := DecodeArchive(data)
+	if err != nil {
+		t.Fatalf("DecodeArchive failed: %v", err)
+	}
+
+	expected := []string{"2026-01-01.json", "2026-01-08.json", "2026-01-15.json", "2026-01-22.json"}
+	if strings.Join(archive.Names(), ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, archive.Names())
+	}
+	for _, snapshot := range snapshots {
+		content, ok := archive.Content(snapshot.Name)
+		if !ok || !SameSnapshotContent(content, snapshot.Content) {
+			t.Errorf("%s rebuilt as %s", snapshot.Name, content)
+		}
+	}
+	if content, _ := archive.Content("2026-01-22.json"); !bytes.Contains(content, []byte("1.50")) {
+		t.Errorf("expected numbers kept as written, got %s", content)
+	}
+
+	again, _ := EncodeArchive(snapshots)
+	if !bytes.Equal(data, again) {
+		t.Error("expected the same snapshots to encode to the same archive")
+	}
+
+	if _, err := EncodeArchive([]ArchivedSnapshot{{"2026-01-01.json", []byte(`{"total_articles": `)}}); err == nil {
+		t.Error("expected an error for an unparsable snapshot")
+	}
+	if _, err := DecodeArchive([]byte("not gzip")); err == nil {
+		t.Error("expected an error for a corrupt archive")
+	}
+}
+
+func TestCompactSnapshots(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	files := map[string]string{
+		"2026-01-01.json":         `{"total_articles": 100, "read_count": 60}`,
+		"2026-01-08.json":         `{"total_articles": 110, "read_count": 61}`,
+		"2026-01-08.quality.json": `{"rows_scanned": 110}`,
+		".gitkeep":                "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		result, err := CompactSnapshots(ctx, store, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Snapshots != 2 || result.Added != 2 || result.ArchiveBytes == 0 {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ArchiveFilename)); !os.IsNotExist(err) {
+			t.Error("expected no archive after a dry run")
+		}
+	})
+
+	t.Run("folds loose snapshots into the archive", func(t *testing.T) {
+		if _, err := CompactSnapshots(ctx, store, false); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, name := range []string{"2026-01-01.json", "2026-01-08.json"} {
+			if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+				t.Errorf("expected %s removed", name)
+			}
+		}
+		for _, name := range []string{"2026-01-08.quality.json", ".gitkeep", ArchiveFilename} {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				t.Errorf("expected %s kept: %v", name, err)
+			}
+		}
+		if m, err := store.Load(ctx, "2026-01-08.json"); err != nil || m.ReadCount != 61 {
+			t.Errorf("unexpected archived snapshot: %+v, %v", m, err)
+		}
+	})
+
+	t.Run("adds new and replaced snapshots", func(t *testing.T) {
+		os.WriteFile(filepath.Join(dir, "2026-01-15.json"), []byte(`{"total_articles": 120}`), 0644)
+		os.WriteFile(filepath.Join(dir, "2026-01-08.json"), []byte(`{"total_articles": 110, "read_count": 62}`), 0644)
+
+		result, err := CompactSnapshots(ctx, store, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Snapshots != 3 || result.Added != 2 {
+			t.Errorf("expected 3 snapshots with 2 added, got %+v", result)
+		}
+		if m, _ := store.Load(ctx, "2026-01-08.json"); m.ReadCount != 62 {
+			t.Errorf("expected the loose copy to replace the archived one, got %d reads", m.ReadCount)
+		}
+		if names, _ := ListSnapshotFiles(dir); len(names) != 0 {
+			t.Errorf("expected no loose snapshots left, got %v", names)
+		}
+	})
+
+	t.Run("empty store", func(t *testing.T) {
+		if _, err := CompactSnapshots(ctx, NewFileStore(t.TempDir()), false); err == nil {
+			t.Error("expected an error without snapshots")
+		}
+	})
+}
+
+func TestArchiveCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	first, _ := EncodeArchive([]ArchivedSnapshot{{"2026-01-01.json", []byte(`{"total_articles": 100}`)}})
+	if err := store.WriteFile(ctx, ArchiveFilename, first); err != nil {
+		t.Fatal(err)
+	}
+	if names, err := store.List(ctx); err != nil || strings.Join(names, ",") != "2026-01-01.json" {
+		t.Fatalf("unexpected snapshots %v, %v", names, err)
+	}
+
+	// Changes behind the store's back are not seen: the decoded archive is reused
+	os.WriteFile(filepath.Join(dir, ArchiveFilename), []byte("not gzip"), 0644)
+	if m, err := store.Load(ctx, "2026-01-01.json"); err != nil || m.TotalArticles != 100 {
+		t.Errorf("expected the cached archive, got %+v, %v", m, err)
+	}
+
+	// Writing the archive through the store drops the cache
+	second, _ := EncodeArchive([]ArchivedSnapshot{{"2026-01-08.json", []byte(`{"total_articles": 110}`)}})
+	if err := store.WriteFile(ctx, ArchiveFilename, second); err != nil {
+		t.Fatal(err)
+	}
+	if names, err := store.List(ctx); err != nil || strings.Join(names, ",") != "2026-01-08.json" {
+		t.Errorf("expected the rewritten archive, got %v, %v", names, err)
+	}
+
+	if err := store.Remove(ctx, ArchiveFilename); err != nil {
+		t.Fatal(err)
+	}
+	if names, err := store.List(ctx); err != nil || len(names) != 0 {
+		t.Errorf("expected no snapshots after removing the archive, got %v, %v", names, err)
+	}
+}
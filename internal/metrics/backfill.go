@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// BackfillDates returns weekly snapshot dates from `from` through `to`, inclusive. With a non-zero
+// anchor (an existing snapshot date) the dates fall on whole weeks from it, so reconstructed
+// snapshots keep the cadence of the real ones; otherwise they start at `from`.
+func BackfillDates(from, to, anchor time.Time) []time.Time {
+	start := from
+	if !anchor.IsZero() {
+		offset := int(snapshotDay(from).Sub(snapshotDay(anchor)).Hours()/24) % 7
+		if offset < 0 {
+			offset += 7
+		}
+		if offset > 0 {
+			start = from.AddDate(0, 0, 7-offset)
+		}
+	}
+
+	var dates []time.Time
+	for date := start; !date.After(to); date = date.AddDate(0, 0, 7) {
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// rewoundSheet is the articles sheet as it stood on a past date
+type rewoundSheet struct {
+	rows           [][]interface{} // header first, then the rows in sheet order
+	sheetRows      []int           // 1-based sheet row number of each entry in rows
+	articles       int             // dated rows added on or before the date
+	reconstruction schema.Reconstruction
+}
+
+// reconstructArticleRows rewinds the articles sheet to asOf: rows added later are dropped and
+// articles read after asOf are marked unread. Read articles without a read date are assumed
+// to have been read when they were added. Rows without a valid date cannot be placed in time,
+// so they are kept as they are for the quality audit to reject.
+func reconstructArticleRows(rows [][]interface{}, asOf time.Time) rewoundSheet {
+	var sheet rewoundSheet
+	if len(rows) == 0 {
+		return sheet
+	}
+
+	sheet.rows = [][]interface{}{rows[0]}
+	sheet.sheetRows = []int{1}
+	for i, row := range rows[1:] {
+		// Sheet rows are 1-based and the header is row 1
+		sheetRow := i + 2
+
+		if len(row) < ColRead+1 {
+			sheet.rows = append(sheet.rows, row)
+			sheet.sheetRows = append(sheet.sheetRows, sheetRow)
+			continue
+		}
+		added, err := time.Parse("2006-01-02", strings.TrimSpace(fmt.Sprintf("%v", row[ColDate])))
+		if err != nil {
+			sheet.rows = append(sheet.rows, row)
+			sheet.sheetRows = append(sheet.sheetRows, sheetRow)
+			continue
+		}
+		if added.After(asOf) {
+			continue
+		}
+
+		readStatus := fmt.Sprintf("%v", row[ColRead])
+		if readStatus == "TRUE" || readStatus == "true" {
+			var readDate time.Time
+			if len(row) > ColReadDate {
+				readDate, _ = time.Parse("2006-01-02", strings.TrimSpace(fmt.Sprintf("%v", row[ColReadDate])))
+			}
+
+			if readDate.IsZero() {
+				sheet.reconstruction.ReadDatesAssumed++
+			} else {
+				sheet.reconstruction.ReadDatesKnown++
+				if readDate.After(asOf) {
+					// Copy before rewriting so the ledger itself is untouched
+					row = append([]interface{}(nil), row...)
+					row[ColRead] = "FALSE"
+				}
+			}
+		}
+
+		sheet.rows = append(sheet.rows, row)
+		sheet.sheetRows = append(sheet.sheetRows, sheetRow)
+		sheet.articles++
+	}
+
+	return sheet
+}
+
+// backfillWithFetcher reads the sheet once and reconstructs a snapshot for every date
+func backfillWithFetcher(spreadsheetID string, fetcher SheetsFetcher, dates []time.Time) ([]schema.Metrics, error) {
+	inputs, err := loadSnapshotInputs(spreadsheetID, fetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]schema.Metrics, 0, len(dates))
+	for _, date := range dates {
+		sheet := reconstructArticleRows(inputs.articleRows, date)
+		if sheet.articles == 0 {
+			// Nothing had been added yet
+			continue
+		}
+
+		snapshot := buildSnapshot(inputs, sheet.rows, sheet.sheetRows, date)
+		snapshot.Reconstructed = &sheet.reconstruction
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// BackfillSnapshotsFromSheets creates a Sheets service and reconstructs a snapshot for each date
+// from the article ledger. Dates before the first article produce no snapshot.
+func BackfillSnapshotsFromSheets(ctx context.Context, spreadsheetID, credentialsPath string, dates []time.Time) ([]schema.Metrics, error) {
+	client, err := sheets.NewService(ctx, option.WithCredentialsFile(credentialsPath))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create sheets client: %w", err)
+	}
+
+	return backfillWithFetcher(spreadsheetID, &SheetServiceFetcher{service: client}, dates)
+}
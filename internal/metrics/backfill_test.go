@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// ============================================================================
+// Backfill: Reconstructs weekly snapshots from the article ledger
+// ============================================================================
+
+func createTestLedgerRows() [][]interface{} {
+	return [][]interface{}{
+		{"Date", "Title", "Link", "Category", "Read", "Read Date"},
+		{"2024-01-02", "First", "https://example.com/1", "GitHub", "TRUE", "2024-01-20"},
+		{"2024-01-03", "Second", "https://example.com/2", "GitHub", "TRUE"},
+		{"2024-01-10", "Third", "https://example.com/3", "Substack", "FALSE"},
+		{"2024-01-16", "Fourth", "https://example.com/4", "Substack", "TRUE", "2024-01-17"},
+		{"not-a-date", "Broken", "https://example.com/5", "GitHub", "FALSE"},
+	}
+}
+
+func TestBackfillDates(t *testing.T) {
+	from := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 26, 0, 0, 0, 0, time.UTC)
+
+	dates := BackfillDates(from, to, time.Time{})
+	if len(dates) != 4 {
+		t.Fatalf("expected 4 weekly dates, got %d: %v", len(dates), dates)
+	}
+	if !dates[0].Equal(from) || !dates[3].Equal(to) {
+		t.Errorf("expected %v..%v, got %v..%v", from, to, dates[0], dates[3])
+	}
+
+	if dates := BackfillDates(to, from, time.Time{}); len(dates) != 0 {
+		t.Errorf("expected no dates for an inverted range, got %v", dates)
+	}
+
+	// Existing snapshots fall on Sundays, before and after the range: the dates follow them
+	for _, anchor := range []time.Time{time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 18, 0, 0, 0, time.UTC)} {
+		dates := BackfillDates(from, to, anchor)
+		if len(dates) != 3 || dates[0].Format("2006-01-02") != "2024-01-07" || dates[2].Format("2006-01-02") != "2024-01-21" {
+			t.Errorf("expected Sundays 2024-01-07..2024-01-21 for anchor %v, got %v", anchor, dates)
+		}
+	}
+}
+
+func TestReconstructArticleRows(t *testing.T) {
+	tests := []struct {
+		name              string
+		asOf              time.Time
+		expectedArticles  int
+		expectedSheetRows []int // excluding header; the undated row 6 is always kept
+		expectedRead      int
+		expectedKnown     int
+		expectedAssumed   int
+	}{
+		{
+			name:              "before read dates",
+			asOf:              time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+			expectedArticles:  3,
+			expectedSheetRows: []int{2, 3, 4, 6},
+			expectedRead:      1, // only the row without a read date
+			expectedKnown:     1,
+			expectedAssumed:   1,
+		},
+		{
+			name:              "after all read dates",
+			asOf:              time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			expectedArticles:  4,
+			expectedSheetRows: []int{2, 3, 4, 5, 6},
+			expectedRead:      3,
+			expectedKnown:     2,
+			expectedAssumed:   1,
+		},
+		{
+			name:              "before the first article",
+			asOf:              time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			expectedArticles:  0,
+			expectedSheetRows: []int{6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ledger := createTestLedgerRows()
+			sheet := reconstructArticleRows(ledger, tt.asOf)
+
+			if sheet.articles != tt.expectedArticles {
+				t.Errorf("expected %d articles, got %d", tt.expectedArticles, sheet.articles)
+			}
+			if len(sheet.sheetRows) != len(sheet.rows) {
+				t.Fatalf("expected a sheet row for each of %d rows, got %v", len(sheet.rows), sheet.sheetRows)
+			}
+			if !reflect.DeepEqual(sheet.sheetRows[1:], tt.expectedSheetRows) {
+				t.Errorf("expected sheet rows %v, got %v", tt.expectedSheetRows, sheet.sheetRows[1:])
+			}
+
+			read := 0
+			for _, row := range sheet.rows[1:] {
+				if row[ColRead] == "TRUE" {
+					read++
+				}
+			}
+			if read != tt.expectedRead {
+				t.Errorf("expected %d read rows, got %d", tt.expectedRead, read)
+			}
+			if sheet.reconstruction.ReadDatesKnown != tt.expectedKnown || sheet.reconstruction.ReadDatesAssumed != tt.expectedAssumed {
+				t.Errorf("unexpected reconstruction counts: %+v", sheet.reconstruction)
+			}
+
+			// The ledger itself must not be rewritten
+			if ledger[1][ColRead] != "TRUE" {
+				t.Error("reconstructArticleRows modified the input rows")
+			}
+		})
+	}
+}
+
+func TestBackfillWithFetcher(t *testing.T) {
+	fetcher := &MockSheetsFetcher{
+		spreadsheet: &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{Title: "Articles"}},
+				{Properties: &sheets.SheetProperties{Title: "Providers"}},
+			},
+		},
+		articleRows:  createTestLedgerRows(),
+		providerRows: [][]interface{}{{"Name"}, {"GitHub"}, {"Substack"}},
+	}
+
+	dates := BackfillDates(time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC), time.Time{})
+	snapshots, err := backfillWithFetcher("spreadsheetID", fetcher, dates)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 2023-12-29 precedes every article, leaving 2024-01-05, 01-12 and 01-19
+	if len(snapshots) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(snapshots))
+	}
+
+	expected := []struct {
+		date  string
+		total int
+		read  int
+	}{
+		{"2024-01-05", 2, 1},
+		{"2024-01-12", 3, 1},
+		{"2024-01-19", 4, 2},
+	}
+	for i, want := range expected {
+		snapshot := snapshots[i]
+		if snapshot.LastUpdated.Format("2006-01-02") != want.date {
+			t.Errorf("snapshot %d: expected date %s, got %s", i, want.date, snapshot.LastUpdated.Format("2006-01-02"))
+		}
+		if snapshot.TotalArticles != want.total || snapshot.ReadCount != want.read {
+			t.Errorf("snapshot %s: expected %d total / %d read, got %d / %d", want.date, want.total, want.read, snapshot.TotalArticles, snapshot.ReadCount)
+		}
+		if snapshot.Reconstructed == nil {
+			t.Errorf("snapshot %s: expected to be marked reconstructed", want.date)
+		}
+
+		// The undated row is rejected under its sheet row even though later rows were dropped
+		if report := snapshot.DataQuality; report.RowsRejected != 1 || report.Issues[0].Row != 6 {
+			t.Errorf("snapshot %s: expected sheet row 6 rejected, got %+v", want.date, report.Issues)
+		}
+	}
+}
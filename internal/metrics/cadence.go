@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"math"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// CadenceRollingWeeks is how many recent weeks the rolling average covers
+const CadenceRollingWeeks = 4
+
+// weekReads is the number of articles read during one calendar week (Monday to Sunday)
+type weekReads struct {
+	start    time.Time
+	end      string // date of the week's last snapshot
+	reads    int
+	afterGap bool // the previous snapshot is more than a week earlier, so reads span several weeks
+}
+
+// weekStart returns the Monday of the week containing day
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// calculateWeeklyReads keeps the last snapshot of each week in a chronological series and measures
+// the reads between consecutive weeks. The first week is only a baseline.
+func calculateWeeklyReads(series []schema.Metrics) []weekReads {
+	type weekSnapshot struct {
+		start     time.Time
+		day       time.Time
+		readCount int
+	}
+
+	var snapshots []weekSnapshot
+	for _, snapshot := range series {
+		day := snapshotDay(snapshot.LastUpdated)
+		entry := weekSnapshot{start: weekStart(day), day: day, readCount: snapshot.ReadCount}
+		if len(snapshots) > 0 && snapshots[len(snapshots)-1].start.Equal(entry.start) {
+			snapshots[len(snapshots)-1] = entry
+			continue
+		}
+		snapshots = append(snapshots, entry)
+	}
+
+	weeks := make([]weekReads, 0, len(snapshots))
+	for i := 1; i < len(snapshots); i++ {
+		reads := snapshots[i].readCount - snapshots[i-1].readCount
+		if reads < 0 {
+			reads = 0 // merged duplicates can lower the read count
+		}
+		weeks = append(weeks, weekReads{
+			start:    snapshots[i].start,
+			end:      snapshots[i].day.Format("2006-01-02"),
+			reads:    reads,
+			afterGap: !snapshots[i].start.Equal(snapshots[i-1].start.AddDate(0, 0, 7)),
+		})
+	}
+	return weeks
+}
+
+// CalculateCadence summarizes weekly reading from a chronological series of snapshots (oldest first).
+// Weeks that follow a gap in the snapshots break streaks but are left out of the other figures,
+// since their reads cover more than one week. Returns nil until a full week has been observed.
+func CalculateCadence(series []schema.Metrics) *schema.Cadence {
+	cadence := &schema.Cadence{RollingWeeks: CadenceRollingWeeks}
+
+	var observed []int
+	run := 0
+	for _, week := range calculateWeeklyReads(series) {
+		if week.afterGap {
+			run = 0
+		}
+		if week.reads > 0 {
+			run++
+		} else {
+			run = 0
+		}
+		if run > cadence.LongestStreak {
+			cadence.LongestStreak = run
+		}
+
+		if week.afterGap {
+			continue
+		}
+		observed = append(observed, week.reads)
+		if week.reads == 0 {
+			cadence.ZeroReadWeeks++
+		}
+		if week.reads > cadence.BestWeekReads {
+			cadence.BestWeekReads = week.reads
+			cadence.BestWeek = week.end
+		}
+	}
+	cadence.CurrentStreak = run
+
+	if len(observed) == 0 {
+		return nil
+	}
+	cadence.WeeksObserved = len(observed)
+
+	var sum float64
+	for _, reads := range observed {
+		sum += float64(reads)
+	}
+	mean := sum / float64(len(observed))
+
+	var variance float64
+	for _, reads := range observed {
+		variance += (float64(reads) - mean) * (float64(reads) - mean)
+	}
+	cadence.WeeklyReadsStdDev = math.Round(math.Sqrt(variance/float64(len(observed)))*100) / 100
+
+	recent := observed[max(0, len(observed)-CadenceRollingWeeks):]
+	var recentSum float64
+	for _, reads := range recent {
+		recentSum += float64(reads)
+	}
+	cadence.RollingAvgReads = math.Round(recentSum/float64(len(recent))*100) / 100
+
+	return cadence
+}
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// createTestCadenceSeries builds snapshots on the given dates with cumulative read counts
+func createTestCadenceSeries(points map[string]int) []schema.Metrics {
+	var series []schema.Metrics
+	for date, readCount := range points {
+		day, _ := time.Parse("2006-01-02", date)
+		series = append(series, schema.Metrics{ReadCount: readCount, LastUpdated: day.Add(9 * time.Hour)})
+	}
+	// Snapshots arrive oldest first
+	for i := 1; i < len(series); i++ {
+		for j := i; j > 0 && series[j].LastUpdated.Before(series[j-1].LastUpdated); j-- {
+			series[j], series[j-1] = series[j-1], series[j]
+		}
+	}
+	return series
+}
+
+// ============================================================================
+// CalculateCadence: Summarizes weekly reads across snapshots
+// ============================================================================
+
+func TestCalculateCadence(t *testing.T) {
+	tests := []struct {
+		name     string
+		points   map[string]int
+		expected *schema.Cadence
+	}{
+		{
+			name: "streaks, zero weeks and best week",
+			points: map[string]int{
+				"2026-01-02": 100, // baseline (Friday)
+				"2026-01-09": 105, // 5
+				"2026-01-16": 115, // 10
+				"2026-01-23": 115, // 0
+				"2026-01-30": 118, // 3
+				"2026-02-06": 122, // 4
+			},
+			expected: &schema.Cadence{
+				WeeksObserved:     5,
+				CurrentStreak:     2,
+				LongestStreak:     2,
+				ZeroReadWeeks:     1,
+				BestWeek:          "2026-01-16",
+				BestWeekReads:     10,
+				RollingAvgReads:   4.25,
+				RollingWeeks:      CadenceRollingWeeks,
+				WeeklyReadsStdDev: 3.26,
+			},
+		},
+		{
+			name: "same-week snapshots collapse to the last one",
+			points: map[string]int{
+				"2026-01-05": 10, // Monday
+				"2026-01-12": 12,
+				"2026-01-14": 15,
+				"2026-01-18": 20, // Sunday, same week
+			},
+			expected: &schema.Cadence{
+				WeeksObserved:   1,
+				CurrentStreak:   1,
+				LongestStreak:   1,
+				BestWeek:        "2026-01-18",
+				BestWeekReads:   10,
+				RollingAvgReads: 10,
+				RollingWeeks:    CadenceRollingWeeks,
+			},
+		},
+		{
+			name: "a gap breaks the streak and is left out of the figures",
+			points: map[string]int{
+				"2026-01-02": 100,
+				"2026-01-09": 104, // 4
+				"2026-01-16": 108, // 4
+				"2026-02-06": 140, // 32 over three weeks
+				"2026-02-13": 142, // 2
+			},
+			expected: &schema.Cadence{
+				WeeksObserved:     3,
+				CurrentStreak:     2,
+				LongestStreak:     2,
+				BestWeek:          "2026-01-09",
+				BestWeekReads:     4,
+				RollingAvgReads:   3.33,
+				RollingWeeks:      CadenceRollingWeeks,
+				WeeklyReadsStdDev: 0.94,
+			},
+		},
+		{
+			name:     "a single week has no cadence",
+			points:   map[string]int{"2026-01-05": 10, "2026-01-07": 12},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cadence := CalculateCadence(createTestCadenceSeries(tt.points))
+			if tt.expected == nil {
+				if cadence != nil {
+					t.Errorf("expected nil cadence, got %+v", cadence)
+				}
+				return
+			}
+			if cadence == nil || *cadence != *tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, cadence)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		date     string
+		expected string
+	}{
+		{"2026-01-05", "2026-01-05"}, // Monday
+		{"2026-01-08", "2026-01-05"}, // Thursday
+		{"2026-01-11", "2026-01-05"}, // Sunday
+	}
+
+	for _, tt := range tests {
+		day, _ := time.Parse("2006-01-02", tt.date)
+		if got := weekStart(day).Format("2006-01-02"); got != tt.expected {
+			t.Errorf("weekStart(%s) = %s, want %s", tt.date, got, tt.expected)
+		}
+	}
+}
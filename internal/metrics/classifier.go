@@ -0,0 +1,234 @@
+package metrics
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// DefaultTopicRulesPath is where the topic classification rules are read from
+const DefaultTopicRulesPath = "config/topics.yml"
+
+// TopicRule maps one topic to the keyword, regex, URL path and domain matchers that select it
+type TopicRule struct {
+	Topic    string   `yaml:"topic"`
+	Priority int      `yaml:"priority"`
+	Keywords []string `yaml:"keywords,omitempty"`  // whole-word, case-insensitive match on the title
+	Patterns []string `yaml:"patterns,omitempty"`  // regular expressions matched against the title
+	URLPaths []string `yaml:"url_paths,omitempty"` // substrings matched against the link path
+	Domains  []string `yaml:"domains,omitempty"`   // link host or any of its parent domains
+}
+
+// TopicRuleSet is the top-level structure of the topic rules YAML file
+type TopicRuleSet struct {
+	MaxLabels int         `yaml:"max_labels"` // 0 means no limit
+	Rules     []TopicRule `yaml:"rules"`
+}
+
+// compiledRule is a TopicRule with its matchers prepared for repeated use
+type compiledRule struct {
+	topic    string
+	priority int
+	title    []*regexp.Regexp
+	urlPaths []string
+	domains  []string
+}
+
+// Classifier tags articles with topics using a rule set
+type Classifier struct {
+	rules     []compiledRule
+	maxLabels int
+}
+
+// ClassificationReport summarizes how well the rule set covers a list of articles
+type ClassificationReport struct {
+	Total    int
+	Tagged   int
+	Coverage float64
+	ByTopic  map[string]int
+	Untagged []schema.ArticleMeta
+}
+
+// LoadTopicRules reads and parses a topic rules YAML file
+func LoadTopicRules(path string) (TopicRuleSet, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return TopicRuleSet{}, fmt.Errorf("unable to read topic rules %s: %w", path, err)
+	}
+
+	var ruleSet TopicRuleSet
+	if err := yaml.Unmarshal(content, &ruleSet); err != nil {
+		return TopicRuleSet{}, fmt.Errorf("failed to parse topic rules %s: %w", path, err)
+	}
+
+	return ruleSet, nil
+}
+
+// NewClassifier compiles a rule set into a Classifier
+func NewClassifier(ruleSet TopicRuleSet) (*Classifier, error) {
+	classifier := &Classifier{maxLabels: ruleSet.MaxLabels}
+
+	for i, rule := range ruleSet.Rules {
+		if strings.TrimSpace(rule.Topic) == "" {
+			return nil, fmt.Errorf("rule %d has no topic", i)
+		}
+
+		compiled := compiledRule{
+			topic:    rule.Topic,
+			priority: rule.Priority,
+		}
+
+		for _, keyword := range rule.Keywords {
+			keyword = strings.TrimSpace(keyword)
+			if keyword == "" {
+				continue
+			}
+			// Word boundaries are expressed as non-alphanumerics so keywords like "c++" still match
+			re := regexp.MustCompile(`(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(keyword) + `($|[^\pL\pN])`)
+			compiled.title = append(compiled.title, re)
+		}
+
+		for _, pattern := range rule.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q for topic %s: %w", pattern, rule.Topic, err)
+			}
+			compiled.title = append(compiled.title, re)
+		}
+
+		for _, path := range rule.URLPaths {
+			if path = strings.ToLower(strings.TrimSpace(path)); path != "" {
+				compiled.urlPaths = append(compiled.urlPaths, path)
+			}
+		}
+
+		for _, domain := range rule.Domains {
+			domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")
+			if domain != "" {
+				compiled.domains = append(compiled.domains, domain)
+			}
+		}
+
+		classifier.rules = append(classifier.rules, compiled)
+	}
+
+	return classifier, nil
+}
+
+// LoadClassifier reads a topic rules file and compiles it into a Classifier
+func LoadClassifier(path string) (*Classifier, error) {
+	ruleSet, err := LoadTopicRules(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClassifier(ruleSet)
+}
+
+// matches reports whether the rule selects the given title, host and path
+func (r compiledRule) matches(title, host, path string) bool {
+	for _, re := range r.title {
+		if re.MatchString(title) {
+			return true
+		}
+	}
+
+	for _, urlPath := range r.urlPaths {
+		if path != "" && strings.Contains(path, urlPath) {
+			return true
+		}
+	}
+
+	for _, domain := range r.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Classify returns the topics matched by an article, highest priority first
+func (c *Classifier) Classify(article schema.ArticleMeta) []string {
+	if c == nil {
+		return nil
+	}
+
+	var host, path string
+	if parsed, err := url.Parse(strings.TrimSpace(article.Link)); err == nil {
+		host = strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+		path = strings.ToLower(parsed.Path)
+	}
+
+	// Keep the highest priority seen for each topic
+	priorities := make(map[string]int)
+	for _, rule := range c.rules {
+		if !rule.matches(article.Title, host, path) {
+			continue
+		}
+		if current, exists := priorities[rule.topic]; !exists || rule.priority > current {
+			priorities[rule.topic] = rule.priority
+		}
+	}
+
+	if len(priorities) == 0 {
+		return nil
+	}
+
+	topics := make([]string, 0, len(priorities))
+	for topic := range priorities {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if priorities[topics[i]] != priorities[topics[j]] {
+			return priorities[topics[i]] > priorities[topics[j]]
+		}
+		return topics[i] < topics[j]
+	})
+
+	if c.maxLabels > 0 && len(topics) > c.maxLabels {
+		topics = topics[:c.maxLabels]
+	}
+
+	return topics
+}
+
+// Tag sets the Topics field on each article in place
+func (c *Classifier) Tag(articles []schema.ArticleMeta) {
+	for i := range articles {
+		articles[i].Topics = c.Classify(articles[i])
+	}
+}
+
+// BuildClassificationReport tags the articles and summarizes coverage per topic
+func BuildClassificationReport(articles []schema.ArticleMeta, classifier *Classifier) ClassificationReport {
+	report := ClassificationReport{
+		Total:   len(articles),
+		ByTopic: make(map[string]int),
+	}
+
+	for _, article := range articles {
+		topics := classifier.Classify(article)
+		if len(topics) == 0 {
+			report.Untagged = append(report.Untagged, article)
+			continue
+		}
+
+		report.Tagged++
+		for _, topic := range topics {
+			report.ByTopic[topic]++
+		}
+	}
+
+	if report.Total > 0 {
+		report.Coverage = (float64(report.Tagged) / float64(report.Total)) * 100
+	}
+
+	return report
+}
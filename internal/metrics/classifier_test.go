@@ -0,0 +1,285 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+func createTestRuleSet() TopicRuleSet {
+	return TopicRuleSet{
+		MaxLabels: 2,
+		Rules: []TopicRule{
+			{Topic: "Go", Priority: 20, Keywords: []string{"golang", "go"}},
+			{Topic: "C++", Priority: 20, Keywords: []string{"c++"}},
+			{Topic: "AI", Priority: 30, Patterns: []string{`(?i)\bllms?\b`}},
+			{Topic: "Career", Priority: 10, URLPaths: []string{"/career"}},
+			{Topic: "Engineering Blogs", Priority: 5, Domains: []string{"github.blog"}},
+		},
+	}
+}
+
+// ============================================================================
+// Classifier: Tags articles with topics from rules
+// ============================================================================
+
+func TestClassifierClassify(t *testing.T) {
+	classifier, err := NewClassifier(createTestRuleSet())
+	if err != nil {
+		t.Fatalf("NewClassifier() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		article  schema.ArticleMeta
+		expected []string
+	}{
+		{
+			name:     "keyword matches whole word case-insensitively",
+			article:  schema.ArticleMeta{Title: "Why GO is great"},
+			expected: []string{"Go"},
+		},
+		{
+			name:     "keyword does not match inside another word",
+			article:  schema.ArticleMeta{Title: "Going places with good habits"},
+			expected: nil,
+		},
+		{
+			name:     "keyword with symbols",
+			article:  schema.ArticleMeta{Title: "Modern C++ in practice"},
+			expected: []string{"C++"},
+		},
+		{
+			name:     "regex pattern on title",
+			article:  schema.ArticleMeta{Title: "Running LLMs locally"},
+			expected: []string{"AI"},
+		},
+		{
+			name:     "url path rule",
+			article:  schema.ArticleMeta{Title: "Notes", Link: "https://example.com/Career/growth"},
+			expected: []string{"Career"},
+		},
+		{
+			name:     "domain rule matches subdomains",
+			article:  schema.ArticleMeta{Title: "Notes", Link: "https://www.engineering.github.blog/post"},
+			expected: []string{"Engineering Blogs"},
+		},
+		{
+			name:     "multiple labels ordered by priority and capped",
+			article:  schema.ArticleMeta{Title: "Golang and LLM tooling", Link: "https://github.blog/career/x"},
+			expected: []string{"AI", "Go"},
+		},
+		{
+			name:     "no match",
+			article:  schema.ArticleMeta{Title: "Weekly newsletter", Link: "https://example.com/posts/1"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := classifier.Classify(tt.article)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Classify(%q) = %v, want %v", tt.article.Title, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShippedTopicRules(t *testing.T) {
+	classifier, err := LoadClassifier(filepath.Join("..", "..", DefaultTopicRulesPath))
+	if err != nil {
+		t.Fatalf("LoadClassifier() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		title  string
+		link   string
+		topic  string
+		tagged bool
+	}{
+		{title: "Let It Go: Deleting Old Code", topic: "Go"},
+		{title: "Where to go next in your career", topic: "Go"},
+		{title: "Ready, set, go", topic: "Go"},
+		{title: "Generics in Go 1.21", topic: "Go", tagged: true},
+		{title: "Writing a CLI in Go", topic: "Go", tagged: true},
+		{title: "Leaking goroutines", topic: "Go", tagged: true},
+		{title: "Release notes", link: "https://go.dev/blog/release", topic: "Go", tagged: true},
+		{title: "A prompt reply to every ticket", topic: "AI & Machine Learning"},
+		{title: "Change agents in large teams", topic: "AI & Machine Learning"},
+		{title: "Building AI agents that call tools", topic: "AI & Machine Learning", tagged: true},
+		{title: "Prompt engineering for code review", topic: "AI & Machine Learning", tagged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			topics := classifier.Classify(schema.ArticleMeta{Title: tt.title, Link: tt.link})
+			tagged := false
+			for _, topic := range topics {
+				tagged = tagged || topic == tt.topic
+			}
+			if tagged != tt.tagged {
+				t.Errorf("Classify(%q) = %v, tagged %s = %v, want %v", tt.title, topics, tt.topic, tagged, tt.tagged)
+			}
+		})
+	}
+}
+
+func TestNewClassifierErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ruleSet TopicRuleSet
+	}{
+		{
+			name:    "missing topic",
+			ruleSet: TopicRuleSet{Rules: []TopicRule{{Keywords: []string{"go"}}}},
+		},
+		{
+			name:    "invalid regex",
+			ruleSet: TopicRuleSet{Rules: []TopicRule{{Topic: "Bad", Patterns: []string{"("}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClassifier(tt.ruleSet); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestNilClassifier(t *testing.T) {
+	var classifier *Classifier
+	if topics := classifier.Classify(schema.ArticleMeta{Title: "Golang"}); topics != nil {
+		t.Errorf("expected nil topics from nil classifier, got %v", topics)
+	}
+}
+
+func TestClassifierTag(t *testing.T) {
+	classifier, _ := NewClassifier(createTestRuleSet())
+	articles := []schema.ArticleMeta{
+		{Title: "Golang tips"},
+		{Title: "Nothing to see"},
+	}
+
+	classifier.Tag(articles)
+
+	if !reflect.DeepEqual(articles[0].Topics, []string{"Go"}) {
+		t.Errorf("expected first article tagged Go, got %v", articles[0].Topics)
+	}
+	if articles[1].Topics != nil {
+		t.Errorf("expected second article untagged, got %v", articles[1].Topics)
+	}
+}
+
+func TestLoadTopicRules(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	t.Run("parses rules file", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "topics.yml")
+		content := `
+max_labels: 1
+rules:
+  - topic: Go
+    priority: 10
+    keywords: ["golang"]
+    domains: ["go.dev"]
+`
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		ruleSet, err := LoadTopicRules(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ruleSet.MaxLabels != 1 || len(ruleSet.Rules) != 1 {
+			t.Fatalf("unexpected rule set: %+v", ruleSet)
+		}
+		if ruleSet.Rules[0].Topic != "Go" || ruleSet.Rules[0].Domains[0] != "go.dev" {
+			t.Errorf("unexpected rule: %+v", ruleSet.Rules[0])
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadTopicRules(filepath.Join(tmpDir, "missing.yml")); err == nil {
+			t.Error("expected error for missing file, got nil")
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "bad.yml")
+		os.WriteFile(path, []byte("rules: [unclosed"), 0644)
+		if _, err := LoadClassifier(path); err == nil {
+			t.Error("expected error for invalid yaml, got nil")
+		}
+	})
+}
+
+func TestBuildClassificationReport(t *testing.T) {
+	classifier, _ := NewClassifier(createTestRuleSet())
+	articles := []schema.ArticleMeta{
+		{Title: "Golang tips"},
+		{Title: "LLM and Go"},
+		{Title: "Untitled musings"},
+		{Title: "Another one"},
+	}
+
+	report := BuildClassificationReport(articles, classifier)
+
+	if report.Total != 4 || report.Tagged != 2 {
+		t.Errorf("expected 2/4 tagged, got %d/%d", report.Tagged, report.Total)
+	}
+	if report.Coverage != 50.0 {
+		t.Errorf("expected coverage 50.0, got %.1f", report.Coverage)
+	}
+	if report.ByTopic["Go"] != 2 || report.ByTopic["AI"] != 1 {
+		t.Errorf("unexpected topic counts: %v", report.ByTopic)
+	}
+	if len(report.Untagged) != 2 || report.Untagged[0].Title != "Untitled musings" {
+		t.Errorf("unexpected untagged list: %v", report.Untagged)
+	}
+}
+
+func TestProcessArticleRowsWithClassifier(t *testing.T) {
+	classifier, _ := NewClassifier(createTestRuleSet())
+	rows := [][]interface{}{
+		{"Date", "Title", "Link", "Category", "Read"},
+		{"2025-12-10", "Golang generics", "https://example.com/a", "Substack", "TRUE"},
+		{"2025-12-11", "Go and LLMs", "https://example.com/b", "Substack", "FALSE"},
+		{"2025-12-12", "Misc", "https://example.com/c", "GitHub", "FALSE"},
+	}
+
+	metrics := schema.Metrics{
+		BySource:                     make(map[string]int),
+		BySourceReadStatus:           make(map[string][2]int),
+		ByYear:                       make(map[string]int),
+		ByMonth:                      make(map[string]int),
+		ByYearAndMonth:               make(map[string]map[string]int),
+		ByMonthAndSource:             make(map[string]map[string][2]int),
+		ByCategory:                   make(map[string][2]int),
+		UnreadByMonth:                make(map[string]int),
+		UnreadByCategory:             make(map[string]int),
+		UnreadBySource:               make(map[string]int),
+		UnreadByYear:                 make(map[string]int),
+		UnreadArticleAgeDistribution: make(map[string]int),
+	}
+
+	var earliestDate, latestDate time.Time
+	unread, _ := processArticleRows(rows, &metrics, &earliestDate, &latestDate, rowProcessingOptions{classifier: classifier})
+
+	if metrics.ByTopic["Go"] != [2]int{1, 1} {
+		t.Errorf("expected Go topic [1 1], got %v", metrics.ByTopic["Go"])
+	}
+	if metrics.ByTopic["AI"] != [2]int{0, 1} {
+		t.Errorf("expected AI topic [0 1], got %v", metrics.ByTopic["AI"])
+	}
+	if len(unread) != 2 || !reflect.DeepEqual(unread[0].Topics, []string{"AI", "Go"}) {
+		t.Errorf("expected unread articles to carry topics, got %+v", unread)
+	}
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseAsOf parses a -date flag value (YYYY-MM-DD) into a UTC date.
+// An empty value returns the zero time, which every as-of parameter treats as "now".
+func ParseAsOf(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	asOf, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid as-of date %q: expected YYYY-MM-DD", value)
+	}
+	return asOf, nil
+}
+
+// resolveAsOf returns asOf, or the current time when asOf is zero
+func resolveAsOf(asOf time.Time) time.Time {
+	if asOf.IsZero() {
+		return time.Now()
+	}
+	return asOf
+}
+
+// snapshotDate returns the day a snapshot taken at asOf describes, as midnight UTC; a zero asOf
+// means now. Ages, future dates and source health are measured against it rather than the time
+// of day the fetch ran.
+func snapshotDate(asOf time.Time) time.Time {
+	return snapshotDay(resolveAsOf(asOf))
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAsOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{"empty means now", "", time.Time{}, false},
+		{"valid date", "2025-06-30", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), false},
+		{"surrounding whitespace", " 2025-06-30 ", time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC), false},
+		{"wrong format", "30/06/2025", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseAsOf(tt.value)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ParseAsOf(%q) error = %v, expectError %v", tt.value, err, tt.expectError)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("ParseAsOf(%q) = %v, want %v", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveAsOf(t *testing.T) {
+	fixed := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	if result := resolveAsOf(fixed); !result.Equal(fixed) {
+		t.Errorf("resolveAsOf(fixed) = %v, want %v", result, fixed)
+	}
+	if result := resolveAsOf(time.Time{}); time.Since(result) > time.Minute {
+		t.Errorf("resolveAsOf(zero) = %v, want the current time", result)
+	}
+}
@@ -0,0 +1,241 @@
+package metrics
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+const (
+	// DuplicateTitleThreshold is the minimum title word overlap (Jaccard) for two articles to be merged
+	DuplicateTitleThreshold = 0.85
+
+	// DuplicateTitleMinWords keeps short, generic titles ("Weekly update") from being fuzzy-matched
+	DuplicateTitleMinWords = 3
+
+	// DuplicateTitleWindow bounds how far apart syndicated copies of a post can be dated
+	DuplicateTitleWindow = 30 * 24 * time.Hour
+
+	// Merge reasons recorded in the duplicate audit
+	DuplicateReasonSameURL      = "same_url"
+	DuplicateReasonSimilarTitle = "similar_title"
+)
+
+// trackingParams are query parameters that identify a campaign or referrer rather than content on
+// any site, next to the utm_* and mc_* prefixes
+var trackingParams = map[string]bool{
+	"ref": true, "ref_src": true, "fbclid": true, "gclid": true,
+}
+
+// substackTrackingParams are added by Substack share and email links. Elsewhere the same names
+// can select content (a post_id or page source), so they are only stripped on Substack hosts.
+var substackTrackingParams = map[string]bool{
+	"s": true, "r": true, "source": true, "post_id": true, "publication_id": true,
+	"isfreemail": true, "triedredirect": true, "sd": true,
+}
+
+// isTrackingParam reports whether a query parameter on host can be dropped when comparing links
+func isTrackingParam(key, host string) bool {
+	key = strings.ToLower(key)
+	if strings.HasPrefix(key, "utm_") || strings.HasPrefix(key, "mc_") || trackingParams[key] {
+		return true
+	}
+	return substackTrackingParams[key] && (host == substackDomain || strings.HasSuffix(host, "."+substackDomain))
+}
+
+// CanonicalizeURL reduces an article link to a comparable form: https scheme, lowercase host
+// without "www.", no fragment, no trailing slash and no tracking query parameters (Substack share
+// parameters on Substack hosts only).
+// Links that cannot be parsed are returned trimmed and lowercased.
+func CanonicalizeURL(link string) string {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Host == "" {
+		return strings.ToLower(link)
+	}
+
+	host := normalizeHost(parsed.Host)
+	query := parsed.Query()
+	for key := range query {
+		if isTrackingParam(key, host) {
+			query.Del(key)
+		}
+	}
+
+	canonical := "https://" + host + strings.TrimRight(parsed.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		canonical += "?" + encoded
+	}
+	return canonical
+}
+
+// titleWords splits a title into lowercase words, ignoring punctuation
+func titleWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// titleSimilarity returns the Jaccard overlap of two titles' word sets
+func titleSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// duplicateCandidate is one valid article row considered during duplicate detection
+type duplicateCandidate struct {
+	index      int // position in the scanned rows
+	row        int // 1-based sheet row number
+	article    schema.ArticleMeta
+	date       time.Time
+	words      map[string]bool
+	reason     string  // how the candidate joined its group; empty for the group's first member
+	similarity float64 // title similarity when reason is similar_title
+}
+
+// duplicateDetection is the outcome of scanning the articles sheet for duplicates
+type duplicateDetection struct {
+	skip   map[int]bool // rows index (0-based) -> excluded from aggregates
+	merges []schema.DuplicateMerge
+}
+
+// detectDuplicates groups rows that refer to the same post, either by canonical URL or by a
+// near-identical title dated within DuplicateTitleWindow. Each group keeps one row: the first
+// read copy if any copy was read, otherwise the first row. Every other row is skipped.
+// sheetRows maps rows to sheet row numbers as in buildSnapshot; nil means rows is the sheet.
+func detectDuplicates(rows [][]interface{}, sheetRows []int, sourceMap map[string]string) duplicateDetection {
+	detection := duplicateDetection{skip: make(map[int]bool)}
+
+	var groups [][]*duplicateCandidate
+	groupByURL := make(map[string]int)
+
+	// Skip header row and group valid rows
+	for i := 1; i < len(rows); i++ {
+		article, err := parseArticleRowWithDetails(rows[i], sourceMap)
+		if err != nil {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", article.Date)
+		if err != nil {
+			continue
+		}
+
+		candidate := &duplicateCandidate{index: i, row: sheetRowNumber(sheetRows, i), article: *article, date: date, words: titleWords(article.Title)}
+		canonical := CanonicalizeURL(article.Link)
+
+		group := -1
+		if index, exists := groupByURL[canonical]; exists && canonical != "" {
+			group = index
+			candidate.reason = DuplicateReasonSameURL
+		} else if len(candidate.words) >= DuplicateTitleMinWords {
+			group, candidate.similarity = findSimilarTitleGroup(groups, candidate)
+			if group >= 0 {
+				candidate.reason = DuplicateReasonSimilarTitle
+			}
+		}
+
+		if group < 0 {
+			group = len(groups)
+			groups = append(groups, nil)
+		}
+		groups[group] = append(groups[group], candidate)
+		if canonical != "" {
+			if _, exists := groupByURL[canonical]; !exists {
+				groupByURL[canonical] = group
+			}
+		}
+	}
+
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+
+		keeper := group[0]
+		for _, candidate := range group {
+			if candidate.article.Read {
+				keeper = candidate
+				break
+			}
+		}
+
+		for _, candidate := range group {
+			if candidate == keeper {
+				continue
+			}
+			detection.skip[candidate.index] = true
+
+			// The group's first member has no join reason; describe the merge from the keeper's side
+			reason, similarity := candidate.reason, candidate.similarity
+			if reason == "" {
+				reason, similarity = keeper.reason, keeper.similarity
+			}
+
+			detection.merges = append(detection.merges, schema.DuplicateMerge{
+				KeptRow:       keeper.row,
+				DroppedRow:    candidate.row,
+				Reason:        reason,
+				Similarity:    similarity,
+				KeptTitle:     keeper.article.Title,
+				DroppedTitle:  candidate.article.Title,
+				KeptLink:      keeper.article.Link,
+				DroppedLink:   candidate.article.Link,
+				KeptSource:    keeper.article.Category,
+				DroppedSource: candidate.article.Category,
+			})
+		}
+	}
+
+	sort.Slice(detection.merges, func(i, j int) bool {
+		return detection.merges[i].DroppedRow < detection.merges[j].DroppedRow
+	})
+
+	return detection
+}
+
+// findSimilarTitleGroup returns the group holding the most similar title within the date window
+func findSimilarTitleGroup(groups [][]*duplicateCandidate, candidate *duplicateCandidate) (int, float64) {
+	best, bestScore := -1, 0.0
+
+	for index, group := range groups {
+		for _, member := range group {
+			if len(member.words) < DuplicateTitleMinWords {
+				continue
+			}
+			gap := candidate.date.Sub(member.date)
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap > DuplicateTitleWindow {
+				continue
+			}
+
+			if score := titleSimilarity(candidate.words, member.words); score >= DuplicateTitleThreshold && score > bestScore {
+				best, bestScore = index, score
+			}
+		}
+	}
+
+	return best, bestScore
+}
@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// ============================================================================
+// CanonicalizeURL: Normalizes links for duplicate comparison
+// ============================================================================
+
+func TestCanonicalizeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{
+			name:     "strips utm parameters",
+			link:     "https://example.com/post?utm_source=rss&utm_medium=feed",
+			expected: "https://example.com/post",
+		},
+		{
+			name:     "keeps content parameters",
+			link:     "https://example.com/view?id=42&ref=newsletter",
+			expected: "https://example.com/view?id=42",
+		},
+		{
+			name:     "normalizes scheme, host, fragment and trailing slash",
+			link:     "http://WWW.Example.com/post/#comments",
+			expected: "https://example.com/post",
+		},
+		{
+			name:     "substack share link",
+			link:     "https://writer.substack.com/p/title?r=abc12&s=w&triedRedirect=true",
+			expected: "https://writer.substack.com/p/title",
+		},
+		{
+			name:     "strips mailchimp and click ids",
+			link:     "https://example.com/post?mc_cid=1&mc_eid=2&fbclid=x&gclid=y&ref_src=twsrc",
+			expected: "https://example.com/post",
+		},
+		{
+			name:     "keeps substack parameter names elsewhere",
+			link:     "https://example.com/view?post_id=42&source=feed&s=3",
+			expected: "https://example.com/view?post_id=42&s=3&source=feed",
+		},
+		{
+			name:     "substack share parameters on substack.com",
+			link:     "https://substack.com/@writer/p-123?post_id=123&publication_id=9&r=abc",
+			expected: "https://substack.com/@writer/p-123",
+		},
+		{
+			name:     "empty link",
+			link:     "  ",
+			expected: "",
+		},
+		{
+			name:     "not a url",
+			link:     "Some Title",
+			expected: "some title",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := CanonicalizeURL(tt.link); result != tt.expected {
+				t.Errorf("CanonicalizeURL(%q) = %q, want %q", tt.link, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		atLeast float64
+		below   float64
+	}{
+		{
+			name:    "syndicated title with site suffix",
+			a:       "How to Build a REST API in Go",
+			b:       "How to Build a REST API in Go | freeCodeCamp",
+			atLeast: DuplicateTitleThreshold,
+			below:   1.01,
+		},
+		{
+			name:    "punctuation and case are ignored",
+			a:       "Understanding Go's Interfaces!",
+			b:       "understanding go s interfaces",
+			atLeast: 1,
+			below:   1.01,
+		},
+		{
+			name:    "different posts",
+			a:       "How to Build a REST API in Go",
+			b:       "How to Test a REST API in Python",
+			atLeast: 0,
+			below:   DuplicateTitleThreshold,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := titleSimilarity(titleWords(tt.a), titleWords(tt.b))
+			if score < tt.atLeast || score >= tt.below {
+				t.Errorf("titleSimilarity(%q, %q) = %.2f, want [%.2f, %.2f)", tt.a, tt.b, score, tt.atLeast, tt.below)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// detectDuplicates: Groups copies of the same post and picks one to keep
+// ============================================================================
+
+func TestDetectDuplicates(t *testing.T) {
+	rows := [][]interface{}{
+		{"Date", "Title", "Link", "Category", "Read"},
+		{"2025-12-01", "Scaling Postgres at Work", "https://example.com/postgres", "GitHub", "FALSE"},
+		{"2025-12-02", "Scaling Postgres at Work", "https://example.com/postgres?utm_source=rss", "GitHub", "TRUE"},
+		{"2025-12-03", "How to Build a REST API in Go", "https://freecodecamp.org/news/rest-api-go", "freeCodeCamp", "FALSE"},
+		{"2025-12-05", "How to Build a REST API in Go | freeCodeCamp", "https://writer.substack.com/p/rest-api-go", "Substack", "FALSE"},
+		{"2025-12-06", "Weekly update", "https://a.example.com/1", "Substack", "FALSE"},
+		{"2025-12-13", "Weekly update", "https://b.example.com/2", "Substack", "FALSE"},
+		{"2026-06-01", "How to Build a REST API in Go", "https://freecodecamp.org/news/rest-api-go-2026", "freeCodeCamp", "FALSE"},
+		{"bad-date", "Scaling Postgres at Work", "https://example.com/postgres", "GitHub", "FALSE"},
+	}
+
+	detection := detectDuplicates(rows, nil, nil)
+
+	expectedSkip := map[int]bool{1: true, 4: true}
+	if len(detection.skip) != len(expectedSkip) {
+		t.Errorf("expected skipped rows %v, got %v", expectedSkip, detection.skip)
+	}
+	for index := range expectedSkip {
+		if !detection.skip[index] {
+			t.Errorf("expected rows index %d to be skipped, got %v", index, detection.skip)
+		}
+	}
+
+	if len(detection.merges) != 2 {
+		t.Fatalf("expected 2 merges, got %+v", detection.merges)
+	}
+
+	// The read copy is kept even though it came second
+	urlMerge := detection.merges[0]
+	if urlMerge.KeptRow != 3 || urlMerge.DroppedRow != 2 || urlMerge.Reason != DuplicateReasonSameURL {
+		t.Errorf("unexpected url merge: %+v", urlMerge)
+	}
+
+	titleMerge := detection.merges[1]
+	if titleMerge.KeptRow != 4 || titleMerge.DroppedRow != 5 || titleMerge.Reason != DuplicateReasonSimilarTitle {
+		t.Errorf("unexpected title merge: %+v", titleMerge)
+	}
+	if titleMerge.KeptSource != "freeCodeCamp" || titleMerge.DroppedSource != "Substack" || titleMerge.Similarity < DuplicateTitleThreshold {
+		t.Errorf("unexpected title merge details: %+v", titleMerge)
+	}
+}
+
+func TestDetectDuplicatesKeepsDistinctQueryParams(t *testing.T) {
+	// Outside Substack post_id selects the article, so these are two different posts
+	rows := [][]interface{}{
+		{"Date", "Title", "Link", "Category", "Read"},
+		{"2025-12-01", "Release notes", "https://example.com/view?post_id=41", "GitHub", "FALSE"},
+		{"2025-12-08", "Team news", "https://example.com/view?post_id=42", "GitHub", "FALSE"},
+	}
+
+	detection := detectDuplicates(rows, nil, nil)
+	if len(detection.skip) != 0 || len(detection.merges) != 0 {
+		t.Errorf("expected no merges, got %+v", detection.merges)
+	}
+}
+
+func TestProcessArticleRowsSkipsDuplicates(t *testing.T) {
+	rows := [][]interface{}{
+		{"Date", "Title", "Link", "Category", "Read"},
+		{"2025-12-01", "Scaling Postgres at Work", "https://example.com/postgres", "GitHub", "FALSE"},
+		{"2025-12-02", "Scaling Postgres at Work", "https://example.com/postgres?utm_source=rss", "GitHub", "TRUE"},
+		{"2025-12-03", "Another post entirely", "https://example.com/other", "GitHub", "FALSE"},
+	}
+
+	metrics := schema.Metrics{
+		BySource:                     make(map[string]int),
+		BySourceReadStatus:           make(map[string][2]int),
+		ByYear:                       make(map[string]int),
+		ByMonth:                      make(map[string]int),
+		ByYearAndMonth:               make(map[string]map[string]int),
+		ByMonthAndSource:             make(map[string]map[string][2]int),
+		ByCategory:                   make(map[string][2]int),
+		UnreadByMonth:                make(map[string]int),
+		UnreadByCategory:             make(map[string]int),
+		UnreadBySource:               make(map[string]int),
+		UnreadByYear:                 make(map[string]int),
+		UnreadArticleAgeDistribution: make(map[string]int),
+	}
+
+	detection := detectDuplicates(rows, nil, nil)
+	var earliestDate, latestDate time.Time
+	processArticleRows(rows, &metrics, &earliestDate, &latestDate, rowProcessingOptions{skipRows: detection.skip})
+
+	if metrics.TotalArticles != 2 {
+		t.Errorf("expected 2 articles after merging, got %d", metrics.TotalArticles)
+	}
+	if metrics.ReadCount != 1 || metrics.UnreadCount != 1 {
+		t.Errorf("expected 1 read / 1 unread, got %d / %d", metrics.ReadCount, metrics.UnreadCount)
+	}
+	if metrics.BySource["GitHub"] != 2 {
+		t.Errorf("expected GitHub counted twice, got %d", metrics.BySource["GitHub"])
+	}
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"sort"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// DiffSnapshots compares two snapshots metric by metric: the headline figures first, then the
+// read and unread counts of every source in either snapshot. Rows for sources whose counts did
+// not change are left out.
+func DiffSnapshots(from, to schema.Metrics) QueryResult {
+	result := QueryResult{Columns: []string{"metric", "from", "to", "change"}}
+	addInt := func(metric string, a, b int) {
+		result.Rows = append(result.Rows, []any{metric, a, b, b - a})
+	}
+
+	addInt("total_articles", from.TotalArticles, to.TotalArticles)
+	addInt("read", from.ReadCount, to.ReadCount)
+	addInt("unread", from.UnreadCount, to.UnreadCount)
+	result.Rows = append(result.Rows, []any{"read_rate", from.ReadRate, to.ReadRate, to.ReadRate - from.ReadRate})
+
+	sources := make(map[string]bool)
+	for _, m := range []schema.Metrics{from, to} {
+		for source := range m.BySourceReadStatus {
+			if source != "substack_author_count" {
+				sources[source] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(sources))
+	for source := range sources {
+		names = append(names, source)
+	}
+	sort.Strings(names)
+
+	for _, source := range names {
+		a, b := from.BySourceReadStatus[source], to.BySourceReadStatus[source]
+		if a == b {
+			continue
+		}
+		addInt(source+" read", a[0], b[0])
+		addInt(source+" unread", a[1], b[1])
+	}
+	return result
+}
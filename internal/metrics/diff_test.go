@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	from := digestSnapshot("2026-03-02", 100, 40, map[string][2]int{"GitHub": {30, 30}, "Stripe": {10, 30}}, nil)
+	to := digestSnapshot("2026-03-09", 105, 42, map[string][2]int{"GitHub": {32, 30}, "Stripe": {10, 30}, "Netflix": {0, 3}}, nil)
+
+	result := DiffSnapshots(from, to)
+	var metrics []string
+	for _, row := range result.Rows {
+		metrics = append(metrics, row[0].(string))
+	}
+	expected := "total_articles,read,unread,read_rate,GitHub read,GitHub unread,Netflix read,Netflix unread"
+	if strings.Join(metrics, ",") != expected {
+		t.Errorf("expected rows %s, got %s", expected, strings.Join(metrics, ","))
+	}
+	if result.Rows[1][3] != 2 || result.Rows[7][3] != 3 {
+		t.Errorf("unexpected changes: %v", result.Rows)
+	}
+}
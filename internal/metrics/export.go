@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// Export dimensions of the long-format breakdown table
+const (
+	DimensionSource    = "source"
+	DimensionYear      = "year"
+	DimensionMonth     = "month"      // calendar month (01-12) across all years
+	DimensionAgeBucket = "age_bucket" // unread articles only, so read is always 0
+	DimensionCategory  = "category"
+)
+
+// BreakdownCSVHeader is the header of the long-format breakdown table
+var BreakdownCSVHeader = []string{"snapshot_date", "dimension", "key", "read", "unread"}
+
+// SummaryCSVHeader is the header of the wide summary table
+var SummaryCSVHeader = []string{
+	"snapshot_date", "total_articles", "read", "unread", "read_rate", "avg_articles_per_month",
+	"sources", "duplicates_merged", "reconstructed", "has_ai_analysis",
+}
+
+// BreakdownRow is one key of one dimension in a snapshot
+type BreakdownRow struct {
+	Dimension string
+	Key       string
+	Read      int
+	Unread    int
+}
+
+// sortedReadStatusRows turns a key -> [read, unread] map into breakdown rows sorted by key
+func sortedReadStatusRows(dimension string, counts map[string][2]int) []BreakdownRow {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := make([]BreakdownRow, 0, len(keys))
+	for _, key := range keys {
+		rows = append(rows, BreakdownRow{Dimension: dimension, Key: key, Read: counts[key][0], Unread: counts[key][1]})
+	}
+	return rows
+}
+
+// SnapshotBreakdown flattens a snapshot's source, year, month, age bucket and category maps into
+// rows, dimension by dimension
+func SnapshotBreakdown(m schema.Metrics) []BreakdownRow {
+	bySource := make(map[string][2]int, len(m.BySourceReadStatus))
+	for source, counts := range m.BySourceReadStatus {
+		if source != "substack_author_count" {
+			bySource[source] = counts
+		}
+	}
+	byYear := make(map[string][2]int, len(m.ByYear))
+	for year, total := range m.ByYear {
+		byYear[year] = [2]int{total - m.UnreadByYear[year], m.UnreadByYear[year]}
+	}
+	byMonth := make(map[string][2]int, len(m.ByMonth))
+	for month, total := range m.ByMonth {
+		byMonth[month] = [2]int{total - m.UnreadByMonth[month], m.UnreadByMonth[month]}
+	}
+
+	var rows []BreakdownRow
+	rows = append(rows, sortedReadStatusRows(DimensionSource, bySource)...)
+	rows = append(rows, sortedReadStatusRows(DimensionYear, byYear)...)
+	rows = append(rows, sortedReadStatusRows(DimensionMonth, byMonth)...)
+
+	// Age buckets keep the order the snapshot declares
+	buckets := m.AgeBuckets
+	if len(buckets) == 0 {
+		buckets = DefaultAgeBuckets()
+	}
+	for _, bucket := range buckets {
+		rows = append(rows, BreakdownRow{Dimension: DimensionAgeBucket, Key: bucket.Key, Unread: m.UnreadArticleAgeDistribution[bucket.Key]})
+	}
+
+	rows = append(rows, sortedReadStatusRows(DimensionCategory, m.ByCategory)...)
+	return rows
+}
+
+// WriteBreakdownCSV writes every snapshot as tidy long-format rows:
+// snapshot_date, dimension, key, read, unread
+func WriteBreakdownCSV(w io.Writer, series []schema.Metrics) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(BreakdownCSVHeader); err != nil {
+		return fmt.Errorf("failed to write breakdown header: %w", err)
+	}
+	for _, m := range series {
+		date := snapshotDay(m.LastUpdated).Format("2006-01-02")
+		for _, row := range SnapshotBreakdown(m) {
+			record := []string{date, row.Dimension, row.Key, strconv.Itoa(row.Read), strconv.Itoa(row.Unread)}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write breakdown rows: %w", err)
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteSummaryCSV writes one wide row of headline figures per snapshot
+func WriteSummaryCSV(w io.Writer, series []schema.Metrics) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(SummaryCSVHeader); err != nil {
+		return fmt.Errorf("failed to write summary header: %w", err)
+	}
+	for _, m := range series {
+		sources := 0
+		for source := range m.BySource {
+			if source != "substack_author_count" {
+				sources++
+			}
+		}
+		row := []string{
+			snapshotDay(m.LastUpdated).Format("2006-01-02"),
+			strconv.Itoa(m.TotalArticles),
+			strconv.Itoa(m.ReadCount),
+			strconv.Itoa(m.UnreadCount),
+			strconv.FormatFloat(m.ReadRate, 'f', 2, 64),
+			strconv.FormatFloat(m.AvgArticlesPerMonth, 'f', 2, 64),
+			strconv.Itoa(sources),
+			strconv.Itoa(m.DuplicatesMerged),
+			strconv.FormatBool(m.Reconstructed != nil),
+			strconv.FormatBool(m.AIDeltaAnalysis != ""),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write summary row: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+func exportSnapshot() schema.Metrics {
+	return schema.Metrics{
+		LastUpdated:   time.Date(2026, 1, 9, 15, 0, 0, 0, time.UTC),
+		TotalArticles: 10,
+		ReadCount:     4,
+		UnreadCount:   6,
+		ReadRate:      40,
+		BySource:      map[string]int{"GitHub": 7, "Stripe": 3, "substack_author_count": 2},
+		BySourceReadStatus: map[string][2]int{
+			"Stripe":                {1, 2},
+			"GitHub":                {3, 4},
+			"substack_author_count": {2, 0},
+		},
+		ByYear:                       map[string]int{"2025": 7, "2026": 3},
+		UnreadByYear:                 map[string]int{"2025": 5, "2026": 1},
+		ByMonth:                      map[string]int{"01": 10},
+		UnreadByMonth:                map[string]int{"01": 6},
+		ByCategory:                   map[string][2]int{"GitHub": {3, 4}, "Stripe": {1, 2}},
+		AgeBuckets:                   []schema.AgeBucket{{Key: "fresh"}, {Key: "stale", MinAge: "1y"}},
+		UnreadArticleAgeDistribution: map[string]int{"fresh": 2, "stale": 4},
+	}
+}
+
+func TestWriteBreakdownCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteBreakdownCSV(&buf, []schema.Metrics{exportSnapshot()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"snapshot_date,dimension,key,read,unread",
+		"2026-01-09,source,GitHub,3,4",
+		"2026-01-09,source,Stripe,1,2",
+		"2026-01-09,year,2025,2,5",
+		"2026-01-09,year,2026,2,1",
+		"2026-01-09,month,01,4,6",
+		"2026-01-09,age_bucket,fresh,0,2",
+		"2026-01-09,age_bucket,stale,0,4",
+		"2026-01-09,category,GitHub,3,4",
+		"2026-01-09,category,Stripe,1,2",
+	}, "\n") + "\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected breakdown CSV:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteSummaryCSV(t *testing.T) {
+	older := exportSnapshot()
+	older.LastUpdated = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	older.Reconstructed = &schema.Reconstruction{}
+	latest := exportSnapshot()
+	latest.AIDeltaAnalysis = "Reading picked up."
+	latest.DuplicatesMerged = 2
+
+	var buf bytes.Buffer
+	if err := WriteSummaryCSV(&buf, []schema.Metrics{older, latest}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[0] != strings.Join(SummaryCSVHeader, ",") {
+		t.Fatalf("unexpected summary CSV:\n%s", buf.String())
+	}
+	if lines[1] != "2026-01-02,10,4,6,40.00,0.00,2,0,true,false" {
+		t.Errorf("unexpected first row: %s", lines[1])
+	}
+	if lines[2] != "2026-01-09,10,4,6,40.00,0.00,2,2,false,true" {
+		t.Errorf("unexpected second row: %s", lines[2])
+	}
+}
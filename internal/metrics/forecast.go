@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// maxProjectionYears caps how far out a zero-backlog date is projected
+const maxProjectionYears = 20
+
+// DefaultForecastTarget is the date the backlog should be cleared by when none is configured:
+// the end of the snapshot's year
+func DefaultForecastTarget(asOf time.Time) time.Time {
+	return time.Date(asOf.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+}
+
+// snapshotDay truncates a snapshot timestamp to its UTC calendar date
+func snapshotDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CalculateBacklogForecast projects the unread backlog from a chronological series of snapshots
+// (oldest first, the current snapshot last). It returns nil when the series spans less than a day.
+func CalculateBacklogForecast(snapshots []schema.Metrics, targetDate time.Time) *schema.BacklogForecast {
+	if len(snapshots) < 2 {
+		return nil
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	firstDay, lastDay := snapshotDay(first.LastUpdated), snapshotDay(last.LastUpdated)
+	spanWeeks := lastDay.Sub(firstDay).Hours() / 24 / 7
+	if spanWeeks <= 0 {
+		return nil
+	}
+
+	forecast := &schema.BacklogForecast{
+		TargetDate:         targetDate.Format("2006-01-02"),
+		WeeksObserved:      spanWeeks,
+		AvgWeeklyNetChange: float64(last.UnreadCount-first.UnreadCount) / spanWeeks,
+		AvgWeeklyAdded:     float64(last.TotalArticles-first.TotalArticles) / spanWeeks,
+		AvgWeeklyReads:     float64(last.ReadCount-first.ReadCount) / spanWeeks,
+		CurrentUnread:      last.UnreadCount,
+	}
+
+	// Least-squares fit of unread count against weeks since the first snapshot
+	var sumX, sumY, sumXY, sumXX float64
+	n := float64(len(snapshots))
+	for _, snapshot := range snapshots {
+		x := snapshotDay(snapshot.LastUpdated).Sub(firstDay).Hours() / 24 / 7
+		y := float64(snapshot.UnreadCount)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+
+		forecast.History = append(forecast.History, schema.BacklogPoint{
+			Date:   snapshotDay(snapshot.LastUpdated).Format("2006-01-02"),
+			Unread: snapshot.UnreadCount,
+		})
+	}
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		forecast.TrendWeeklyChange = (n*sumXY - sumX*sumY) / denominator
+	}
+
+	// Project the zero-backlog date only while the trend is shrinking the backlog
+	if forecast.TrendWeeklyChange < 0 {
+		weeksToZero := float64(last.UnreadCount) / -forecast.TrendWeeklyChange
+		zeroDate := lastDay.Add(time.Duration(weeksToZero * 7 * 24 * float64(time.Hour)))
+		if zeroDate.Before(lastDay.AddDate(maxProjectionYears, 0, 0)) {
+			forecast.ProjectedZeroDate = zeroDate.Format("2006-01-02")
+		}
+	}
+
+	// Clearing by the target means reading the backlog plus everything still arriving
+	if weeksLeft := targetDate.Sub(lastDay).Hours() / 24 / 7; weeksLeft > 0 {
+		addedPerWeek := forecast.AvgWeeklyAdded
+		if addedPerWeek < 0 {
+			addedPerWeek = 0
+		}
+		forecast.RequiredWeeklyReads = float64(last.UnreadCount)/weeksLeft + addedPerWeek
+	}
+
+	return forecast
+}
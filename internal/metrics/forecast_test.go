@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// createTestBacklogSeries builds weekly snapshots starting 2026-01-02 with the given unread counts.
+// Each week adds 5 articles; reads make up the rest of the change.
+func createTestBacklogSeries(unread ...int) []schema.Metrics {
+	start := time.Date(2026, 1, 2, 9, 30, 0, 0, time.UTC)
+	snapshots := make([]schema.Metrics, len(unread))
+	for i, count := range unread {
+		total := 500 + 5*i
+		snapshots[i] = schema.Metrics{
+			TotalArticles: total,
+			UnreadCount:   count,
+			ReadCount:     total - count,
+			LastUpdated:   start.AddDate(0, 0, 7*i),
+		}
+	}
+	return snapshots
+}
+
+// ============================================================================
+// CalculateBacklogForecast: Projects the unread backlog from snapshot history
+// ============================================================================
+
+func TestCalculateBacklogForecast(t *testing.T) {
+	target := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("shrinking backlog projects a zero date", func(t *testing.T) {
+		forecast := CalculateBacklogForecast(createTestBacklogSeries(100, 90, 80, 70), target)
+		if forecast == nil {
+			t.Fatal("expected a forecast")
+		}
+
+		if forecast.WeeksObserved != 3 || forecast.CurrentUnread != 70 {
+			t.Errorf("unexpected span/current: %+v", forecast)
+		}
+		if forecast.AvgWeeklyNetChange != -10 || forecast.TrendWeeklyChange != -10 {
+			t.Errorf("expected -10/week, got avg %.2f trend %.2f", forecast.AvgWeeklyNetChange, forecast.TrendWeeklyChange)
+		}
+		if forecast.AvgWeeklyAdded != 5 || forecast.AvgWeeklyReads != 15 {
+			t.Errorf("expected 5 added / 15 read per week, got %.2f / %.2f", forecast.AvgWeeklyAdded, forecast.AvgWeeklyReads)
+		}
+
+		// 70 unread at 10/week from 2026-01-23 is 7 weeks later
+		if forecast.ProjectedZeroDate != "2026-03-13" {
+			t.Errorf("expected zero date 2026-03-13, got %s", forecast.ProjectedZeroDate)
+		}
+		if len(forecast.History) != 4 || forecast.History[0].Date != "2026-01-02" {
+			t.Errorf("unexpected history: %+v", forecast.History)
+		}
+	})
+
+	t.Run("growing backlog has no zero date", func(t *testing.T) {
+		forecast := CalculateBacklogForecast(createTestBacklogSeries(100, 105, 110), target)
+		if forecast.ProjectedZeroDate != "" {
+			t.Errorf("expected no zero date, got %s", forecast.ProjectedZeroDate)
+		}
+		if forecast.TrendWeeklyChange != 5 {
+			t.Errorf("expected +5/week trend, got %.2f", forecast.TrendWeeklyChange)
+		}
+	})
+
+	t.Run("required weekly reads to hit the target", func(t *testing.T) {
+		// 2026-01-16 to 2026-12-31 is 50 weeks; 100 unread plus 5 added per week
+		forecast := CalculateBacklogForecast(createTestBacklogSeries(100, 100, 100), target)
+		expected := 100.0/50 + 5
+		if math.Abs(forecast.RequiredWeeklyReads-expected) > 0.01 {
+			t.Errorf("expected %.2f required weekly reads, got %.2f", expected, forecast.RequiredWeeklyReads)
+		}
+	})
+
+	t.Run("target already passed", func(t *testing.T) {
+		forecast := CalculateBacklogForecast(createTestBacklogSeries(100, 90), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+		if forecast.RequiredWeeklyReads != 0 {
+			t.Errorf("expected no requirement for a past target, got %.2f", forecast.RequiredWeeklyReads)
+		}
+	})
+
+	t.Run("not enough history", func(t *testing.T) {
+		if forecast := CalculateBacklogForecast(createTestBacklogSeries(100), target); forecast != nil {
+			t.Errorf("expected nil forecast for one snapshot, got %+v", forecast)
+		}
+	})
+}
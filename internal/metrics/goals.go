@@ -0,0 +1,247 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// DefaultGoalsPath is where reading goals are read from
+const DefaultGoalsPath = "config/goals.yml"
+
+// Goal kinds
+const (
+	GoalMonthlyReads       = "monthly_reads"         // at least Target articles read per calendar month
+	GoalMaxUnreadOlderThan = "max_unread_older_than" // fewer than Target unread articles older than OlderThan
+	GoalReadRate           = "read_rate"             // read rate of at least Target percent, overall or for Source
+)
+
+// Streak units
+const (
+	StreakMonths    = "months"
+	StreakSnapshots = "snapshots"
+)
+
+// Goal is one target from the goals config
+type Goal struct {
+	ID        string  `yaml:"id"`
+	Label     string  `yaml:"label"`
+	Kind      string  `yaml:"kind"`
+	Target    float64 `yaml:"target"`
+	OlderThan string  `yaml:"older_than"`
+	Source    string  `yaml:"source"`
+}
+
+// GoalConfig is the top-level structure of the goals YAML file
+type GoalConfig struct {
+	Goals []Goal `yaml:"goals"`
+}
+
+// validateGoals checks ids, kinds and the fields each kind needs
+func validateGoals(goals []Goal) error {
+	seen := make(map[string]bool)
+	for i, goal := range goals {
+		if strings.TrimSpace(goal.ID) == "" {
+			return fmt.Errorf("goal %d has no id", i)
+		}
+		if seen[goal.ID] {
+			return fmt.Errorf("goal %q is declared twice", goal.ID)
+		}
+		seen[goal.ID] = true
+
+		if goal.Target <= 0 {
+			return fmt.Errorf("goal %q: target must be positive", goal.ID)
+		}
+
+		switch goal.Kind {
+		case GoalMonthlyReads:
+		case GoalMaxUnreadOlderThan:
+			days, err := parseAgeDays(goal.OlderThan)
+			if err != nil {
+				return fmt.Errorf("goal %q: %w", goal.ID, err)
+			}
+			if days == 0 {
+				return fmt.Errorf("goal %q: older_than is required", goal.ID)
+			}
+		case GoalReadRate:
+			if goal.Target > 100 {
+				return fmt.Errorf("goal %q: read rate target must be at most 100", goal.ID)
+			}
+		default:
+			return fmt.Errorf("goal %q: unknown kind %q", goal.ID, goal.Kind)
+		}
+	}
+	return nil
+}
+
+// LoadGoals reads and validates a goals YAML file
+func LoadGoals(path string) ([]Goal, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read goals %s: %w", path, err)
+	}
+
+	var config GoalConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse goals %s: %w", path, err)
+	}
+
+	if err := validateGoals(config.Goals); err != nil {
+		return nil, fmt.Errorf("invalid goals %s: %w", path, err)
+	}
+	return config.Goals, nil
+}
+
+// unreadOlderThan sums the snapshot's age buckets that start at or after the given age.
+// Buckets straddling the age are not counted, so the age should match a bucket boundary.
+func unreadOlderThan(m schema.Metrics, olderThan string) float64 {
+	thresholdDays, _ := parseAgeDays(olderThan)
+
+	buckets := m.AgeBuckets
+	if len(buckets) == 0 {
+		buckets = DefaultAgeBuckets()
+	}
+
+	total := 0
+	for _, bucket := range buckets {
+		days, err := parseAgeDays(bucket.MinAge)
+		if err == nil && days >= thresholdDays-0.5 {
+			total += m.UnreadArticleAgeDistribution[bucket.Key]
+		}
+	}
+	return float64(total)
+}
+
+// snapshotGoalValue measures a threshold goal on a single snapshot
+func snapshotGoalValue(goal Goal, m schema.Metrics) float64 {
+	switch goal.Kind {
+	case GoalMaxUnreadOlderThan:
+		return unreadOlderThan(m, goal.OlderThan)
+	case GoalReadRate:
+		if goal.Source == "" {
+			return m.ReadRate
+		}
+		counts := m.BySourceReadStatus[goal.Source]
+		if total := counts[0] + counts[1]; total > 0 {
+			return float64(counts[0]) / float64(total) * 100
+		}
+	}
+	return 0
+}
+
+// goalMet reports whether a measured value satisfies the goal
+func goalMet(goal Goal, value float64) bool {
+	if goal.Kind == GoalMaxUnreadOlderThan {
+		return value < goal.Target
+	}
+	return value >= goal.Target
+}
+
+// goalProgress is the percent of the way to the target, capped at 100.
+// For ceilings it shrinks as the value climbs past the target.
+func goalProgress(goal Goal, value float64) float64 {
+	progress := value / goal.Target * 100
+	if goal.Kind == GoalMaxUnreadOlderThan {
+		if goalMet(goal, value) {
+			return 100
+		}
+		progress = goal.Target / value * 100
+	}
+	return math.Min(100, math.Round(progress*10)/10)
+}
+
+// monthReads is the number of articles read during one calendar month, measured between snapshots
+type monthReads struct {
+	month    time.Time
+	baseline int
+	reads    int
+	complete bool // a snapshot from the previous month provides the baseline
+}
+
+// calculateMonthlyReads groups a chronological series by snapshot month. Each month's reads are the
+// read count at its last snapshot minus the count at the last snapshot before it.
+func calculateMonthlyReads(series []schema.Metrics) []monthReads {
+	var months []monthReads
+	for i, snapshot := range series {
+		day := snapshotDay(snapshot.LastUpdated)
+		month := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+		if len(months) == 0 || !months[len(months)-1].month.Equal(month) {
+			entry := monthReads{month: month, baseline: snapshot.ReadCount}
+			if i > 0 {
+				previous := snapshotDay(series[i-1].LastUpdated)
+				entry.baseline = series[i-1].ReadCount
+				entry.complete = !previous.Before(month.AddDate(0, -1, 0))
+			}
+			months = append(months, entry)
+		}
+
+		last := &months[len(months)-1]
+		last.reads = snapshot.ReadCount - last.baseline
+	}
+	return months
+}
+
+// evaluateMonthlyReadsGoal measures reads in the current month. The streak counts the consecutive
+// earlier months that met the target, plus the current month once it has.
+func evaluateMonthlyReadsGoal(goal Goal, series []schema.Metrics) (float64, int) {
+	months := calculateMonthlyReads(series)
+	current := months[len(months)-1]
+
+	streak := 0
+	if goalMet(goal, float64(current.reads)) {
+		streak++
+	}
+	for i := len(months) - 2; i >= 0; i-- {
+		// Stop at gaps between months and at months without a full baseline
+		if !months[i+1].complete || !months[i].complete || !goalMet(goal, float64(months[i].reads)) {
+			break
+		}
+		streak++
+	}
+	return float64(current.reads), streak
+}
+
+// EvaluateGoals measures each goal against current, using the earlier snapshots in history
+// (oldest first) for monthly reads and streaks
+func EvaluateGoals(goals []Goal, history []schema.Metrics, current schema.Metrics) []schema.GoalResult {
+	series := append(append([]schema.Metrics(nil), history...), current)
+
+	results := make([]schema.GoalResult, 0, len(goals))
+	for _, goal := range goals {
+		result := schema.GoalResult{
+			ID:     goal.ID,
+			Label:  goal.Label,
+			Kind:   goal.Kind,
+			Source: goal.Source,
+			Target: goal.Target,
+		}
+		if result.Label == "" {
+			result.Label = goal.ID
+		}
+
+		var value float64
+		if goal.Kind == GoalMonthlyReads {
+			value, result.Streak = evaluateMonthlyReadsGoal(goal, series)
+			result.StreakUnit = StreakMonths
+		} else {
+			value = snapshotGoalValue(goal, current)
+			for i := len(series) - 1; i >= 0 && goalMet(goal, snapshotGoalValue(goal, series[i])); i-- {
+				result.Streak++
+			}
+			result.StreakUnit = StreakSnapshots
+		}
+
+		result.Current = math.Round(value*10) / 10
+		result.Met = goalMet(goal, value)
+		result.Progress = goalProgress(goal, value)
+		results = append(results, result)
+	}
+	return results
+}
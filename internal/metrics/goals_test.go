@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// createTestGoalSnapshot builds a snapshot on date with a read count and Substack read status
+func createTestGoalSnapshot(date string, readCount, substackRead, substackUnread, oldUnread int) schema.Metrics {
+	day, _ := time.Parse("2006-01-02", date)
+	return schema.Metrics{
+		ReadCount:          readCount,
+		BySourceReadStatus: map[string][2]int{"Substack": {substackRead, substackUnread}},
+		UnreadArticleAgeDistribution: map[string]int{
+			"6_to_12_months":   10,
+			"older_than_1year": oldUnread,
+		},
+		LastUpdated: day.Add(9 * time.Hour),
+	}
+}
+
+// ============================================================================
+// LoadGoals: Reads and validates the goals config
+// ============================================================================
+
+func TestLoadGoals(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectError string
+		expectGoals int
+	}{
+		{
+			name: "valid goals",
+			content: `goals:
+  - id: monthly
+    kind: monthly_reads
+    target: 40
+  - id: old
+    kind: max_unread_older_than
+    older_than: 1y
+    target: 200
+  - id: substack
+    kind: read_rate
+    source: Substack
+    target: 60
+`,
+			expectGoals: 3,
+		},
+		{
+			name:        "unknown kind",
+			content:     "goals:\n  - id: x\n    kind: weekly_reads\n    target: 5\n",
+			expectError: "unknown kind",
+		},
+		{
+			name:        "duplicate id",
+			content:     "goals:\n  - id: x\n    kind: monthly_reads\n    target: 5\n  - id: x\n    kind: read_rate\n    target: 5\n",
+			expectError: "declared twice",
+		},
+		{
+			name:        "missing older_than",
+			content:     "goals:\n  - id: x\n    kind: max_unread_older_than\n    target: 5\n",
+			expectError: "older_than is required",
+		},
+		{
+			name:        "non-positive target",
+			content:     "goals:\n  - id: x\n    kind: monthly_reads\n",
+			expectError: "target must be positive",
+		},
+		{
+			name:        "read rate above 100",
+			content:     "goals:\n  - id: x\n    kind: read_rate\n    target: 120\n",
+			expectError: "at most 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "goals.yml")
+			os.WriteFile(path, []byte(tt.content), 0644)
+
+			goals, err := LoadGoals(path)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(goals) != tt.expectGoals {
+				t.Errorf("expected %d goals, got %d", tt.expectGoals, len(goals))
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadGoals(filepath.Join(t.TempDir(), "missing.yml")); err == nil {
+			t.Error("expected error for missing file, got nil")
+		}
+	})
+}
+
+// ============================================================================
+// EvaluateGoals: Measures goals and streaks against the snapshot history
+// ============================================================================
+
+func TestEvaluateGoals(t *testing.T) {
+	history := []schema.Metrics{
+		createTestGoalSnapshot("2025-12-28", 100, 50, 50, 250), // baseline for January
+		createTestGoalSnapshot("2026-01-31", 145, 60, 40, 210), // January: 45 reads
+		createTestGoalSnapshot("2026-02-14", 170, 62, 38, 190), // February so far
+		createTestGoalSnapshot("2026-02-28", 190, 64, 36, 180), // February: 45 reads
+	}
+	current := createTestGoalSnapshot("2026-03-07", 200, 66, 34, 170) // March: 10 reads so far
+
+	goals := []Goal{
+		{ID: "monthly", Label: "Read 40 a month", Kind: GoalMonthlyReads, Target: 40},
+		{ID: "old", Kind: GoalMaxUnreadOlderThan, OlderThan: "1y", Target: 200},
+		{ID: "substack", Kind: GoalReadRate, Source: "Substack", Target: 60},
+	}
+
+	results := EvaluateGoals(goals, history, current)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	monthly := results[0]
+	if monthly.Current != 10 || monthly.Met || monthly.Progress != 25 {
+		t.Errorf("unexpected monthly result: %+v", monthly)
+	}
+	if monthly.Streak != 2 || monthly.StreakUnit != StreakMonths {
+		t.Errorf("expected a 2-month streak kept alive by January and February, got %d %s", monthly.Streak, monthly.StreakUnit)
+	}
+
+	old := results[1]
+	if old.Label != "old" {
+		t.Errorf("expected label to default to the id, got %q", old.Label)
+	}
+	if old.Current != 170 || !old.Met || old.Progress != 100 || old.Streak != 3 {
+		t.Errorf("unexpected ceiling result: %+v", old)
+	}
+
+	substack := results[2]
+	if substack.Current != 66 || !substack.Met || substack.Streak != 4 || substack.StreakUnit != StreakSnapshots {
+		t.Errorf("unexpected read rate result: %+v", substack)
+	}
+}
+
+func TestEvaluateGoalsCeilingProgress(t *testing.T) {
+	goal := Goal{ID: "old", Kind: GoalMaxUnreadOlderThan, OlderThan: "1y", Target: 200}
+	results := EvaluateGoals([]Goal{goal}, nil, createTestGoalSnapshot("2026-03-07", 0, 0, 0, 250))
+
+	if results[0].Met || results[0].Progress != 80 || results[0].Streak != 0 {
+		t.Errorf("expected an unmet goal at 80%%, got %+v", results[0])
+	}
+}
+
+func TestCalculateMonthlyReads(t *testing.T) {
+	series := []schema.Metrics{
+		createTestGoalSnapshot("2026-01-10", 10, 0, 0, 0),
+		createTestGoalSnapshot("2026-01-24", 30, 0, 0, 0),
+		createTestGoalSnapshot("2026-02-07", 50, 0, 0, 0),
+		createTestGoalSnapshot("2026-04-04", 90, 0, 0, 0), // March has no snapshot
+	}
+
+	months := calculateMonthlyReads(series)
+	expected := []struct {
+		month    string
+		reads    int
+		complete bool
+	}{
+		{"2026-01", 20, false},
+		{"2026-02", 20, true},
+		{"2026-04", 40, false},
+	}
+
+	if len(months) != len(expected) {
+		t.Fatalf("expected %d months, got %d", len(expected), len(months))
+	}
+	for i, want := range expected {
+		got := months[i]
+		if got.month.Format("2006-01") != want.month || got.reads != want.reads || got.complete != want.complete {
+			t.Errorf("month %d: expected %+v, got %s reads=%d complete=%v", i, want, got.month.Format("2006-01"), got.reads, got.complete)
+		}
+	}
+}
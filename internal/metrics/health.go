@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"math"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// Source health thresholds
+const (
+	HealthWindowDays      = 90   // recent activity window; a source with no article inside it is stale
+	NoisyMinArticles      = 10   // sources need this many articles before they can be called noisy
+	NoisyMaxReadRate      = 20.0 // percent; at or below this a well-fed source is noise
+	ValuableMinArticles   = 5    // sources need this many articles before they can be called valuable
+	ValuableMinReadRate   = 60.0 // percent
+	healthTrendLookbackMo = 3    // the read rate trend compares against a snapshot this many months back
+)
+
+// Source health classes
+const (
+	HealthStale    = "stale"
+	HealthNoisy    = "noisy"
+	HealthValuable = "valuable"
+	HealthActive   = "active"
+	HealthRetired  = "retired"
+)
+
+// updateSourceActivity records a source's latest article date and its articles inside the health window
+func updateSourceActivity(metrics *schema.Metrics, article *ParsedArticle, referenceDate time.Time) {
+	if article.Category == "" || article.Date.IsZero() {
+		return
+	}
+	if metrics.SourceHealth == nil {
+		metrics.SourceHealth = make(map[string]schema.SourceHealth)
+	}
+
+	health := metrics.SourceHealth[article.Category]
+	if date := article.Date.Format("2006-01-02"); date > health.LastArticle {
+		health.LastArticle = date
+	}
+
+	windowStart := referenceDate.AddDate(0, 0, -HealthWindowDays)
+	if article.Date.After(windowStart) && !article.Date.After(referenceDate) {
+		health.RecentArticles++
+	}
+	metrics.SourceHealth[article.Category] = health
+}
+
+// classifySource picks a health class; staleness wins over read-rate classes
+func classifySource(health schema.SourceHealth, total int, retired bool) string {
+	switch {
+	case retired:
+		return HealthRetired
+	case health.LastArticle == "" || health.DaysSinceLastArticle > HealthWindowDays:
+		return HealthStale
+	case total >= NoisyMinArticles && health.ReadRate <= NoisyMaxReadRate:
+		return HealthNoisy
+	case total >= ValuableMinArticles && health.ReadRate >= ValuableMinReadRate:
+		return HealthValuable
+	default:
+		return HealthActive
+	}
+}
+
+// calculateSourceHealth fills the derived health fields once every row has been processed.
+// Providers without any article are included so they show up as stale.
+func calculateSourceHealth(metrics *schema.Metrics, referenceDate time.Time) {
+	if metrics.SourceHealth == nil {
+		metrics.SourceHealth = make(map[string]schema.SourceHealth)
+	}
+	for name := range metrics.SourceMetadata {
+		if _, exists := metrics.SourceHealth[name]; !exists {
+			metrics.SourceHealth[name] = schema.SourceHealth{}
+		}
+	}
+
+	for name, health := range metrics.SourceHealth {
+		if last, err := time.Parse("2006-01-02", health.LastArticle); err == nil {
+			health.DaysSinceLastArticle = int(referenceDate.Sub(last).Hours() / 24)
+		}
+		health.ArticlesPerMonth = math.Round(float64(health.RecentArticles)/(HealthWindowDays/daysPerMonth)*100) / 100
+
+		counts := metrics.BySourceReadStatus[name]
+		total := counts[0] + counts[1]
+		if total > 0 {
+			health.ReadRate = math.Round(float64(counts[0])/float64(total)*1000) / 10
+		}
+
+		health.Class = classifySource(health, total, metrics.SourceMetadata[name].Retired != "")
+		metrics.SourceHealth[name] = health
+	}
+}
+
+// applySourceReadRateTrends sets each source's read rate change against the latest earlier snapshot
+// at least healthTrendLookbackMo months old, or the oldest snapshot when history is shorter.
+func applySourceReadRateTrends(history []schema.Metrics, m *schema.Metrics) {
+	if len(history) == 0 || len(m.SourceHealth) == 0 {
+		return
+	}
+
+	cutoff := snapshotDay(m.LastUpdated).AddDate(0, -healthTrendLookbackMo, 0)
+	baseline := history[0]
+	for _, snapshot := range history {
+		if snapshotDay(snapshot.LastUpdated).After(cutoff) {
+			break
+		}
+		baseline = snapshot
+	}
+
+	since := snapshotDay(baseline.LastUpdated).Format("2006-01-02")
+	for name, health := range m.SourceHealth {
+		counts, exists := baseline.BySourceReadStatus[name]
+		total := counts[0] + counts[1]
+		if !exists || total == 0 {
+			continue
+		}
+		previousRate := float64(counts[0]) / float64(total) * 100
+		health.ReadRateTrend = math.Round((health.ReadRate-previousRate)*10) / 10
+		health.TrendSince = since
+		m.SourceHealth[name] = health
+	}
+}
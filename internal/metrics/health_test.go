@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// ============================================================================
+// Source health: Activity tracking, classification and read rate trends
+// ============================================================================
+
+func TestUpdateSourceActivity(t *testing.T) {
+	ref := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	m := &schema.Metrics{}
+
+	for _, article := range []ParsedArticle{
+		{Date: time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC), Category: "GitHub"}, // outside the window
+		{Date: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), Category: "GitHub"},
+		{Date: time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC), Category: "GitHub"},
+		{Date: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), Category: "GitHub"}, // future-dated
+		{Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},                     // no source
+	} {
+		updateSourceActivity(m, &article, ref)
+	}
+
+	health := m.SourceHealth["GitHub"]
+	if health.LastArticle != "2026-04-02" {
+		t.Errorf("expected last article 2026-04-02, got %s", health.LastArticle)
+	}
+	if health.RecentArticles != 2 {
+		t.Errorf("expected 2 recent articles, got %d", health.RecentArticles)
+	}
+	if len(m.SourceHealth) != 1 {
+		t.Errorf("expected only GitHub to be tracked, got %v", m.SourceHealth)
+	}
+}
+
+func TestCalculateSourceHealth(t *testing.T) {
+	ref := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	m := &schema.Metrics{
+		BySourceReadStatus: map[string][2]int{
+			"Quiet":    {8, 2},
+			"Noisy":    {2, 18},
+			"Valuable": {7, 3},
+			"Steady":   {4, 4},
+			"Old":      {1, 1},
+		},
+		SourceMetadata: map[string]schema.SourceMeta{
+			"Old":   {Retired: "2025-01-01"},
+			"Empty": {Added: "2026-01-01"},
+		},
+		SourceHealth: map[string]schema.SourceHealth{
+			"Quiet":    {LastArticle: "2025-12-01"},
+			"Noisy":    {LastArticle: "2026-03-20", RecentArticles: 9},
+			"Valuable": {LastArticle: "2026-03-01", RecentArticles: 3},
+			"Steady":   {LastArticle: "2026-03-28", RecentArticles: 6},
+			"Old":      {LastArticle: "2024-12-20"},
+		},
+	}
+
+	calculateSourceHealth(m, ref)
+
+	expected := map[string]string{
+		"Quiet":    HealthStale,
+		"Noisy":    HealthNoisy,
+		"Valuable": HealthValuable,
+		"Steady":   HealthActive,
+		"Old":      HealthRetired,
+		"Empty":    HealthStale,
+	}
+	for name, class := range expected {
+		if got := m.SourceHealth[name].Class; got != class {
+			t.Errorf("%s: expected class %s, got %s", name, class, got)
+		}
+	}
+
+	quiet := m.SourceHealth["Quiet"]
+	if quiet.DaysSinceLastArticle != 120 || quiet.ReadRate != 80 {
+		t.Errorf("unexpected quiet source health: %+v", quiet)
+	}
+	if steady := m.SourceHealth["Steady"]; steady.ArticlesPerMonth != 2.03 {
+		t.Errorf("expected 6 articles over 90 days to be 2.03/month, got %.2f", steady.ArticlesPerMonth)
+	}
+}
+
+func TestApplySourceReadRateTrends(t *testing.T) {
+	snapshot := func(date string, counts map[string][2]int) schema.Metrics {
+		day, _ := time.Parse("2006-01-02", date)
+		return schema.Metrics{BySourceReadStatus: counts, LastUpdated: day}
+	}
+
+	history := []schema.Metrics{
+		snapshot("2025-11-07", map[string][2]int{"GitHub": {2, 8}}),
+		snapshot("2025-12-26", map[string][2]int{"GitHub": {4, 6}}), // latest at least 3 months back
+		snapshot("2026-02-06", map[string][2]int{"GitHub": {8, 2}}),
+	}
+	current := snapshot("2026-03-27", nil)
+	current.SourceHealth = map[string]schema.SourceHealth{
+		"GitHub": {ReadRate: 55},
+		"New":    {ReadRate: 10},
+	}
+
+	applySourceReadRateTrends(history, &current)
+
+	github := current.SourceHealth["GitHub"]
+	if github.ReadRateTrend != 15 || github.TrendSince != "2025-12-26" {
+		t.Errorf("expected +15 pts since 2025-12-26, got %+v", github)
+	}
+	if current.SourceHealth["New"].TrendSince != "" {
+		t.Errorf("expected no trend for a source missing from the baseline, got %+v", current.SourceHealth["New"])
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// DefaultMetricsDir is where snapshots are stored unless METRICS_DIR or a -metrics-dir flag says otherwise
+const DefaultMetricsDir = "metrics"
+
+// MetricsDirFromEnv returns the snapshot directory from METRICS_DIR, or DefaultMetricsDir when unset
+func MetricsDirFromEnv() string {
+	if dir := strings.TrimSpace(os.Getenv("METRICS_DIR")); dir != "" {
+		return dir
+	}
+	return DefaultMetricsDir
+}
+
+// ListSnapshotFiles returns the snapshot filenames in dir, oldest first. A daily snapshot sorts
+// before the intra-day snapshots of the same date.
+// Quality reports, .gitkeep and other files sharing the directory are skipped.
+func ListSnapshotFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read metrics directory %s: %w", dir, err)
+	}
+
+	var filenames []string
+	for _, entry := range entries {
+		if !entry.IsDir() && IsSnapshotFilename(entry.Name()) {
+			filenames = append(filenames, entry.Name())
+		}
+	}
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
+// LoadSnapshotHistory reads every snapshot in store dated before `before`, oldest first.
+// A zero `before` loads all snapshots.
+func LoadSnapshotHistory(ctx context.Context, store SnapshotStore, before time.Time) ([]schema.Metrics, error) {
+	filenames, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]schema.Metrics, 0, len(filenames))
+	for _, filename := range filenames {
+		if !before.IsZero() && SnapshotFileDate(filename) >= before.Format(SnapshotDateLayout) {
+			continue
+		}
+		snapshot, err := LoadSnapshotWithAnalysis(ctx, store, filename)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, snapshot)
+	}
+
+	return history, nil
+}
+
+// HistoryOptions configures the analytics that AttachHistoryAnalytics derives from earlier snapshots
+type HistoryOptions struct {
+	TargetDate time.Time // backlog forecast target; zero uses DefaultForecastTarget
+	Goals      []Goal
+	SourceMap  map[string]string // lines earlier snapshots and goal sources up under canonical names (see BuildSourceMap)
+}
+
+// AttachHistoryAnalytics loads the snapshots in store that precede m and sets the figures that need
+// them: the backlog forecast, goal progress, reading cadence and source read rate trends. A missing
+// directory is treated as no history.
+func AttachHistoryAnalytics(ctx context.Context, store SnapshotStore, m *schema.Metrics, opts HistoryOptions) error {
+	day := snapshotDay(m.LastUpdated)
+	history, err := LoadSnapshotHistory(ctx, store, day)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// Earlier snapshots keep the source names of their day; renamed sources must line up with m
+	if opts.SourceMap != nil {
+		for i := range history {
+			CanonicalizeSources(&history[i], opts.SourceMap)
+		}
+	}
+
+	targetDate := opts.TargetDate
+	if targetDate.IsZero() {
+		targetDate = DefaultForecastTarget(day)
+	}
+
+	series := append(history, *m)
+	m.BacklogForecast = CalculateBacklogForecast(series, targetDate)
+	m.Cadence = CalculateCadence(series)
+	applySourceReadRateTrends(history, m)
+	if len(opts.Goals) > 0 {
+		goals := append([]Goal(nil), opts.Goals...)
+		for i := range goals {
+			if goals[i].Source != "" {
+				goals[i].Source = NormalizeSourceName(goals[i].Source, opts.SourceMap)
+			}
+		}
+		m.Goals = EvaluateGoals(goals, history, *m)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+func TestLoadSnapshotHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"2026-01-15.json":         `{"total_articles": 120, "last_updated": "2026-01-15T10:00:00Z"}`,
+		"2026-01-01.json":         `{"total_articles": 100}`,
+		"2026-01-08.json":         `{"total_articles": 110, "last_updated": "2026-01-08T10:00:00Z"}`,
+		"2026-01-08.quality.json": `{"rows_scanned": 3}`,
+		"2026-01-15T060000.json":  `{"total_articles": 118}`,
+		".gitkeep":                ``,
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644)
+	}
+
+	t.Run("all snapshots oldest first", func(t *testing.T) {
+		history, err := LoadSnapshotHistory(context.Background(), NewFileStore(tmpDir), time.Time{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(history) != 4 || history[0].TotalArticles != 100 || history[2].TotalArticles != 120 || history[3].TotalArticles != 118 {
+			t.Fatalf("unexpected history: %+v", history)
+		}
+		if history[0].LastUpdated.Format("2006-01-02") != "2026-01-01" {
+			t.Errorf("expected missing timestamp to fall back to the filename, got %v", history[0].LastUpdated)
+		}
+		if history[3].LastUpdated.Format("15:04") != "06:00" {
+			t.Errorf("expected an intra-day filename to supply the time of day, got %v", history[3].LastUpdated)
+		}
+	})
+
+	t.Run("only snapshots before a date", func(t *testing.T) {
+		history, err := LoadSnapshotHistory(context.Background(), NewFileStore(tmpDir), time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(history) != 2 {
+			t.Errorf("expected 2 snapshots before 2026-01-15, got %d", len(history))
+		}
+	})
+
+	t.Run("invalid snapshot", func(t *testing.T) {
+		os.WriteFile(filepath.Join(tmpDir, "2026-01-22.json"), []byte("{broken"), 0644)
+		if _, err := LoadSnapshotHistory(context.Background(), NewFileStore(tmpDir), time.Time{}); err == nil {
+			t.Error("expected error for invalid JSON, got nil")
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		if _, err := LoadSnapshotHistory(context.Background(), NewFileStore(filepath.Join(tmpDir, "missing")), time.Time{}); err == nil {
+			t.Error("expected error for missing directory, got nil")
+		}
+	})
+}
+
+func TestMetricsDirFromEnv(t *testing.T) {
+	t.Setenv("METRICS_DIR", "")
+	if dir := MetricsDirFromEnv(); dir != DefaultMetricsDir {
+		t.Errorf("expected %q without METRICS_DIR, got %q", DefaultMetricsDir, dir)
+	}
+
+	t.Setenv("METRICS_DIR", "/data/snapshots")
+	if dir := MetricsDirFromEnv(); dir != "/data/snapshots" {
+		t.Errorf("expected METRICS_DIR to be used, got %q", dir)
+	}
+}
+
+func TestAttachHistoryAnalytics(t *testing.T) {
+	goals := []Goal{{ID: "substack", Kind: GoalReadRate, Source: "Substack", Target: 60}}
+
+	t.Run("with earlier snapshots", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		series := createTestBacklogSeries(100, 90, 80, 70)
+
+		// Write all but the current snapshot, plus one from the future that must be ignored
+		written := []schema.Metrics{series[0], series[1], series[2], createTestBacklogSeries(0, 0, 0, 0, 0, 0)[5]}
+		for _, snapshot := range written {
+			content, _ := json.Marshal(snapshot)
+			os.WriteFile(filepath.Join(tmpDir, snapshot.LastUpdated.Format("2006-01-02")+".json"), content, 0644)
+		}
+
+		current := series[3]
+		if err := AttachHistoryAnalytics(context.Background(), NewFileStore(tmpDir), &current, HistoryOptions{Goals: goals}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if current.BacklogForecast == nil || len(current.BacklogForecast.History) != 4 {
+			t.Fatalf("expected a forecast over 4 snapshots, got %+v", current.BacklogForecast)
+		}
+		if current.BacklogForecast.TargetDate != "2026-12-31" {
+			t.Errorf("expected default target 2026-12-31, got %s", current.BacklogForecast.TargetDate)
+		}
+		if current.Cadence == nil || current.Cadence.WeeksObserved != 3 || current.Cadence.CurrentStreak != 3 {
+			t.Errorf("expected 3 observed weeks all with reads, got %+v", current.Cadence)
+		}
+		if len(current.Goals) != 1 {
+			t.Errorf("expected 1 goal result, got %d", len(current.Goals))
+		}
+	})
+
+	t.Run("S3 store", func(t *testing.T) {
+		_, server := newFakeS3(t, "reading")
+		store := newTestS3Store(t, server, "reading", "metrics/")
+		series := createTestBacklogSeries(100, 90, 80, 70)
+		for _, snapshot := range series[:3] {
+			if err := store.Save(context.Background(), SnapshotFilename(snapshot.LastUpdated, false), snapshot, false); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+		}
+
+		current := series[3]
+		if err := AttachHistoryAnalytics(context.Background(), store, &current, HistoryOptions{Goals: goals}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if current.BacklogForecast == nil || len(current.BacklogForecast.History) != 4 || len(current.Goals) != 1 {
+			t.Errorf("expected a forecast over 4 snapshots and 1 goal, got %+v / %+v", current.BacklogForecast, current.Goals)
+		}
+	})
+
+	t.Run("renamed source", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		store := NewFileStore(tmpDir)
+		for _, date := range []string{"2026-03-07", "2026-03-14"} {
+			snapshot := createTestGoalSnapshot(date, 100, 0, 0, 10)
+			snapshot.BySourceReadStatus = map[string][2]int{"GitHub Engineering": {7, 3}}
+			if err := store.Save(context.Background(), SnapshotFilename(snapshot.LastUpdated, false), snapshot, false); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+		}
+		renamed := []Goal{{ID: "github", Kind: GoalReadRate, Source: "GitHub Engineering", Target: 60}}
+
+		for _, tt := range []struct {
+			name      string
+			sourceMap map[string]string
+			source    string
+			streak    int
+		}{
+			{name: "without the source map", source: "GitHub Engineering", streak: 0},
+			{name: "with the source map", sourceMap: createTestSourceMap(), source: "GitHub", streak: 3},
+		} {
+			current := createTestGoalSnapshot("2026-03-21", 110, 0, 0, 10)
+			current.BySourceReadStatus = map[string][2]int{"GitHub": {8, 2}}
+			if err := AttachHistoryAnalytics(context.Background(), store, &current, HistoryOptions{Goals: renamed, SourceMap: tt.sourceMap}); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if len(current.Goals) != 1 || current.Goals[0].Source != tt.source || current.Goals[0].Streak != tt.streak {
+				t.Errorf("%s: expected %s with a streak of %d, got %+v", tt.name, tt.source, tt.streak, current.Goals)
+			}
+		}
+	})
+
+	t.Run("without history", func(t *testing.T) {
+		m := createTestGoalSnapshot("2026-03-07", 200, 66, 34, 170)
+		if err := AttachHistoryAnalytics(context.Background(), NewFileStore(filepath.Join(t.TempDir(), "missing")), &m, HistoryOptions{Goals: goals}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if m.BacklogForecast != nil || m.Cadence != nil {
+			t.Errorf("expected no forecast or cadence from a single snapshot, got %+v / %+v", m.BacklogForecast, m.Cadence)
+		}
+		if len(m.Goals) != 1 || !m.Goals[0].Met || m.Goals[0].Streak != 1 {
+			t.Errorf("expected the goal measured on the snapshot alone, got %+v", m.Goals)
+		}
+	})
+}
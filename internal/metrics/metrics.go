@@ -37,6 +37,7 @@ const (
 	ColLink     = 2 // Column C: article link
 	ColCategory = 3 // Column D: source/category
 	ColRead     = 4 // Column E: read status (TRUE/FALSE)
+	ColReadDate = 5 // Column F: date the article was read (optional, YYYY-MM-DD)
 
 	// Sheet names
 	DefaultArticlesSheet  = "articles"
@@ -55,6 +56,15 @@ const (
 
 	// Top oldest unread articles count
 	TopUnreadArticlesCount = 3
+
+	// Oldest unread articles kept per source for the source pages
+	SourceBacklogCount = 25
+
+	// Oldest unread articles kept per publication year for the year pages
+	YearBacklogCount = 10
+
+	// Oldest unread articles kept per Substack publication for the drill-down
+	SubstackBacklogCount = 5
 )
 
 // calculateMonthsDifference calculates the number of months between two dates
@@ -96,52 +106,43 @@ func countSubstackProviders(client SheetsClient, spreadsheetID, providersSheet s
 	return count, nil
 }
 
-// BuildSourceMap creates a normalization map from the providers sheet data
-func BuildSourceMap(rows [][]interface{}) map[string]string {
-	sourceMap := map[string]string{
-		"substack":     "Substack",
-		"freecodecamp": "freeCodeCamp",
-		"github":       "GitHub",
-		"shopify":      "Shopify",
-		"stripe":       "Stripe",
-	}
-
-	if len(rows) <= 1 {
-		return sourceMap
-	}
+// BuildSourceMap creates a normalization map (lookup key -> canonical name) from the alias
+// configuration and the providers sheet. Configured aliases win over provider spellings so
+// merged and renamed sources always resolve to their canonical name.
+func BuildSourceMap(rows [][]interface{}, aliases SourceAliasConfig) map[string]string {
+	sourceMap := make(map[string]string)
 
-	// Skip header row and build map from provider names
+	// Skip header row and map each provider name to itself
 	for i := 1; i < len(rows); i++ {
 		row := rows[i]
 		if len(row) > ProvidersColName {
-			name := fmt.Sprintf("%v", row[ProvidersColName])
-			sourceMap[strings.ToLower(name)] = name
+			name := strings.TrimSpace(fmt.Sprintf("%v", row[ProvidersColName]))
+			if name != "" {
+				sourceMap[sourceKey(name)] = name
+			}
 		}
 	}
 
-	return sourceMap
-}
-
-// NormalizeSourceName converts source names to proper capitalization using a dynamic map
-func NormalizeSourceName(name string, sourceMap map[string]string) string {
-	if sourceMap == nil {
-		sourceMap = map[string]string{
-			"substack":     "Substack",
-			"freecodecamp": "freeCodeCamp",
-			"github":       "GitHub",
-			"shopify":      "Shopify",
-			"stripe":       "Stripe",
+	for _, source := range aliases.Sources {
+		sourceMap[sourceKey(source.Name)] = source.Name
+		for _, alias := range source.Aliases {
+			sourceMap[sourceKey(alias)] = source.Name
+		}
+		for _, previous := range source.PreviousNames {
+			sourceMap[sourceKey(previous)] = source.Name
 		}
 	}
 
-	// Convert to lowercase for comparison
-	lower := strings.ToLower(name)
+	return sourceMap
+}
 
-	// Return normalized name if found, otherwise return original
-	if normalized, exists := sourceMap[lower]; exists {
+// NormalizeSourceName resolves a source name to its canonical form using the normalization map.
+// Lookups ignore case and surrounding or repeated whitespace; unknown names are returned trimmed.
+func NormalizeSourceName(name string, sourceMap map[string]string) string {
+	if normalized, exists := sourceMap[sourceKey(name)]; exists {
 		return normalized
 	}
-	return name
+	return strings.TrimSpace(name)
 }
 
 // ParsedArticle represents parsed data from a single article row
@@ -260,6 +261,28 @@ func updateMetricsByDate(metrics *schema.Metrics, article *ParsedArticle, earlie
 	}
 }
 
+// updateMetricsBySourceAndMonth tracks read/unread counts per source and calendar month (YYYY-MM)
+func updateMetricsBySourceAndMonth(metrics *schema.Metrics, article *ParsedArticle) {
+	if article.Category == "" || article.Date.IsZero() {
+		return
+	}
+	if metrics.BySourceAndMonth == nil {
+		metrics.BySourceAndMonth = make(map[string]map[string][2]int)
+	}
+	if metrics.BySourceAndMonth[article.Category] == nil {
+		metrics.BySourceAndMonth[article.Category] = make(map[string][2]int)
+	}
+
+	month := article.Date.Format("2006-01")
+	status := metrics.BySourceAndMonth[article.Category][month]
+	if article.IsRead {
+		status[0]++
+	} else {
+		status[1]++
+	}
+	metrics.BySourceAndMonth[article.Category][month] = status
+}
+
 // updateMetricsBySource updates source-level aggregate metrics
 func updateMetricsBySource(metrics *schema.Metrics, category string) {
 	if category != "" {
@@ -310,52 +333,92 @@ func updateMetricsReadStatus(metrics *schema.Metrics, article *ParsedArticle) {
 	}
 }
 
-// calculateArticleAgeBucket determines which age bucket an article falls into
-func calculateArticleAgeBucket(articleDate, referenceDate time.Time) string {
-	if articleDate.After(referenceDate) {
-		return "less_than_1_month" // Handle future dates just in case
+// updateMetricsByTopic updates topic-level aggregate metrics
+func updateMetricsByTopic(metrics *schema.Metrics, topics []string, isRead bool) {
+	if len(topics) == 0 {
+		return
+	}
+	if metrics.ByTopic == nil {
+		metrics.ByTopic = make(map[string][2]int)
 	}
 
-	daysDiff := referenceDate.Sub(articleDate).Hours() / 24
-	monthsDiff := daysDiff / 30.44 // Average days per month
-	yearsDiff := daysDiff / 365.25 // Average days per year
-
-	if yearsDiff >= 1 {
-		return "older_than_1year"
-	} else if monthsDiff >= 6 {
-		return "6_to_12_months"
-	} else if monthsDiff >= 3 {
-		return "3_to_6_months"
-	} else if monthsDiff >= 1 {
-		return "1_to_3_months"
+	for _, topic := range topics {
+		status := metrics.ByTopic[topic]
+		if isRead {
+			status[0]++
+		} else {
+			status[1]++
+		}
+		metrics.ByTopic[topic] = status
 	}
-	return "less_than_1_month"
 }
 
-// updateUnreadArticleAgeDistribution updates the age distribution for unread articles
-func updateUnreadArticleAgeDistribution(metrics *schema.Metrics, article *ParsedArticle, referenceDate time.Time) {
+// calculateArticleAgeBucket determines which default age bucket an article falls into
+func calculateArticleAgeBucket(articleDate, referenceDate time.Time) string {
+	return defaultAgeBucketScheme.Bucket(articleDate, referenceDate)
+}
+
+// updateUnreadArticleAgeDistribution updates the age distribution for unread articles.
+// A nil scheme uses the default buckets.
+func updateUnreadArticleAgeDistribution(metrics *schema.Metrics, article *ParsedArticle, referenceDate time.Time, scheme *AgeBucketScheme) {
+	if scheme == nil {
+		scheme = defaultAgeBucketScheme
+	}
 	if !article.IsRead && !article.Date.IsZero() {
-		bucket := calculateArticleAgeBucket(article.Date, referenceDate)
+		bucket := scheme.Bucket(article.Date, referenceDate)
 		metrics.UnreadArticleAgeDistribution[bucket]++
 	}
 }
 
+// rowProcessingOptions carries the lookups and collectors used while processing article rows.
+// Every field is optional: a nil lookup disables its feature and a zero asOf means now.
+type rowProcessingOptions struct {
+	sourceMap    map[string]string
+	classifier   *Classifier
+	publications map[string]string
+	quality      *qualityCollector
+	skipRows     map[int]bool // rows index -> duplicate copy excluded from aggregates
+	sheetRows    []int        // rows index -> 1-based sheet row; nil when rows is the whole sheet
+	ageBuckets   *AgeBucketScheme
+	asOf         time.Time // when the snapshot is taken; article ages are measured against its date
+}
+
+// sheetRowNumber returns the 1-based sheet row of rows[index]. sheetRows maps a rewound sheet
+// back to the original; nil means rows is the sheet itself.
+func sheetRowNumber(sheetRows []int, index int) int {
+	if sheetRows == nil {
+		return index + 1
+	}
+	return sheetRows[index]
+}
+
 // processArticleRows processes all article rows and updates metrics
-func processArticleRows(rows [][]interface{}, metrics *schema.Metrics, earliestDate, latestDate *time.Time, sourceMap map[string]string) ([]schema.ArticleMeta, *schema.ArticleMeta) {
+func processArticleRows(rows [][]interface{}, metrics *schema.Metrics, earliestDate, latestDate *time.Time, opts rowProcessingOptions) ([]schema.ArticleMeta, *schema.ArticleMeta) {
 	var unreadArticles []schema.ArticleMeta
 	var oldestUnreadArticle *schema.ArticleMeta
 
+	sourceMap := opts.sourceMap
+	referenceDate := snapshotDate(opts.asOf)
+
 	// Skip header row (row 0) and process each article
 	for i := 1; i < len(rows); i++ {
 		row := rows[i]
 
 		// Parse the article row into structured data
 		article, err := parseArticleRow(row, sourceMap)
+
+		// Record rejected and suspicious rows against their sheet row
+		opts.quality.inspect(sheetRowNumber(opts.sheetRows, i), row, article, err)
 		if err != nil {
 			// Skip incomplete or invalid rows
 			continue
 		}
 
+		// Count each duplicated post once
+		if opts.skipRows[i] {
+			continue
+		}
+
 		metrics.TotalArticles++
 
 		// Update metrics by date (year, month, month+source aggregates)
@@ -363,6 +426,7 @@ func processArticleRows(rows [][]interface{}, metrics *schema.Metrics, earliestD
 
 		// Update source-level aggregates
 		updateMetricsBySource(metrics, article.Category)
+		updateMetricsBySourceAndMonth(metrics, article)
 
 		// Update category-level aggregates
 		updateMetricsByCategory(metrics, article)
@@ -370,6 +434,24 @@ func processArticleRows(rows [][]interface{}, metrics *schema.Metrics, earliestD
 		// Update read/unread counts and by-source read status
 		updateMetricsReadStatus(metrics, article)
 
+		// Track when each source last published and how much it published recently
+		updateSourceActivity(metrics, article, referenceDate)
+
+		// Tag topics from the title and link when rules are configured
+		var topics []string
+		if opts.classifier != nil {
+			if detail, _ := parseArticleRowWithDetails(row, sourceMap); detail != nil {
+				topics = opts.classifier.Classify(*detail)
+				updateMetricsByTopic(metrics, topics, article.IsRead)
+			}
+		}
+
+		// Attribute Substack articles to their publication
+		if article.Category == SubstackProvider && len(row) > ColLink {
+			publication := SubstackPublication(fmt.Sprintf("%v", row[ColLink]), opts.publications)
+			updateMetricsBySubstackPublication(metrics, publication, article.IsRead)
+		}
+
 		// Track unread by month and age distribution
 		if !article.IsRead {
 			month := article.Date.Format("01")
@@ -380,11 +462,12 @@ func processArticleRows(rows [][]interface{}, metrics *schema.Metrics, earliestD
 			metrics.UnreadByYear[year]++
 
 			// Update age distribution for unread articles
-			updateUnreadArticleAgeDistribution(metrics, article, time.Now())
+			updateUnreadArticleAgeDistribution(metrics, article, referenceDate, opts.ageBuckets)
 
 			// Collect unread article details
 			articleDetail, _ := parseArticleRowWithDetails(row, sourceMap)
 			if articleDetail != nil {
+				articleDetail.Topics = topics
 				unreadArticles = append(unreadArticles, *articleDetail)
 
 				// Track oldest unread article
@@ -405,8 +488,9 @@ func processArticleRows(rows [][]interface{}, metrics *schema.Metrics, earliestD
 	return unreadArticles, oldestUnreadArticle
 }
 
-// calculateDerivedMetrics computes read rate and average articles per month
-func calculateDerivedMetrics(metrics *schema.Metrics, earliestDate, latestDate time.Time) {
+// calculateDerivedMetrics computes read rate and average articles per month.
+// The partial latest month is measured as of asOf; a zero asOf means now.
+func calculateDerivedMetrics(metrics *schema.Metrics, earliestDate, latestDate, asOf time.Time) {
 	if metrics.TotalArticles > 0 {
 		metrics.ReadRate = (float64(metrics.ReadCount) / float64(metrics.TotalArticles)) * 100
 	}
@@ -418,7 +502,7 @@ func calculateDerivedMetrics(metrics *schema.Metrics, earliestDate, latestDate t
 
 		// Handle partial month for the latest month
 		// If latestDate is in the current month, we calculate the fraction of the month passed
-		now := time.Now()
+		now := resolveAsOf(asOf)
 		if latestDate.Year() == now.Year() && latestDate.Month() == now.Month() {
 			daysInMonth := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
 			fraction := float64(now.Day()) / float64(daysInMonth)
@@ -455,6 +539,43 @@ func populateTopArticles(metrics *schema.Metrics, unreadArticles []schema.Articl
 		} else {
 			metrics.TopOldestUnreadArticles = unreadArticles
 		}
+
+		// Keep the oldest unread articles of each source for its page
+		metrics.SourceBacklog = make(map[string][]schema.ArticleMeta)
+		for _, article := range unreadArticles {
+			if article.Category != "" && len(metrics.SourceBacklog[article.Category]) < SourceBacklogCount {
+				metrics.SourceBacklog[article.Category] = append(metrics.SourceBacklog[article.Category], article)
+			}
+		}
+
+		// And the oldest unread articles published in each year
+		metrics.YearBacklog = make(map[string][]schema.ArticleMeta)
+		for _, article := range unreadArticles {
+			if len(article.Date) < 4 {
+				continue
+			}
+			year := article.Date[:4]
+			if len(metrics.YearBacklog[year]) < YearBacklogCount {
+				metrics.YearBacklog[year] = append(metrics.YearBacklog[year], article)
+			}
+		}
+	}
+}
+
+// populateSubstackBacklog keeps the oldest unread articles of each Substack publication.
+// unreadArticles must already be sorted oldest first, as populateTopArticles leaves them.
+func populateSubstackBacklog(metrics *schema.Metrics, unreadArticles []schema.ArticleMeta, publications map[string]string) {
+	for _, article := range unreadArticles {
+		if article.Category != SubstackProvider {
+			continue
+		}
+		if metrics.SubstackBacklog == nil {
+			metrics.SubstackBacklog = make(map[string][]schema.ArticleMeta)
+		}
+		publication := SubstackPublication(article.Link, publications)
+		if len(metrics.SubstackBacklog[publication]) < SubstackBacklogCount {
+			metrics.SubstackBacklog[publication] = append(metrics.SubstackBacklog[publication], article)
+		}
 	}
 }
 
@@ -541,17 +662,44 @@ func getSubstackProviderCount(fetcher SheetsFetcher, spreadsheetID, providersShe
 
 // FetchMetricsFromSheetsWithService retrieves and calculates metrics using an existing Sheets service client.
 // This is now a thin orchestrator that delegates to smaller, testable functions.
-func FetchMetricsFromSheetsWithService(ctx context.Context, client *sheets.Service, spreadsheetID string) (schema.Metrics, error) {
+// asOf pins the snapshot date and every "now" derived from it; a zero asOf means now.
+func FetchMetricsFromSheetsWithService(ctx context.Context, client *sheets.Service, spreadsheetID string, asOf time.Time) (schema.Metrics, error) {
 	fetcher := &SheetServiceFetcher{service: client}
-	return fetchMetricsWithFetcher(spreadsheetID, fetcher)
+	return fetchMetricsWithFetcher(spreadsheetID, fetcher, asOf)
+}
+
+// fetchMetricsWithFetcher performs metrics calculation with a pluggable sheet fetcher for testability.
+// With an asOf date the sheet is first rewound to that date, as a backfill would, so articles
+// added or read later do not leak into the snapshot.
+func fetchMetricsWithFetcher(spreadsheetID string, fetcher SheetsFetcher, asOf time.Time) (schema.Metrics, error) {
+	inputs, err := loadSnapshotInputs(spreadsheetID, fetcher)
+	if err != nil {
+		return schema.Metrics{}, err
+	}
+
+	if asOf.IsZero() {
+		return buildSnapshot(inputs, inputs.articleRows, nil, asOf), nil
+	}
+	sheet := reconstructArticleRows(inputs.articleRows, asOf)
+	return buildSnapshot(inputs, sheet.rows, sheet.sheetRows, asOf), nil
+}
+
+// snapshotInputs holds the sheet data and configuration a snapshot is computed from,
+// read once so several snapshots can be built from the same data
+type snapshotInputs struct {
+	providerRows [][]interface{}
+	articleRows  [][]interface{}
+	aliases      SourceAliasConfig
+	classifier   *Classifier
+	ageBuckets   *AgeBucketScheme
 }
 
-// fetchMetricsWithFetcher performs metrics calculation with a pluggable sheet fetcher for testability
-func fetchMetricsWithFetcher(spreadsheetID string, fetcher SheetsFetcher) (schema.Metrics, error) {
+// loadSnapshotInputs reads the providers and articles sheets and the analysis configuration
+func loadSnapshotInputs(spreadsheetID string, fetcher SheetsFetcher) (snapshotInputs, error) {
 	// Get spreadsheet metadata to find sheet names
 	spreadsheet, err := fetcher.GetSpreadsheet(spreadsheetID)
 	if err != nil {
-		return schema.Metrics{}, fmt.Errorf("unable to retrieve spreadsheet: %w", err)
+		return snapshotInputs{}, fmt.Errorf("unable to retrieve spreadsheet: %w", err)
 	}
 
 	// Find Article and Provider sheet names
@@ -563,8 +711,43 @@ func fetchMetricsWithFetcher(spreadsheetID string, fetcher SheetsFetcher) (schem
 		log.Printf("Warning: Unable to read providers sheet: %v\n", err)
 	}
 
-	// Build normalization map from providers
-	sourceMap := BuildSourceMap(providerRows)
+	// Read all articles data
+	articleRows, err := fetcher.GetArticleRows(spreadsheetID, articlesSheet)
+	if err != nil {
+		return snapshotInputs{}, fmt.Errorf("unable to retrieve data from sheet: %w", err)
+	}
+
+	if len(articleRows) == 0 {
+		return snapshotInputs{}, fmt.Errorf("no data found in sheet")
+	}
+
+	// Load topic rules; tagging is skipped when no rules are available
+	classifier, err := LoadClassifier(DefaultTopicRulesPath)
+	if err != nil {
+		log.Printf("Warning: Topic classification disabled: %v\n", err)
+		classifier = nil
+	}
+
+	return snapshotInputs{
+		providerRows: providerRows,
+		articleRows:  articleRows,
+		aliases:      loadDefaultSourceAliases(),
+		classifier:   classifier,
+		ageBuckets:   loadDefaultAgeBucketScheme(),
+	}, nil
+}
+
+// buildSnapshot computes a snapshot from article rows as of the given date (zero means now).
+// sheetRows maps each of a rewound sheet's rows back to its sheet row for the quality report;
+// nil means articleRows is the sheet as read.
+func buildSnapshot(inputs snapshotInputs, articleRows [][]interface{}, sheetRows []int, asOf time.Time) schema.Metrics {
+	providerRows := inputs.providerRows
+
+	// Build normalization map from the alias config and providers
+	sourceMap := BuildSourceMap(providerRows, inputs.aliases)
+
+	// Map Substack provider hosts to publication names
+	publications := BuildSubstackPublicationMap(providerRows)
 
 	// Initialize metrics
 	metrics := schema.Metrics{
@@ -576,12 +759,15 @@ func fetchMetricsWithFetcher(spreadsheetID string, fetcher SheetsFetcher) (schem
 		ByMonthAndSource:             make(map[string]map[string][2]int),
 		ByCategory:                   make(map[string][2]int),
 		ByCategoryAndSource:          make(map[string]map[string][2]int),
+		ByTopic:                      make(map[string][2]int),
+		BySubstackPublication:        make(map[string][2]int),
 		UnreadByMonth:                make(map[string]int),
 		UnreadByCategory:             make(map[string]int),
 		UnreadBySource:               make(map[string]int),
 		UnreadByYear:                 make(map[string]int),
 		UnreadArticleAgeDistribution: make(map[string]int),
 		SourceMetadata:               make(map[string]schema.SourceMeta),
+		SourceHealth:                 make(map[string]schema.SourceHealth),
 	}
 
 	// Populate source metadata and count Substack authors
@@ -616,47 +802,119 @@ func fetchMetricsWithFetcher(spreadsheetID string, fetcher SheetsFetcher) (schem
 		}
 	}
 
-	// Read all articles data
-	articleRows, err := fetcher.GetArticleRows(spreadsheetID, articlesSheet)
-	if err != nil {
-		return schema.Metrics{}, fmt.Errorf("unable to retrieve data from sheet: %w", err)
-	}
+	// Flag sources the alias config marks as retired
+	MarkRetiredSources(&metrics, inputs.aliases.RetiredSources())
 
-	if len(articleRows) == 0 {
-		return schema.Metrics{}, fmt.Errorf("no data found in sheet")
+	// The snapshot records the age bucket scheme it was built with
+	ageBuckets := inputs.ageBuckets
+	if ageBuckets == nil {
+		ageBuckets = defaultAgeBucketScheme
 	}
+	metrics.AgeBuckets = ageBuckets.Buckets()
 
 	var earliestDate, latestDate time.Time
+	now := resolveAsOf(asOf)
+
+	// Audit rows against the providers sheet while processing
+	quality := newQualityCollector(providerRows, sourceMap, now)
+
+	// Detect re-ingested and syndicated copies of the same post
+	duplicates := detectDuplicates(articleRows, sheetRows, sourceMap)
+	metrics.DuplicatesMerged = len(duplicates.merges)
+	quality.report.Duplicates = duplicates.merges
 
 	// Process all articles
-	unreadArticles, oldestUnreadArticle := processArticleRows(articleRows, &metrics, &earliestDate, &latestDate, sourceMap)
+	unreadArticles, oldestUnreadArticle := processArticleRows(articleRows, &metrics, &earliestDate, &latestDate, rowProcessingOptions{
+		sourceMap:    sourceMap,
+		classifier:   inputs.classifier,
+		publications: publications,
+		quality:      quality,
+		skipRows:     duplicates.skip,
+		sheetRows:    sheetRows,
+		ageBuckets:   ageBuckets,
+		asOf:         now,
+	})
 
 	// Calculate derived metrics
-	calculateDerivedMetrics(&metrics, earliestDate, latestDate)
+	calculateDerivedMetrics(&metrics, earliestDate, latestDate, now)
+	calculateSourceHealth(&metrics, snapshotDate(now))
 
 	// Populate read/unread totals
 	metrics.ReadUnreadTotals = [2]int{metrics.ReadCount, metrics.UnreadCount}
 
 	// Populate top articles
 	populateTopArticles(&metrics, unreadArticles, oldestUnreadArticle)
+	populateSubstackBacklog(&metrics, unreadArticles, publications)
 
 	// Store substack count for later use in display
 	metrics.BySourceReadStatus["substack_author_count"] = [2]int{substackCount, 0}
 
 	// Set timestamp
-	metrics.LastUpdated = time.Now()
+	metrics.LastUpdated = now
+
+	// Attach the data-quality report for the caller to persist
+	quality.report.SnapshotDate = snapshotDate(now).Format("2006-01-02")
+	metrics.DataQuality = quality.report
+
+	return metrics
+}
+
+// fetchArticlesWithFetcher reads every valid article row as ArticleMeta with normalized source names
+func fetchArticlesWithFetcher(spreadsheetID string, fetcher SheetsFetcher) ([]schema.ArticleMeta, error) {
+	spreadsheet, err := fetcher.GetSpreadsheet(spreadsheetID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve spreadsheet: %w", err)
+	}
+
+	articlesSheet, providersSheet := findSheetNames(spreadsheet)
+
+	providerRows, err := fetcher.GetProvidersSheet(spreadsheetID, providersSheet)
+	if err != nil {
+		log.Printf("Warning: Unable to read providers sheet: %v\n", err)
+	}
+	sourceMap := BuildSourceMap(providerRows, loadDefaultSourceAliases())
+
+	articleRows, err := fetcher.GetArticleRows(spreadsheetID, articlesSheet)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve data from sheet: %w", err)
+	}
+
+	var articles []schema.ArticleMeta
+	for i := 1; i < len(articleRows); i++ {
+		article, err := parseArticleRowWithDetails(articleRows[i], sourceMap)
+		if err != nil {
+			continue
+		}
+		articles = append(articles, *article)
+	}
 
-	return metrics, nil
+	return articles, nil
+}
+
+// FetchArticlesFromSheets creates a Sheets service and returns all article rows as ArticleMeta
+func FetchArticlesFromSheets(ctx context.Context, spreadsheetID, credentialsPath string) ([]schema.ArticleMeta, error) {
+	client, err := sheets.NewService(ctx, option.WithCredentialsFile(credentialsPath))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create sheets client: %w", err)
+	}
+
+	return fetchArticlesWithFetcher(spreadsheetID, &SheetServiceFetcher{service: client})
 }
 
 // FetchMetricsFromSheets is a backward-compatible wrapper that creates a Sheets service
 // and delegates to FetchMetricsFromSheetsWithService.
 func FetchMetricsFromSheets(ctx context.Context, spreadsheetID, credentialsPath string) (schema.Metrics, error) {
+	return FetchMetricsFromSheetsAsOf(ctx, spreadsheetID, credentialsPath, time.Time{})
+}
+
+// FetchMetricsFromSheetsAsOf creates a Sheets service and builds a snapshot dated asOf.
+// A zero asOf means now.
+func FetchMetricsFromSheetsAsOf(ctx context.Context, spreadsheetID, credentialsPath string, asOf time.Time) (schema.Metrics, error) {
 	// Create Sheets service
 	client, err := sheets.NewService(ctx, option.WithCredentialsFile(credentialsPath))
 	if err != nil {
 		return schema.Metrics{}, fmt.Errorf("unable to create sheets client: %w", err)
 	}
 
-	return FetchMetricsFromSheetsWithService(ctx, client, spreadsheetID)
+	return FetchMetricsFromSheetsWithService(ctx, client, spreadsheetID, asOf)
 }
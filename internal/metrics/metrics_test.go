@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -343,44 +344,80 @@ func TestCountSubstackProviders(t *testing.T) {
 // BuildSourceMap: creates a normalization map from the providers sheet data
 // ============================================================================
 
+// createTestSourceAliases mirrors the canonical sources used across the metrics tests
+func createTestSourceAliases() SourceAliasConfig {
+	return SourceAliasConfig{
+		Sources: []SourceAlias{
+			{Name: "Substack"},
+			{Name: "freeCodeCamp", Aliases: []string{"free code camp", "fcc"}},
+			{Name: "GitHub", PreviousNames: []string{"GitHub Engineering"}},
+			{Name: "Shopify"},
+			{Name: "Stripe", Retired: "2026-01-01"},
+		},
+	}
+}
+
+// createTestSourceMap builds the normalization map for the test aliases
+func createTestSourceMap() map[string]string {
+	return BuildSourceMap(nil, createTestSourceAliases())
+}
+
 func TestBuildSourceMap(t *testing.T) {
 	tests := []struct {
 		name     string
 		rows     [][]interface{}
+		aliases  SourceAliasConfig
 		expected map[string]string
 	}{
 		{
-			name: "custom providers added to default map",
+			name: "providers and configured aliases",
 			rows: [][]interface{}{
 				{"Name", "URL", "Element", "Strategy", "Color", "Added"},
 				{"MyBlog", "url1", "e1", "html", "c1", "a1"},
 				{"another-blog", "url2", "e2", "rss", "c2", "a2"},
 			},
+			aliases: createTestSourceAliases(),
 			expected: map[string]string{
-				"substack":     "Substack",
-				"freecodecamp": "freeCodeCamp",
-				"github":       "GitHub",
-				"shopify":      "Shopify",
-				"stripe":       "Stripe",
-				"myblog":       "MyBlog",
-				"another-blog": "another-blog",
+				"substack":           "Substack",
+				"freecodecamp":       "freeCodeCamp",
+				"free code camp":     "freeCodeCamp",
+				"fcc":                "freeCodeCamp",
+				"github":             "GitHub",
+				"github engineering": "GitHub",
+				"shopify":            "Shopify",
+				"stripe":             "Stripe",
+				"myblog":             "MyBlog",
+				"another-blog":       "another-blog",
+			},
+		},
+		{
+			name: "configured canonical name wins over provider casing",
+			rows: [][]interface{}{
+				{"Name", "URL", "Element", "Strategy", "Color", "Added"},
+				{"GITHUB", "url1", "e1", "html", "c1", "a1"},
+			},
+			aliases: createTestSourceAliases(),
+			expected: map[string]string{
+				"github": "GitHub",
 			},
 		},
 		{
-			name: "overwrites default with custom casing",
+			name: "provider casing kept without aliases",
 			rows: [][]interface{}{
 				{"Name", "URL", "Element", "Strategy", "Color", "Added"},
 				{"GITHUB", "url1", "e1", "html", "c1", "a1"},
 			},
+			aliases: SourceAliasConfig{},
 			expected: map[string]string{
 				"github": "GITHUB",
 			},
 		},
 		{
-			name: "empty or only header returns default map",
+			name: "only header returns configured aliases",
 			rows: [][]interface{}{
 				{"Name", "URL"},
 			},
+			aliases: createTestSourceAliases(),
 			expected: map[string]string{
 				"substack":     "Substack",
 				"freecodecamp": "freeCodeCamp",
@@ -393,7 +430,7 @@ func TestBuildSourceMap(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildSourceMap(tt.rows)
+			result := BuildSourceMap(tt.rows, tt.aliases)
 			for k, v := range tt.expected {
 				if result[k] != v {
 					t.Errorf("BuildSourceMap()[%q] = %q, want %q", k, result[k], v)
@@ -408,7 +445,9 @@ func TestBuildSourceMap(t *testing.T) {
 // ============================================================================
 
 func TestNormalizeSourceName(t *testing.T) {
-	t.Run("default map", func(t *testing.T) {
+	t.Run("configured aliases", func(t *testing.T) {
+		sourceMap := BuildSourceMap(nil, createTestSourceAliases())
+
 		tests := []struct {
 			input    string
 			expected string
@@ -418,14 +457,33 @@ func TestNormalizeSourceName(t *testing.T) {
 			{"Substack", "Substack"},
 			{"freecodecamp", "freeCodeCamp"},
 			{"FREECODECAMP", "freeCodeCamp"},
+			{"Free  Code Camp", "freeCodeCamp"},
+			{" fcc ", "freeCodeCamp"},
 			{"github", "GitHub"},
-			{"GITHUB", "GitHub"},
+			{"GitHub Engineering", "GitHub"},
 			{"shopify", "Shopify"},
 			{"stripe", "Stripe"},
 			{"Unknown", "Unknown"},
 			{"medium", "medium"},
 		}
 
+		for _, tt := range tests {
+			result := NormalizeSourceName(tt.input, sourceMap)
+			if result != tt.expected {
+				t.Errorf("NormalizeSourceName(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		}
+	})
+
+	t.Run("nil map returns trimmed input", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected string
+		}{
+			{"substack", "substack"},
+			{" GitHub ", "GitHub"},
+		}
+
 		for _, tt := range tests {
 			result := NormalizeSourceName(tt.input, nil)
 			if result != tt.expected {
@@ -538,7 +596,7 @@ func TestParseArticleRow(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseArticleRow(tt.row, nil)
+			result, err := parseArticleRow(tt.row, createTestSourceMap())
 			if (err != nil) != tt.expectErr {
 				t.Errorf("parseArticleRow() error = %v, expectErr %v", err, tt.expectErr)
 				return
@@ -606,7 +664,7 @@ func TestParseArticleRowWithDetails(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseArticleRowWithDetails(tt.row, nil)
+			result, err := parseArticleRowWithDetails(tt.row, createTestSourceMap())
 			if (err != nil) != tt.expectErr {
 				t.Errorf("parseArticleRowWithDetails() error = %v, expectErr %v", err, tt.expectErr)
 				return
@@ -693,6 +751,26 @@ func TestUpdateMetricsBySource(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// updateMetricsBySourceAndMonth: Tracks read status per source and month
+// ============================================================================
+
+func TestUpdateMetricsBySourceAndMonth(t *testing.T) {
+	metrics := &schema.Metrics{}
+
+	updateMetricsBySourceAndMonth(metrics, &ParsedArticle{Category: "GitHub", Date: time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC), IsRead: true})
+	updateMetricsBySourceAndMonth(metrics, &ParsedArticle{Category: "GitHub", Date: time.Date(2025, 11, 20, 0, 0, 0, 0, time.UTC)})
+	updateMetricsBySourceAndMonth(metrics, &ParsedArticle{Category: "GitHub", Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)})
+	updateMetricsBySourceAndMonth(metrics, &ParsedArticle{Category: "", Date: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)})
+
+	expected := map[string]map[string][2]int{
+		"GitHub": {"2025-11": {1, 1}, "2025-12": {0, 1}},
+	}
+	if !reflect.DeepEqual(metrics.BySourceAndMonth, expected) {
+		t.Errorf("updateMetricsBySourceAndMonth() = %v, want %v", metrics.BySourceAndMonth, expected)
+	}
+}
+
 // ============================================================================
 // updateMetricsByCategory: Updates category-level aggregate metrics
 // ============================================================================
@@ -1595,7 +1673,7 @@ func TestProcessArticleRows(t *testing.T) {
 			}
 
 			var earliestDate, latestDate time.Time
-			unread, oldest := processArticleRows(tt.rows, &metrics, &earliestDate, &latestDate, nil)
+			unread, oldest := processArticleRows(tt.rows, &metrics, &earliestDate, &latestDate, rowProcessingOptions{sourceMap: createTestSourceMap()})
 
 			if !tt.validate(&metrics, unread, oldest) {
 				t.Errorf("%s: validation failed", tt.name)
@@ -1676,7 +1754,7 @@ func TestCalculateDerivedMetrics(t *testing.T) {
 				ReadCount:     tt.readCount,
 			}
 
-			calculateDerivedMetrics(&metrics, tt.earliestDate, tt.latestDate)
+			calculateDerivedMetrics(&metrics, tt.earliestDate, tt.latestDate, time.Time{})
 
 			if metrics.ReadRate != tt.expectedReadRate {
 				t.Errorf("Expected read rate %.1f%%, got %.1f%%", tt.expectedReadRate, metrics.ReadRate)
@@ -1729,7 +1807,10 @@ func TestPopulateTopArticles(t *testing.T) {
 			validate: func(m *schema.Metrics) bool {
 				return len(m.TopOldestUnreadArticles) == 3 && // TopUnreadArticlesCount = 3
 					m.OldestUnreadArticle != nil &&
-					m.OldestUnreadArticle.Date == "2025-08-10"
+					m.OldestUnreadArticle.Date == "2025-08-10" &&
+					len(m.SourceBacklog["Substack"]) == 2 &&
+					len(m.SourceBacklog["Stripe"]) == 1 &&
+					len(m.YearBacklog["2025"]) == 5
 			},
 		},
 		{
@@ -1756,6 +1837,37 @@ func TestPopulateTopArticles(t *testing.T) {
 	}
 }
 
+func TestPopulateSubstackBacklog(t *testing.T) {
+	unread := []schema.ArticleMeta{
+		{Date: "2025-01-10", Title: "A1", Link: "https://alpha.substack.com/p/one", Category: "Substack"},
+		{Date: "2025-02-10", Title: "G1", Link: "https://github.blog/post", Category: "GitHub"},
+		{Date: "2025-03-10", Title: "B1", Link: "https://substack.com/@beta/p/two", Category: "Substack"},
+		{Date: "2025-04-10", Title: "X1", Link: "", Category: "Substack"},
+	}
+	for i := 0; i < SubstackBacklogCount+2; i++ {
+		unread = append(unread, schema.ArticleMeta{Date: "2025-05-10", Title: "A", Link: "https://alpha.substack.com/p/more", Category: "Substack"})
+	}
+
+	metrics := schema.Metrics{}
+	populateSubstackBacklog(&metrics, unread, nil)
+
+	if len(metrics.SubstackBacklog["alpha"]) != SubstackBacklogCount || metrics.SubstackBacklog["alpha"][0].Title != "A1" {
+		t.Errorf("expected the %d oldest alpha articles, got %+v", SubstackBacklogCount, metrics.SubstackBacklog["alpha"])
+	}
+	if len(metrics.SubstackBacklog["beta"]) != 1 || len(metrics.SubstackBacklog[UnknownPublication]) != 1 {
+		t.Errorf("unexpected backlogs: %+v", metrics.SubstackBacklog)
+	}
+	if len(metrics.SubstackBacklog) != 3 {
+		t.Errorf("expected only Substack articles, got %d publications", len(metrics.SubstackBacklog))
+	}
+
+	empty := schema.Metrics{}
+	populateSubstackBacklog(&empty, unread[1:2], nil)
+	if empty.SubstackBacklog != nil {
+		t.Errorf("expected no backlog without Substack articles, got %+v", empty.SubstackBacklog)
+	}
+}
+
 // ============================================================================
 // SheetsFetcher Mock: For testing sheet operations
 // ============================================================================
@@ -2052,7 +2164,7 @@ func TestFetchMetricsWithFetcher(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			metrics, err := fetchMetricsWithFetcher("spreadsheetID", tt.fetcher)
+			metrics, err := fetchMetricsWithFetcher("spreadsheetID", tt.fetcher, time.Time{})
 
 			if tt.expectErr && err == nil {
 				t.Errorf("%s: expected error, got nil", tt.name)
@@ -2070,6 +2182,128 @@ func TestFetchMetricsWithFetcher(t *testing.T) {
 	}
 }
 
+func TestFetchMetricsWithFetcherAsOf(t *testing.T) {
+	fetcher := &MockSheetsFetcher{
+		spreadsheet: &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{Title: "Articles"}},
+				{Properties: &sheets.SheetProperties{Title: "Providers"}},
+			},
+		},
+		articleRows: [][]interface{}{
+			{"Date", "Title", "Link", "Category", "Read"},
+			{"2025-01-10", "Old", "https://example.com/a", "GitHub", "FALSE"},
+			{"2025-03-05", "Recent", "https://example.com/b", "GitHub", "FALSE"},
+		},
+		providerRows: [][]interface{}{{"Name"}, {"GitHub"}},
+	}
+
+	asOf := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	metrics, err := fetchMetricsWithFetcher("spreadsheetID", fetcher, asOf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !metrics.LastUpdated.Equal(asOf) {
+		t.Errorf("expected LastUpdated %v, got %v", asOf, metrics.LastUpdated)
+	}
+	if metrics.DataQuality == nil || metrics.DataQuality.SnapshotDate != "2025-03-15" {
+		t.Errorf("expected quality report dated 2025-03-15, got %+v", metrics.DataQuality)
+	}
+	if metrics.UnreadArticleAgeDistribution["less_than_1_month"] != 1 || metrics.UnreadArticleAgeDistribution["1_to_3_months"] != 1 {
+		t.Errorf("expected ages measured from the as-of date, got %v", metrics.UnreadArticleAgeDistribution)
+	}
+
+	// Jan to Mar with half of March elapsed
+	if metrics.AvgArticlesPerMonth < 0.8 || metrics.AvgArticlesPerMonth > 0.85 {
+		t.Errorf("expected partial-month average from the as-of date, got %.3f", metrics.AvgArticlesPerMonth)
+	}
+}
+
+func TestFetchMetricsWithFetcherAsOfRewindsRows(t *testing.T) {
+	fetcher := &MockSheetsFetcher{
+		spreadsheet: &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{Title: "Articles"}},
+				{Properties: &sheets.SheetProperties{Title: "Providers"}},
+			},
+		},
+		articleRows: [][]interface{}{
+			{"Date", "Title", "Link", "Category", "Read", "Read Date"},
+			{"2025-01-10", "Read early", "https://example.com/a", "GitHub", "TRUE", "2025-02-01"},
+			{"2025-02-20", "Read later", "https://example.com/b", "GitHub", "TRUE", "2025-04-02"},
+			{"2025-04-01", "Added later", "https://example.com/c", "GitHub", "FALSE"},
+		},
+		providerRows: [][]interface{}{{"Name"}, {"GitHub"}},
+	}
+
+	metrics, err := fetchMetricsWithFetcher("spreadsheetID", fetcher, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if metrics.TotalArticles != 2 {
+		t.Errorf("expected the row added after the as-of date to be dropped, got %d articles", metrics.TotalArticles)
+	}
+	if metrics.ReadCount != 1 || metrics.UnreadCount != 1 {
+		t.Errorf("expected the row read after the as-of date to be unread, got %d read / %d unread", metrics.ReadCount, metrics.UnreadCount)
+	}
+	if metrics.DataQuality.ByKind[IssueFutureDate] != 0 {
+		t.Errorf("expected no future_date findings, got %+v", metrics.DataQuality.Findings)
+	}
+	if metrics.Reconstructed != nil {
+		t.Error("expected an as-of fetch not to be marked as backfilled")
+	}
+}
+
+func TestFetchMetricsWithFetcherAsOfAuditsSheetRows(t *testing.T) {
+	fetcher := &MockSheetsFetcher{
+		spreadsheet: &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{Title: "Articles"}},
+				{Properties: &sheets.SheetProperties{Title: "Providers"}},
+			},
+		},
+		articleRows: [][]interface{}{
+			{"Date", "Title", "Link", "Category", "Read"},
+			{"2025-04-01", "Added later", "https://example.com/a", "GitHub", "FALSE"},
+			{"2025-01-10", "Short"},
+			{"12/03/2025", "Bad date", "https://example.com/b", "GitHub", "FALSE"},
+			{"2025-02-01", "Kept", "https://example.com/c", "GitHub", "FALSE"},
+			{"2025-02-02", "Copy", "https://example.com/c", "GitHub", "FALSE"},
+		},
+		providerRows: [][]interface{}{{"Name"}, {"GitHub"}},
+	}
+
+	metrics, err := fetchMetricsWithFetcher("spreadsheetID", fetcher, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Malformed rows are rejected as they would be without -date, so -strict still fails on them
+	report := metrics.DataQuality
+	if report.RowsRejected != 2 {
+		t.Fatalf("expected 2 rejected rows, got %d: %+v", report.RowsRejected, report.Issues)
+	}
+	if report.Issues[0].Row != 3 || report.Issues[0].Kind != IssueIncompleteRow {
+		t.Errorf("expected incomplete_row on sheet row 3, got %+v", report.Issues[0])
+	}
+	if report.Issues[1].Row != 4 || report.Issues[1].Kind != IssueInvalidDate {
+		t.Errorf("expected invalid_date on sheet row 4, got %+v", report.Issues[1])
+	}
+
+	// Findings and merges point at sheet rows too, not at the rewound rows
+	if len(report.Findings) != 1 || report.Findings[0].Row != 6 {
+		t.Errorf("expected a duplicate_link finding on sheet row 6, got %+v", report.Findings)
+	}
+	if len(report.Duplicates) != 1 || report.Duplicates[0].KeptRow != 5 || report.Duplicates[0].DroppedRow != 6 {
+		t.Errorf("expected rows 5 and 6 merged, got %+v", report.Duplicates)
+	}
+	if metrics.TotalArticles != 1 {
+		t.Errorf("expected 1 article, got %d", metrics.TotalArticles)
+	}
+}
+
 // ============================================================================
 // FetchMetricsFromSheets: Retrieves and calculates metrics from Google Sheets
 // ============================================================================
@@ -2110,7 +2344,7 @@ func TestFetchMetricsFromSheetsWithService(t *testing.T) {
 
 				// Parse articles from rows (skip header row 0)
 				for i := 1; i < len(rows); i++ {
-					article, err := parseArticleRow(rows[i], nil)
+					article, err := parseArticleRow(rows[i], createTestSourceMap())
 					if err != nil {
 						continue
 					}
@@ -2176,7 +2410,7 @@ func TestFetchMetricsFromSheetsWithService(t *testing.T) {
 
 				// Get latest date from all articles
 				for i := 1; i < len(rows); i++ {
-					article, err := parseArticleRow(rows[i], nil)
+					article, err := parseArticleRow(rows[i], createTestSourceMap())
 					if err != nil {
 						continue
 					}
@@ -2187,7 +2421,7 @@ func TestFetchMetricsFromSheetsWithService(t *testing.T) {
 
 				// Process unread articles for age distribution
 				for i := 1; i < len(rows); i++ {
-					article, err := parseArticleRow(rows[i], nil)
+					article, err := parseArticleRow(rows[i], createTestSourceMap())
 					if err != nil {
 						continue
 					}
@@ -2248,7 +2482,7 @@ func TestFetchMetricsFromSheetsWithService(t *testing.T) {
 
 				// Process articles
 				for i := 1; i < len(rows); i++ {
-					article, err := parseArticleRow(rows[i], nil)
+					article, err := parseArticleRow(rows[i], createTestSourceMap())
 					if err != nil {
 						continue
 					}
@@ -2345,7 +2579,7 @@ func TestFetchMetricsFromSheets(t *testing.T) {
 
 				// Simulate FetchMetricsFromSheetsWithService processing (skip header at index 0)
 				for i := 1; i < len(rows); i++ {
-					article, err := parseArticleRow(rows[i], nil)
+					article, err := parseArticleRow(rows[i], createTestSourceMap())
 					if err != nil {
 						continue
 					}
@@ -2424,7 +2658,7 @@ func TestFetchMetricsFromSheets(t *testing.T) {
 
 				// Process rows
 				for i := 1; i < len(rows); i++ {
-					article, err := parseArticleRow(rows[i], nil)
+					article, err := parseArticleRow(rows[i], createTestSourceMap())
 					if err != nil {
 						continue
 					}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// OpenMetricsContentType is the content type of the text WriteOpenMetrics produces
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// openMetricsLabel is one name="value" pair on a sample
+type openMetricsLabel struct {
+	name, value string
+}
+
+// openMetricsSample is one value of a gauge family
+type openMetricsSample struct {
+	labels []openMetricsLabel
+	value  float64
+}
+
+// escapeLabelValue escapes backslashes, quotes and newlines as OpenMetrics requires
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// writeGauge writes one gauge family: its TYPE and HELP lines followed by its samples
+func writeGauge(w *bufio.Writer, name, help string, samples []openMetricsSample) {
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	for _, sample := range samples {
+		w.WriteString(name)
+		if len(sample.labels) > 0 {
+			pairs := make([]string, 0, len(sample.labels))
+			for _, label := range sample.labels {
+				pairs = append(pairs, fmt.Sprintf(`%s="%s"`, label.name, escapeLabelValue(label.value)))
+			}
+			w.WriteString("{" + strings.Join(pairs, ",") + "}")
+		}
+		w.WriteString(" " + strconv.FormatFloat(sample.value, 'f', -1, 64) + "\n")
+	}
+}
+
+// readStatusSamples turns a key -> [read, unread] map into samples labelled by key and status, sorted by key
+func readStatusSamples(label string, counts map[string][2]int) []openMetricsSample {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	samples := make([]openMetricsSample, 0, len(keys)*2)
+	for _, key := range keys {
+		samples = append(samples,
+			openMetricsSample{labels: []openMetricsLabel{{label, key}, {"status", "read"}}, value: float64(counts[key][0])},
+			openMetricsSample{labels: []openMetricsLabel{{label, key}, {"status", "unread"}}, value: float64(counts[key][1])},
+		)
+	}
+	return samples
+}
+
+// WriteOpenMetrics renders a snapshot as OpenMetrics text: overall gauges, and article counts
+// labelled by source, publication year and unread age bucket
+func WriteOpenMetrics(out io.Writer, m schema.Metrics) error {
+	w := bufio.NewWriter(out)
+
+	writeGauge(w, "reading_articles", "Articles tracked in the snapshot.", []openMetricsSample{{value: float64(m.TotalArticles)}})
+	writeGauge(w, "reading_articles_read", "Articles marked read.", []openMetricsSample{{value: float64(m.ReadCount)}})
+	writeGauge(w, "reading_articles_unread", "Articles not yet read.", []openMetricsSample{{value: float64(m.UnreadCount)}})
+	writeGauge(w, "reading_read_ratio", "Share of articles read, from 0 to 1.", []openMetricsSample{{value: m.ReadRate / 100}})
+	if !m.LastUpdated.IsZero() {
+		writeGauge(w, "reading_snapshot_timestamp_seconds", "Time the snapshot was taken, in Unix seconds.", []openMetricsSample{{value: float64(m.LastUpdated.Unix())}})
+	}
+
+	bySource := make(map[string][2]int, len(m.BySourceReadStatus))
+	for source, counts := range m.BySourceReadStatus {
+		if source != "substack_author_count" {
+			bySource[source] = counts
+		}
+	}
+	writeGauge(w, "reading_source_articles", "Articles per source and read status.", readStatusSamples("source", bySource))
+
+	byYear := make(map[string][2]int, len(m.ByYear))
+	for year, total := range m.ByYear {
+		unread := m.UnreadByYear[year]
+		byYear[year] = [2]int{total - unread, unread}
+	}
+	writeGauge(w, "reading_year_articles", "Articles per publication year and read status.", readStatusSamples("year", byYear))
+
+	// Age buckets keep the order the snapshot declares
+	buckets := m.AgeBuckets
+	if len(buckets) == 0 {
+		buckets = DefaultAgeBuckets()
+	}
+	ageSamples := make([]openMetricsSample, 0, len(buckets))
+	for _, bucket := range buckets {
+		ageSamples = append(ageSamples, openMetricsSample{
+			labels: []openMetricsLabel{{"bucket", bucket.Key}},
+			value:  float64(m.UnreadArticleAgeDistribution[bucket.Key]),
+		})
+	}
+	writeGauge(w, "reading_unread_age_articles", "Unread articles per age bucket.", ageSamples)
+
+	w.WriteString("# EOF\n")
+	return w.Flush()
+}
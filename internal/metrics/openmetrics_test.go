@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	m := schema.Metrics{
+		TotalArticles: 10,
+		ReadCount:     4,
+		UnreadCount:   6,
+		ReadRate:      40,
+		LastUpdated:   time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC),
+		BySourceReadStatus: map[string][2]int{
+			"Stripe":                {1, 2},
+			"GitHub":                {3, 4},
+			`Odd "name"`:            {0, 0},
+			"substack_author_count": {9, 0},
+		},
+		ByYear:                       map[string]int{"2025": 7, "2026": 3},
+		UnreadByYear:                 map[string]int{"2025": 5},
+		AgeBuckets:                   []schema.AgeBucket{{Key: "fresh"}, {Key: "stale", MinAge: "1y"}},
+		UnreadArticleAgeDistribution: map[string]int{"fresh": 2, "stale": 4},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteOpenMetrics(&buf, m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"# TYPE reading_articles gauge\n# HELP reading_articles Articles tracked in the snapshot.\nreading_articles 10\n",
+		"reading_read_ratio 0.4\n",
+		"reading_snapshot_timestamp_seconds 1767916800\n",
+		`reading_source_articles{source="GitHub",status="read"} 3` + "\n" + `reading_source_articles{source="GitHub",status="unread"} 4`,
+		`reading_source_articles{source="Odd \"name\"",status="read"} 0`,
+		`reading_year_articles{year="2025",status="read"} 2`,
+		`reading_year_articles{year="2026",status="unread"} 0`,
+		`reading_unread_age_articles{bucket="fresh"} 2` + "\n" + `reading_unread_age_articles{bucket="stale"} 4`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "substack_author_count") {
+		t.Error("expected the Substack author count to be left out")
+	}
+	if !strings.HasSuffix(output, "# EOF\n") {
+		t.Error("expected output to end with # EOF")
+	}
+	if strings.Index(output, `source="GitHub"`) > strings.Index(output, `source="Stripe"`) {
+		t.Error("expected sources in sorted order")
+	}
+}
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// Data-quality issue kinds recorded while processing the articles sheet. Rejected rows are left
+// out of the snapshot and listed as issues; the other kinds are informational findings on rows
+// that are still counted.
+const (
+	IssueIncompleteRow     = "incomplete_row"      // rejected: fewer columns than expected
+	IssueInvalidDate       = "invalid_date"        // rejected: date is not YYYY-MM-DD
+	IssueMissingLink       = "missing_link"        // finding: no link to the article
+	IssueUnknownSource     = "unknown_source"      // finding: source is not in the providers sheet
+	IssueDuplicateLink     = "duplicate_link"      // finding: link already seen on an earlier row
+	IssueFutureDate        = "future_date"         // finding: dated after the snapshot
+	IssueInvalidReadStatus = "invalid_read_status" // finding: read value is not TRUE/FALSE
+
+	// QualityReportSuffix replaces ".json" in a snapshot filename to name its quality report
+	QualityReportSuffix = ".quality.json"
+)
+
+// Snapshot filename layouts: one snapshot per day, or timestamped intra-day snapshots
+const (
+	SnapshotDateLayout      = "2006-01-02"        // YYYY-MM-DD.json
+	SnapshotTimestampLayout = "2006-01-02T150405" // YYYY-MM-DDTHHMMSS.json
+)
+
+// ParseSnapshotFilename returns the time a snapshot filename encodes: midnight UTC for daily
+// snapshots and the time of day for intra-day ones. ok is false for any other file.
+func ParseSnapshotFilename(name string) (t time.Time, ok bool) {
+	if !strings.HasSuffix(name, ".json") {
+		return time.Time{}, false
+	}
+	stem := strings.TrimSuffix(name, ".json")
+	for _, layout := range []string{SnapshotDateLayout, SnapshotTimestampLayout} {
+		if t, err := time.Parse(layout, stem); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// IsSnapshotFilename reports whether a metrics directory entry is a snapshot (YYYY-MM-DD.json or
+// YYYY-MM-DDTHHMMSS.json). Quality reports and other sidecar files sharing the directory are excluded.
+func IsSnapshotFilename(name string) bool {
+	_, ok := ParseSnapshotFilename(name)
+	return ok
+}
+
+// SnapshotFileDate returns the YYYY-MM-DD date of a snapshot filename
+func SnapshotFileDate(name string) string {
+	if len(name) < len(SnapshotDateLayout) {
+		return name
+	}
+	return name[:len(SnapshotDateLayout)]
+}
+
+// SnapshotFilename returns the filename a snapshot taken at t is saved under: its date, or its
+// date and time of day when several snapshots should be kept for one day
+func SnapshotFilename(t time.Time, intraday bool) string {
+	if intraday {
+		return t.Format(SnapshotTimestampLayout) + ".json"
+	}
+	return t.Format(SnapshotDateLayout) + ".json"
+}
+
+// QualityReportFilename returns the quality report filename for a snapshot filename
+func QualityReportFilename(snapshotFilename string) string {
+	return strings.TrimSuffix(snapshotFilename, ".json") + QualityReportSuffix
+}
+
+// qualityCollector accumulates data-quality issues while article rows are processed
+type qualityCollector struct {
+	report       *schema.QualityReport
+	knownSources map[string]bool // canonical provider names; nil disables the unknown source check
+	seenLinks    map[string]int  // link -> first sheet row it appeared on
+	asOf         time.Time       // when the snapshot is taken; rows dated after its day are flagged
+}
+
+// newQualityCollector creates a collector that checks sources against the providers sheet and
+// dates against the snapshot taken at asOf (zero means now)
+func newQualityCollector(providerRows [][]interface{}, sourceMap map[string]string, asOf time.Time) *qualityCollector {
+	var knownSources map[string]bool
+	if len(providerRows) > 1 {
+		knownSources = make(map[string]bool)
+		for i := 1; i < len(providerRows); i++ {
+			if len(providerRows[i]) > ProvidersColName {
+				knownSources[NormalizeSourceName(fmt.Sprintf("%v", providerRows[i][ProvidersColName]), sourceMap)] = true
+			}
+		}
+	}
+
+	return &qualityCollector{
+		report:       &schema.QualityReport{ByKind: make(map[string]int)},
+		knownSources: knownSources,
+		seenLinks:    make(map[string]int),
+		asOf:         asOf,
+	}
+}
+
+// add records a single issue against a sheet row: rejected rows as issues, the rest as findings
+func (q *qualityCollector) add(rowNumber int, row []interface{}, kind, detail string, rejected bool) {
+	issue := schema.QualityIssue{Row: rowNumber, Kind: kind, Detail: detail, Rejected: rejected}
+	if len(row) > ColTitle {
+		issue.Title = fmt.Sprintf("%v", row[ColTitle])
+	}
+	if len(row) > ColLink {
+		issue.Link = fmt.Sprintf("%v", row[ColLink])
+	}
+
+	if rejected {
+		q.report.Issues = append(q.report.Issues, issue)
+	} else {
+		q.report.Findings = append(q.report.Findings, issue)
+	}
+	q.report.ByKind[kind]++
+}
+
+// inspect checks one article row. article and parseErr are the result of parseArticleRow.
+// A nil collector ignores every row.
+func (q *qualityCollector) inspect(rowNumber int, row []interface{}, article *ParsedArticle, parseErr error) {
+	if q == nil {
+		return
+	}
+	q.report.RowsScanned++
+
+	if parseErr != nil {
+		q.report.RowsRejected++
+		if len(row) < ColRead+1 {
+			q.add(rowNumber, row, IssueIncompleteRow, parseErr.Error(), true)
+		} else {
+			q.add(rowNumber, row, IssueInvalidDate, parseErr.Error(), true)
+		}
+		return
+	}
+
+	link := strings.TrimSpace(fmt.Sprintf("%v", row[ColLink]))
+	if link == "" {
+		q.add(rowNumber, row, IssueMissingLink, "row has no link", false)
+	} else if firstRow, seen := q.seenLinks[link]; seen {
+		q.add(rowNumber, row, IssueDuplicateLink, fmt.Sprintf("link already used on row %d", firstRow), false)
+	} else {
+		q.seenLinks[link] = rowNumber
+	}
+
+	if q.knownSources != nil && !q.knownSources[article.Category] {
+		q.add(rowNumber, row, IssueUnknownSource, fmt.Sprintf("source %q is not in the providers sheet", article.Category), false)
+	}
+
+	if referenceDate := snapshotDate(q.asOf); article.Date.After(referenceDate) {
+		q.add(rowNumber, row, IssueFutureDate, fmt.Sprintf("dated %s, after %s", article.Date.Format("2006-01-02"), referenceDate.Format("2006-01-02")), false)
+	}
+
+	switch readStatus := fmt.Sprintf("%v", row[ColRead]); readStatus {
+	case "TRUE", "true", "FALSE", "false":
+	default:
+		q.add(rowNumber, row, IssueInvalidReadStatus, fmt.Sprintf("read value %q treated as unread", readStatus), false)
+	}
+}
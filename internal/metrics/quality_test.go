@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// ============================================================================
+// IsSnapshotFilename: Distinguishes snapshots from sidecar files
+// ============================================================================
+
+func TestIsSnapshotFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"2026-01-15.json", true},
+		{"2026-01-15T093000.json", true},
+		{"2026-01-15.quality.json", false},
+		{"2026-01-15T093000.quality.json", false},
+		{"2026-01-15T9.json", false},
+		{".gitkeep", false},
+		{"latest.json", false},
+		{"2026-01-15.txt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := IsSnapshotFilename(tt.name); result != tt.expected {
+				t.Errorf("IsSnapshotFilename(%q) = %v, want %v", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSnapshotFilename(t *testing.T) {
+	taken := time.Date(2026, 1, 15, 9, 30, 5, 0, time.UTC)
+	if result := SnapshotFilename(taken, false); result != "2026-01-15.json" {
+		t.Errorf("SnapshotFilename(daily) = %q, want %q", result, "2026-01-15.json")
+	}
+	name := SnapshotFilename(taken, true)
+	if name != "2026-01-15T093005.json" {
+		t.Errorf("SnapshotFilename(intraday) = %q, want %q", name, "2026-01-15T093005.json")
+	}
+
+	// The intra-day name round-trips to the time it was taken
+	parsed, ok := ParseSnapshotFilename(name)
+	if !ok || !parsed.Equal(taken) || SnapshotFileDate(name) != "2026-01-15" {
+		t.Errorf("ParseSnapshotFilename(%q) = %v, %v; date %q", name, parsed, ok, SnapshotFileDate(name))
+	}
+}
+
+func TestQualityReportFilename(t *testing.T) {
+	if result := QualityReportFilename("2026-01-15.json"); result != "2026-01-15.quality.json" {
+		t.Errorf("QualityReportFilename() = %q, want %q", result, "2026-01-15.quality.json")
+	}
+}
+
+// ============================================================================
+// qualityCollector: Records rejected and suspicious rows during processing
+// ============================================================================
+
+func TestProcessArticleRowsQualityReport(t *testing.T) {
+	providerRows := [][]interface{}{
+		{"Name", "URL"},
+		{"GitHub", "https://github.blog"},
+		{"Substack", "https://example.substack.com"},
+	}
+	rows := [][]interface{}{
+		{"Date", "Title", "Link", "Category", "Read"},
+		{"2025-12-01", "Good", "https://example.com/a", "GitHub", "TRUE"},
+		{"2025-12-02", "Short row"},
+		{"12/03/2025", "Bad date", "https://example.com/b", "GitHub", "FALSE"},
+		{"2025-12-04", "No link", "", "GitHub", "FALSE"},
+		{"2025-12-05", "Again", "https://example.com/a", "GitHub", "FALSE"},
+		{"2025-12-06", "Mystery", "https://example.com/c", "Medium", "FALSE"},
+		{"2026-03-01", "From the future", "https://example.com/d", "Substack", "FALSE"},
+		{"2025-12-07", "Checkbox typo", "https://example.com/e", "Substack", "yes"},
+	}
+
+	referenceDate := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	sourceMap := BuildSourceMap(providerRows, SourceAliasConfig{})
+	quality := newQualityCollector(providerRows, sourceMap, referenceDate)
+
+	metrics := schema.Metrics{
+		BySource:                     make(map[string]int),
+		BySourceReadStatus:           make(map[string][2]int),
+		ByYear:                       make(map[string]int),
+		ByMonth:                      make(map[string]int),
+		ByYearAndMonth:               make(map[string]map[string]int),
+		ByMonthAndSource:             make(map[string]map[string][2]int),
+		ByCategory:                   make(map[string][2]int),
+		UnreadByMonth:                make(map[string]int),
+		UnreadByCategory:             make(map[string]int),
+		UnreadBySource:               make(map[string]int),
+		UnreadByYear:                 make(map[string]int),
+		UnreadArticleAgeDistribution: make(map[string]int),
+	}
+
+	var earliestDate, latestDate time.Time
+	processArticleRows(rows, &metrics, &earliestDate, &latestDate, rowProcessingOptions{
+		sourceMap: sourceMap,
+		quality:   quality,
+		asOf:      referenceDate,
+	})
+
+	report := quality.report
+	if report.RowsScanned != 8 || report.RowsRejected != 2 {
+		t.Errorf("expected 8 scanned / 2 rejected, got %d / %d", report.RowsScanned, report.RowsRejected)
+	}
+	if metrics.TotalArticles != 6 {
+		t.Errorf("expected suspicious rows to still be counted (6 articles), got %d", metrics.TotalArticles)
+	}
+
+	expectedRows := map[string]int{
+		IssueIncompleteRow:     3,
+		IssueInvalidDate:       4,
+		IssueMissingLink:       5,
+		IssueDuplicateLink:     6,
+		IssueUnknownSource:     7,
+		IssueFutureDate:        8,
+		IssueInvalidReadStatus: 9,
+	}
+	for kind, row := range expectedRows {
+		if report.ByKind[kind] != 1 {
+			t.Errorf("expected 1 %s issue, got %d", kind, report.ByKind[kind])
+		}
+		// Rejected rows are issues, everything else an informational finding
+		expectRejected := kind == IssueIncompleteRow || kind == IssueInvalidDate
+		listed := report.Findings
+		if expectRejected {
+			listed = report.Issues
+		}
+		found := false
+		for _, issue := range listed {
+			if issue.Kind == kind && issue.Row == row {
+				found = true
+				if issue.Rejected != expectRejected {
+					t.Errorf("%s on row %d: rejected = %v, want %v", kind, row, issue.Rejected, expectRejected)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected %s on row %d, got issues %+v, findings %+v", kind, row, report.Issues, report.Findings)
+		}
+	}
+	if len(report.Issues) != 2 || len(report.Findings) != len(expectedRows)-2 {
+		t.Errorf("expected 2 issues and %d findings, got %d and %d", len(expectedRows)-2, len(report.Issues), len(report.Findings))
+	}
+}
+
+func TestQualityCollectorWithoutProviders(t *testing.T) {
+	quality := newQualityCollector(nil, nil, time.Time{})
+	article := &ParsedArticle{Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Category: "Anything"}
+
+	quality.inspect(2, []interface{}{"2025-01-01", "Title", "https://example.com", "Anything", "FALSE"}, article, nil)
+
+	if len(quality.report.Issues) != 0 || len(quality.report.Findings) != 0 {
+		t.Errorf("expected the source check to be skipped, got %+v", quality.report.Findings)
+	}
+}
+
+func TestQualityCollectorFutureDateUsesSnapshotClock(t *testing.T) {
+	// A zero asOf means now, the same clock the snapshot itself is built with
+	quality := newQualityCollector(nil, nil, time.Time{})
+	article := &ParsedArticle{Date: time.Now().AddDate(0, 0, 2), Category: "Anything"}
+
+	quality.inspect(2, []interface{}{article.Date.Format("2006-01-02"), "Title", "https://example.com", "Anything", "FALSE"}, article, nil)
+
+	if quality.report.ByKind[IssueFutureDate] != 1 {
+		t.Errorf("expected a future_date finding, got %+v", quality.report.Findings)
+	}
+}
+
+func TestNilQualityCollector(t *testing.T) {
+	var quality *qualityCollector
+	// Must not panic
+	quality.inspect(2, []interface{}{"bad"}, nil, nil)
+}
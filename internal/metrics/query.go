@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// Query output formats
+const (
+	QueryFormatTable = "table"
+	QueryFormatJSON  = "json"
+)
+
+// QueryResult is a small table of query output. Values are strings, ints or float64s.
+type QueryResult struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// formatQueryValue renders a cell for the table output
+func formatQueryValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case nil:
+		return "-"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Write renders the result as an aligned table or as a JSON array of objects keyed by column
+func (r QueryResult) Write(w io.Writer, format string) error {
+	switch format {
+	case QueryFormatJSON:
+		records := make([]map[string]any, 0, len(r.Rows))
+		for _, row := range r.Rows {
+			record := make(map[string]any, len(r.Columns))
+			for i, column := range r.Columns {
+				record[column] = row[i]
+			}
+			records = append(records, record)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case QueryFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(r.Columns, "\t"))
+		for _, row := range r.Rows {
+			cells := make([]string, len(row))
+			for i, value := range row {
+				cells[i] = formatQueryValue(value)
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q (use %s or %s)", format, QueryFormatTable, QueryFormatJSON)
+	}
+}
+
+// findSource returns the name a source is stored under in any snapshot of the series, matching
+// case-insensitively
+func findSource(series []schema.Metrics, source string) (string, bool) {
+	for i := len(series) - 1; i >= 0; i-- {
+		for name := range series[i].BySourceReadStatus {
+			if name != "substack_author_count" && sourceKey(name) == sourceKey(source) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// QueryUnread lists the unread count of every snapshot dated on or after since, overall or for one
+// source, with the change from the previous row. A zero since covers the whole series.
+func QueryUnread(series []schema.Metrics, source string, since time.Time) (QueryResult, error) {
+	result := QueryResult{Columns: []string{"snapshot_date", "unread", "change"}}
+	if source != "" {
+		name, ok := findSource(series, source)
+		if !ok {
+			return result, fmt.Errorf("source %q not found in any snapshot", source)
+		}
+		source = name
+		result.Columns = []string{"snapshot_date", "source", "unread", "change"}
+	}
+
+	var previous *int
+	for _, m := range series {
+		day := snapshotDay(m.LastUpdated)
+		if day.Before(since) {
+			continue
+		}
+
+		unread := m.UnreadCount
+		if source != "" {
+			unread = m.BySourceReadStatus[source][1]
+		}
+		var change any
+		if previous != nil {
+			change = unread - *previous
+		}
+		previous = &unread
+
+		row := []any{day.Format("2006-01-02")}
+		if source != "" {
+			row = append(row, source)
+		}
+		result.Rows = append(result.Rows, append(row, unread, change))
+	}
+	return result, nil
+}
+
+// QueryReadRate reports the read rate at the end of each of the last `weeks` calendar weeks, using
+// the last snapshot of each week. by groups the rates by a breakdown dimension (source, year, month
+// or category); empty reports the overall rate.
+func QueryReadRate(series []schema.Metrics, by string, weeks int) (QueryResult, error) {
+	switch by {
+	case "", DimensionSource, DimensionYear, DimensionMonth, DimensionCategory:
+	default:
+		return QueryResult{}, fmt.Errorf("cannot group read rate by %q (use %s, %s, %s or %s)", by, DimensionSource, DimensionYear, DimensionMonth, DimensionCategory)
+	}
+	if weeks < 1 {
+		return QueryResult{}, fmt.Errorf("weeks must be at least 1, got %d", weeks)
+	}
+
+	result := QueryResult{Columns: []string{"week_start", "snapshot_date", "read", "total", "read_rate"}}
+	if by != "" {
+		result.Columns = []string{"week_start", "snapshot_date", by, "read", "total", "read_rate"}
+	}
+	if len(series) == 0 {
+		return result, nil
+	}
+
+	// Keep the last snapshot of each week within the window
+	first := weekStart(snapshotDay(series[len(series)-1].LastUpdated)).AddDate(0, 0, -7*(weeks-1))
+	var weekly []schema.Metrics
+	for _, m := range series {
+		start := weekStart(snapshotDay(m.LastUpdated))
+		if start.Before(first) {
+			continue
+		}
+		if n := len(weekly); n > 0 && weekStart(snapshotDay(weekly[n-1].LastUpdated)).Equal(start) {
+			weekly[n-1] = m
+			continue
+		}
+		weekly = append(weekly, m)
+	}
+
+	rate := func(read, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(read) / float64(total) * 100
+	}
+	for _, m := range weekly {
+		day := snapshotDay(m.LastUpdated)
+		prefix := []any{weekStart(day).Format("2006-01-02"), day.Format("2006-01-02")}
+		if by == "" {
+			result.Rows = append(result.Rows, append(prefix, m.ReadCount, m.TotalArticles, rate(m.ReadCount, m.TotalArticles)))
+			continue
+		}
+		for _, row := range SnapshotBreakdown(m) {
+			if row.Dimension != by {
+				continue
+			}
+			total := row.Read + row.Unread
+			result.Rows = append(result.Rows, append(append([]any{}, prefix...), row.Key, row.Read, total, rate(row.Read, total)))
+		}
+	}
+	return result, nil
+}
+
+// QueryTopUnread lists up to n of the oldest unread articles the latest snapshot keeps, optionally
+// for one source. Snapshots only keep the oldest articles per source (SourceBacklogCount), so this
+// is the tail of the backlog rather than all of it.
+func QueryTopUnread(series []schema.Metrics, source string, n int) (QueryResult, error) {
+	result := QueryResult{Columns: []string{"published_date", "age_days", "source", "title", "link"}}
+	if n < 1 {
+		return result, fmt.Errorf("n must be at least 1, got %d", n)
+	}
+	if len(series) == 0 {
+		return result, nil
+	}
+	if source != "" {
+		name, ok := findSource(series, source)
+		if !ok {
+			return result, fmt.Errorf("source %q not found in any snapshot", source)
+		}
+		source = name
+	}
+
+	latest := series[len(series)-1]
+	articles := backlogArticles(latest)
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].Date < articles[j].Date
+	})
+
+	day := snapshotDay(latest.LastUpdated)
+	for _, article := range articles {
+		if source != "" && article.Category != source {
+			continue
+		}
+		var age any
+		if published, err := time.Parse("2006-01-02", article.Date); err == nil {
+			age = int(day.Sub(published).Hours() / 24)
+		}
+		result.Rows = append(result.Rows, []any{article.Date, age, article.Category, article.Title, article.Link})
+		if len(result.Rows) == n {
+			break
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+func querySeries() []schema.Metrics {
+	series := []schema.Metrics{
+		digestSnapshot("2026-03-02", 100, 40, map[string][2]int{"GitHub": {30, 30}, "Stripe": {10, 30}}, nil),
+		digestSnapshot("2026-03-04", 104, 44, map[string][2]int{"GitHub": {33, 31}, "Stripe": {11, 29}}, nil),
+		digestSnapshot("2026-03-10", 110, 50, map[string][2]int{"GitHub": {36, 32}, "Stripe": {14, 28}, "substack_author_count": {9, 0}}, nil),
+	}
+	series[2].TopOldestUnreadArticles = []schema.ArticleMeta{
+		{Date: "2025-03-10", Title: "Old GitHub", Link: "https://a.example/1", Category: "GitHub"},
+	}
+	series[2].SourceBacklog = map[string][]schema.ArticleMeta{
+		"GitHub": {{Date: "2025-03-10", Title: "Old GitHub", Link: "https://a.example/1", Category: "GitHub"}},
+		"Stripe": {
+			{Date: "2024-03-10", Title: "Older Stripe", Link: "https://b.example/1", Category: "Stripe"},
+			{Date: "2025-09-10", Title: "Newer Stripe", Link: "https://b.example/2", Category: "Stripe"},
+		},
+	}
+	return series
+}
+
+func TestQueryUnread(t *testing.T) {
+	series := querySeries()
+
+	t.Run("overall since a date", func(t *testing.T) {
+		result, err := QueryUnread(series, "", time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := [][]any{{"2026-03-04", 60, nil}, {"2026-03-10", 60, 0}}
+		if !reflect.DeepEqual(result.Rows, expected) {
+			t.Errorf("expected %v, got %v", expected, result.Rows)
+		}
+	})
+
+	t.Run("one source matched case-insensitively", func(t *testing.T) {
+		result, err := QueryUnread(series, "stripe", time.Time{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := [][]any{{"2026-03-02", "Stripe", 30, nil}, {"2026-03-04", "Stripe", 29, -1}, {"2026-03-10", "Stripe", 28, -1}}
+		if !reflect.DeepEqual(result.Rows, expected) {
+			t.Errorf("expected %v, got %v", expected, result.Rows)
+		}
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		if _, err := QueryUnread(series, "Nope", time.Time{}); err == nil {
+			t.Error("expected an error for an unknown source")
+		}
+	})
+}
+
+func TestQueryReadRate(t *testing.T) {
+	series := querySeries()
+
+	tests := []struct {
+		name        string
+		by          string
+		weeks       int
+		expected    [][]any
+		expectError bool
+	}{
+		{
+			name:  "overall keeps the last snapshot of each week",
+			weeks: 12,
+			expected: [][]any{
+				{"2026-03-02", "2026-03-04", 44, 104, float64(44) / 104 * 100},
+				{"2026-03-09", "2026-03-10", 50, 110, float64(50) / 110 * 100},
+			},
+		},
+		{
+			name:  "by source over the latest week",
+			by:    DimensionSource,
+			weeks: 1,
+			expected: [][]any{
+				{"2026-03-09", "2026-03-10", "GitHub", 36, 68, float64(36) / 68 * 100},
+				{"2026-03-09", "2026-03-10", "Stripe", 14, 42, float64(14) / 42 * 100},
+			},
+		},
+		{name: "unsupported dimension", by: DimensionAgeBucket, weeks: 4, expectError: true},
+		{name: "no weeks", weeks: 0, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := QueryReadRate(series, tt.by, tt.weeks)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Rows, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result.Rows)
+			}
+		})
+	}
+}
+
+func TestQueryTopUnread(t *testing.T) {
+	series := querySeries()
+
+	result, err := QueryTopUnread(series, "", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Rows) != 2 || result.Rows[0][3] != "Older Stripe" || result.Rows[0][1] != 730 || result.Rows[1][3] != "Old GitHub" {
+		t.Errorf("expected the two oldest listed articles, got %v", result.Rows)
+	}
+
+	result, _ = QueryTopUnread(series, "Stripe", 10)
+	if len(result.Rows) != 2 || result.Rows[1][3] != "Newer Stripe" {
+		t.Errorf("expected only Stripe articles, got %v", result.Rows)
+	}
+
+	if _, err := QueryTopUnread(series, "", 0); err == nil {
+		t.Error("expected an error for n below 1")
+	}
+}
+
+func TestQueryResultWrite(t *testing.T) {
+	result := QueryResult{
+		Columns: []string{"snapshot_date", "unread", "change"},
+		Rows:    [][]any{{"2026-03-04", 60, nil}, {"2026-03-10", 58, -2}},
+	}
+
+	var table bytes.Buffer
+	if err := result.Write(&table, QueryFormatTable); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "snapshot_date  unread  change") || !strings.HasSuffix(lines[1], "-") {
+		t.Errorf("unexpected table:\n%s", table.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := result.Write(&jsonOut, QueryFormatJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(jsonOut.String(), `"change": null`) || !strings.Contains(jsonOut.String(), `"unread": 58`) {
+		t.Errorf("unexpected JSON:\n%s", jsonOut.String())
+	}
+
+	if err := result.Write(&jsonOut, "yaml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,245 @@
+package metrics
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// Digest periods
+const (
+	DigestMonthly = "monthly"
+	DigestAnnual  = "annual"
+)
+
+// DigestTopSourcesCount is how many sources a digest ranks by reads
+const DigestTopSourcesCount = 5
+
+// Digest is a reading recap for one calendar month or year, measured between the last snapshot
+// before the period (the baseline) and the last snapshot inside it
+type Digest struct {
+	Period       string // DigestMonthly or DigestAnnual
+	Label        string // "October 2026" or "2026"
+	Key          string // "2026-10" or "2026"; names the report files
+	BaselineDate string
+	EndDate      string
+	Partial      bool // no snapshot precedes the period, so changes start at its first snapshot
+
+	TotalArticles  int
+	ReadCount      int
+	UnreadCount    int
+	ReadRate       float64
+	Added          int
+	Read           int
+	UnreadChange   int
+	ReadRateChange float64 // percentage points
+
+	TopSources []DigestSource
+	Cleared    []schema.ArticleMeta // oldest unread articles at the baseline that left the backlog
+	Narrative  string               // AI delta analysis of the period's last snapshot
+}
+
+// DigestSource is one source's reads during a digest period
+type DigestSource struct {
+	Name   string
+	Read   int
+	Total  int // articles from the source at the end of the period
+	Unread int
+}
+
+// DigestPeriodStart parses the period a digest covers: YYYY-MM for monthly digests, YYYY for annual ones
+func DigestPeriodStart(period, value string) (time.Time, error) {
+	switch period {
+	case DigestMonthly:
+		start, err := time.Parse("2006-01", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid month %q: expected YYYY-MM", value)
+		}
+		return start, nil
+	case DigestAnnual:
+		start, err := time.Parse("2006", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid year %q: expected YYYY", value)
+		}
+		return start, nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown digest period %q: expected %s or %s", period, DigestMonthly, DigestAnnual)
+	}
+}
+
+// BuildDigest summarizes the period starting at start from a chronological series of snapshots
+// (oldest first). It fails when no snapshot falls inside the period.
+func BuildDigest(series []schema.Metrics, period string, start time.Time) (Digest, error) {
+	digest := Digest{Period: period}
+	var end time.Time
+	switch period {
+	case DigestMonthly:
+		end = start.AddDate(0, 1, 0)
+		digest.Label = start.Format("January 2006")
+		digest.Key = start.Format("2006-01")
+	case DigestAnnual:
+		end = start.AddDate(1, 0, 0)
+		digest.Label = start.Format("2006")
+		digest.Key = digest.Label
+	default:
+		return Digest{}, fmt.Errorf("unknown digest period %q", period)
+	}
+
+	var baseline, current *schema.Metrics
+	for i := range series {
+		day := snapshotDay(series[i].LastUpdated)
+		switch {
+		case day.Before(start):
+			baseline = &series[i]
+		case day.Before(end):
+			if current == nil && baseline == nil {
+				baseline = &series[i]
+				digest.Partial = true
+			}
+			current = &series[i]
+		}
+	}
+	if current == nil {
+		return Digest{}, fmt.Errorf("no snapshots in %s", digest.Label)
+	}
+
+	digest.BaselineDate = snapshotDay(baseline.LastUpdated).Format("2006-01-02")
+	digest.EndDate = snapshotDay(current.LastUpdated).Format("2006-01-02")
+	digest.TotalArticles = current.TotalArticles
+	digest.ReadCount = current.ReadCount
+	digest.UnreadCount = current.UnreadCount
+	digest.ReadRate = current.ReadRate
+	digest.Added = current.TotalArticles - baseline.TotalArticles
+	digest.Read = current.ReadCount - baseline.ReadCount
+	digest.UnreadChange = current.UnreadCount - baseline.UnreadCount
+	digest.ReadRateChange = current.ReadRate - baseline.ReadRate
+	digest.Narrative = current.AIDeltaAnalysis
+
+	for name, counts := range current.BySourceReadStatus {
+		if name == "substack_author_count" {
+			continue
+		}
+		read := counts[0] - baseline.BySourceReadStatus[name][0]
+		if read > 0 {
+			digest.TopSources = append(digest.TopSources, DigestSource{Name: name, Read: read, Total: counts[0] + counts[1], Unread: counts[1]})
+		}
+	}
+	sort.Slice(digest.TopSources, func(i, j int) bool {
+		if digest.TopSources[i].Read != digest.TopSources[j].Read {
+			return digest.TopSources[i].Read > digest.TopSources[j].Read
+		}
+		return digest.TopSources[i].Name < digest.TopSources[j].Name
+	})
+	if len(digest.TopSources) > DigestTopSourcesCount {
+		digest.TopSources = digest.TopSources[:DigestTopSourcesCount]
+	}
+
+	digest.Cleared = clearedBacklog(*baseline, *current)
+	return digest, nil
+}
+
+// backlogArticles lists the oldest unread articles a snapshot keeps, deduplicated by link or title
+func backlogArticles(m schema.Metrics) []schema.ArticleMeta {
+	seen := make(map[string]bool)
+	var articles []schema.ArticleMeta
+	add := func(article schema.ArticleMeta) {
+		key := article.Link
+		if key == "" {
+			key = article.Title
+		}
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		articles = append(articles, article)
+	}
+
+	for _, article := range m.TopOldestUnreadArticles {
+		add(article)
+	}
+	for _, backlog := range m.SourceBacklog {
+		for _, article := range backlog {
+			add(article)
+		}
+	}
+	return articles
+}
+
+// clearedBacklog returns the baseline's listed unread articles that the current snapshot no longer
+// lists, oldest first. Only the oldest articles per source are kept in a snapshot, so this covers
+// the tail of the backlog rather than every article read.
+func clearedBacklog(baseline, current schema.Metrics) []schema.ArticleMeta {
+	still := make(map[string]bool)
+	for _, article := range backlogArticles(current) {
+		still[article.Link+"\x00"+article.Title] = true
+	}
+
+	var cleared []schema.ArticleMeta
+	for _, article := range backlogArticles(baseline) {
+		if !still[article.Link+"\x00"+article.Title] {
+			cleared = append(cleared, article)
+		}
+	}
+	sort.SliceStable(cleared, func(i, j int) bool {
+		return cleared[i].Date < cleared[j].Date
+	})
+	return cleared
+}
+
+// signedInt formats a change with an explicit sign
+func signedInt(n int) string {
+	return fmt.Sprintf("%+d", n)
+}
+
+// signedPoints formats a read rate change in percentage points
+func signedPoints(f float64) string {
+	return fmt.Sprintf("%+.1f pts", f)
+}
+
+var digestFuncs = map[string]interface{}{
+	"signedInt":    signedInt,
+	"signedPoints": signedPoints,
+	"inc":          func(i int) int { return i + 1 },
+}
+
+// digestTemplates holds the Markdown and HTML digest layouts, compiled into the binary so reports
+// render from any working directory
+//
+//go:embed templates/digest.md templates/digest.html
+var digestTemplates embed.FS
+
+// RenderDigestMarkdown formats a digest as Markdown
+func RenderDigestMarkdown(d Digest) (string, error) {
+	tmpl, err := texttemplate.New("digest.md").Funcs(digestFuncs).ParseFS(digestTemplates, "templates/digest.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to parse markdown digest template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render markdown digest: %w", err)
+	}
+	return strings.TrimSpace(buf.String()) + "\n", nil
+}
+
+// RenderDigestHTML formats a digest as a self-contained HTML page with inline styles, so it
+// survives being pasted into an email
+func RenderDigestHTML(d Digest) (string, error) {
+	tmpl, err := htmltemplate.New("digest.html").Funcs(digestFuncs).ParseFS(digestTemplates, "templates/digest.html")
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML digest template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render HTML digest: %w", err)
+	}
+	return buf.String(), nil
+}
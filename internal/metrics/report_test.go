@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+func digestSnapshot(date string, total, read int, bySource map[string][2]int, backlog []schema.ArticleMeta) schema.Metrics {
+	day, _ := time.Parse("2006-01-02", date)
+	return schema.Metrics{
+		LastUpdated:             day,
+		TotalArticles:           total,
+		ReadCount:               read,
+		UnreadCount:             total - read,
+		ReadRate:                float64(read) / float64(total) * 100,
+		BySourceReadStatus:      bySource,
+		TopOldestUnreadArticles: backlog,
+	}
+}
+
+func TestDigestPeriodStart(t *testing.T) {
+	tests := []struct {
+		period, value string
+		expected      string
+		expectError   bool
+	}{
+		{period: DigestMonthly, value: "2026-03", expected: "2026-03-01"},
+		{period: DigestAnnual, value: "2025", expected: "2025-01-01"},
+		{period: DigestMonthly, value: "2026", expectError: true},
+		{period: DigestAnnual, value: "2025-01", expectError: true},
+		{period: "weekly", value: "2026-03", expectError: true},
+	}
+
+	for _, tt := range tests {
+		start, err := DigestPeriodStart(tt.period, tt.value)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("DigestPeriodStart(%q, %q) expected an error", tt.period, tt.value)
+			}
+			continue
+		}
+		if err != nil || start.Format("2006-01-02") != tt.expected {
+			t.Errorf("DigestPeriodStart(%q, %q) = %v, %v; want %s", tt.period, tt.value, start, err, tt.expected)
+		}
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	oldest := schema.ArticleMeta{Date: "2020-01-01", Title: "Oldest", Link: "https://a.example/1", Category: "GitHub"}
+	older := schema.ArticleMeta{Date: "2021-01-01", Title: "Older", Link: "https://a.example/2", Category: "Stripe"}
+	series := []schema.Metrics{
+		digestSnapshot("2026-01-30", 100, 40, map[string][2]int{"GitHub": {30, 30}, "Stripe": {10, 30}}, []schema.ArticleMeta{oldest, older}),
+		digestSnapshot("2026-02-13", 110, 45, map[string][2]int{"GitHub": {33, 32}, "Stripe": {12, 33}}, []schema.ArticleMeta{oldest, older}),
+		digestSnapshot("2026-02-27", 120, 52, map[string][2]int{"GitHub": {35, 35}, "Stripe": {17, 33}, "substack_author_count": {9, 0}}, []schema.ArticleMeta{older}),
+		digestSnapshot("2026-03-06", 125, 60, map[string][2]int{"GitHub": {40, 30}, "Stripe": {20, 35}}, nil),
+	}
+	series[2].AIDeltaAnalysis = "Reading picked up."
+
+	t.Run("monthly digest measures against the last earlier snapshot", func(t *testing.T) {
+		digest, err := BuildDigest(series, DigestMonthly, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if digest.Label != "February 2026" || digest.Key != "2026-02" || digest.BaselineDate != "2026-01-30" || digest.EndDate != "2026-02-27" || digest.Partial {
+			t.Errorf("unexpected period: %+v", digest)
+		}
+		if digest.Added != 20 || digest.Read != 12 || digest.UnreadChange != 8 || digest.UnreadCount != 68 {
+			t.Errorf("unexpected changes: %+v", digest)
+		}
+
+		expectedTop := []DigestSource{
+			{Name: "Stripe", Read: 7, Total: 50, Unread: 33},
+			{Name: "GitHub", Read: 5, Total: 70, Unread: 35},
+		}
+		if !reflect.DeepEqual(digest.TopSources, expectedTop) {
+			t.Errorf("expected top sources %v, got %v", expectedTop, digest.TopSources)
+		}
+		if len(digest.Cleared) != 1 || digest.Cleared[0].Title != "Oldest" {
+			t.Errorf("expected the oldest article to be cleared, got %v", digest.Cleared)
+		}
+		if digest.Narrative != "Reading picked up." {
+			t.Errorf("expected the period's AI narrative, got %q", digest.Narrative)
+		}
+	})
+
+	t.Run("annual digest without an earlier snapshot is partial", func(t *testing.T) {
+		digest, err := BuildDigest(series, DigestAnnual, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !digest.Partial || digest.BaselineDate != "2026-01-30" || digest.EndDate != "2026-03-06" || digest.Read != 20 {
+			t.Errorf("unexpected annual digest: %+v", digest)
+		}
+		if len(digest.Cleared) != 2 {
+			t.Errorf("expected both listed articles cleared, got %v", digest.Cleared)
+		}
+	})
+
+	t.Run("no snapshots in the period", func(t *testing.T) {
+		_, err := BuildDigest(series, DigestMonthly, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+		if err == nil || !strings.Contains(err.Error(), "June 2025") {
+			t.Errorf("expected an error naming the period, got %v", err)
+		}
+	})
+}
+
+func TestRenderDigest(t *testing.T) {
+	digest := Digest{
+		Period:         DigestMonthly,
+		Label:          "February 2026",
+		BaselineDate:   "2026-01-30",
+		EndDate:        "2026-02-27",
+		TotalArticles:  120,
+		ReadCount:      52,
+		UnreadCount:    68,
+		ReadRate:       43.33,
+		Added:          20,
+		Read:           12,
+		UnreadChange:   8,
+		ReadRateChange: 3.33,
+		TopSources:     []DigestSource{{Name: "Stripe", Read: 7, Total: 50, Unread: 33}},
+		Cleared:        []schema.ArticleMeta{{Date: "2020-01-01", Title: "Oldest <post>", Link: "https://a.example/1", Category: "GitHub"}},
+		Narrative:      "Reading picked up.",
+	}
+
+	markdown, err := RenderDigestMarkdown(digest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expected := range []string{
+		"# Reading Digest: February 2026",
+		"| Read | 52 | +12 |",
+		"| Read rate | 43.3% | +3.3 pts |",
+		"1. **Stripe**: 7 read (33 of 50 still unread)",
+		"- 2020-01-01 [Oldest <post>](https://a.example/1) (GitHub)",
+		"## AI Narrative\n\nReading picked up.",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", expected, markdown)
+		}
+	}
+
+	html, err := RenderDigestHTML(digest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(html, "<link") || strings.Contains(html, "<style") {
+		t.Error("expected only inline styles in the HTML digest")
+	}
+	for _, expected := range []string{"Reading Digest: February 2026", "Oldest &lt;post&gt;", `href="https://a.example/1"`, "Reading picked up."} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected HTML to contain %q", expected)
+		}
+	}
+
+	empty, _ := RenderDigestMarkdown(Digest{Label: "March 2026"})
+	if !strings.Contains(empty, "No articles were read") || strings.Contains(empty, "AI Narrative") {
+		t.Errorf("unexpected empty digest:\n%s", empty)
+	}
+}
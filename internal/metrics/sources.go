@@ -0,0 +1,287 @@
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// DefaultSourceAliasesPath is where the source alias configuration is read from
+const DefaultSourceAliasesPath = "config/sources.yml"
+
+// SourceAlias declares a canonical source name and every spelling that should resolve to it
+type SourceAlias struct {
+	Name          string   `yaml:"name"`
+	Aliases       []string `yaml:"aliases,omitempty"`        // alternate spellings and merged sources
+	PreviousNames []string `yaml:"previous_names,omitempty"` // names used before a rename
+	Retired       string   `yaml:"retired,omitempty"`        // YYYY-MM-DD the source stopped being tracked
+}
+
+// SourceAliasConfig is the top-level structure of the source aliases YAML file
+type SourceAliasConfig struct {
+	Sources []SourceAlias `yaml:"sources"`
+}
+
+// sourceKey normalizes a source name for lookups: case-insensitive and whitespace-collapsed
+func sourceKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// LoadSourceAliases reads and parses a source aliases YAML file
+func LoadSourceAliases(path string) (SourceAliasConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return SourceAliasConfig{}, fmt.Errorf("unable to read source aliases %s: %w", path, err)
+	}
+
+	var config SourceAliasConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return SourceAliasConfig{}, fmt.Errorf("failed to parse source aliases %s: %w", path, err)
+	}
+
+	for i, source := range config.Sources {
+		if strings.TrimSpace(source.Name) == "" {
+			return SourceAliasConfig{}, fmt.Errorf("source %d in %s has no name", i, path)
+		}
+	}
+
+	return config, nil
+}
+
+// loadDefaultSourceAliases reads the default alias file, logging and falling back to no aliases when absent
+func loadDefaultSourceAliases() SourceAliasConfig {
+	config, err := LoadSourceAliases(DefaultSourceAliasesPath)
+	if err != nil {
+		log.Printf("Warning: Source aliases disabled: %v\n", err)
+		return SourceAliasConfig{}
+	}
+	return config
+}
+
+// RetiredSources returns canonical source name -> retirement date for retired sources
+func (c SourceAliasConfig) RetiredSources() map[string]string {
+	retired := make(map[string]string)
+	for _, source := range c.Sources {
+		if source.Retired != "" {
+			retired[source.Name] = source.Retired
+		}
+	}
+	return retired
+}
+
+// MarkRetiredSources records retirement dates on the snapshot's source metadata
+func MarkRetiredSources(m *schema.Metrics, retired map[string]string) {
+	for name, date := range retired {
+		meta, exists := m.SourceMetadata[name]
+		if !exists {
+			continue
+		}
+		meta.Retired = date
+		m.SourceMetadata[name] = meta
+	}
+}
+
+// CanonicalizeSources rewrites every source-keyed aggregate in a snapshot to canonical names,
+// merging counts when several historic names resolve to the same source.
+func CanonicalizeSources(m *schema.Metrics, sourceMap map[string]string) {
+	if len(sourceMap) == 0 {
+		return
+	}
+
+	normalize := func(name string) string {
+		// The Substack author count is stored alongside sources but is not one
+		if name == "substack_author_count" {
+			return name
+		}
+		return NormalizeSourceName(name, sourceMap)
+	}
+
+	m.BySource = mergeIntBySource(m.BySource, normalize)
+	m.UnreadBySource = mergeIntBySource(m.UnreadBySource, normalize)
+	m.UnreadByCategory = mergeIntBySource(m.UnreadByCategory, normalize)
+	m.BySourceReadStatus = mergePairBySource(m.BySourceReadStatus, normalize)
+	m.ByCategory = mergePairBySource(m.ByCategory, normalize)
+
+	for month, sources := range m.ByMonthAndSource {
+		m.ByMonthAndSource[month] = mergePairBySource(sources, normalize)
+	}
+
+	if m.ByCategoryAndSource != nil {
+		merged := make(map[string]map[string][2]int)
+		for category, sources := range m.ByCategoryAndSource {
+			canonical := normalize(category)
+			if merged[canonical] == nil {
+				merged[canonical] = make(map[string][2]int)
+			}
+			for source, counts := range mergePairBySource(sources, normalize) {
+				status := merged[canonical][source]
+				merged[canonical][source] = [2]int{status[0] + counts[0], status[1] + counts[1]}
+			}
+		}
+		m.ByCategoryAndSource = merged
+	}
+
+	if m.SourceMetadata != nil {
+		merged := make(map[string]schema.SourceMeta)
+		for _, name := range mergeOrder(slices.Collect(maps.Keys(m.SourceMetadata)), normalize) {
+			canonical := normalize(name)
+			if existing, exists := merged[canonical]; exists {
+				merged[canonical] = mergeSourceMeta(existing, m.SourceMetadata[name])
+			} else {
+				merged[canonical] = m.SourceMetadata[name]
+			}
+		}
+		m.SourceMetadata = merged
+	}
+
+	if m.BySourceAndMonth != nil {
+		merged := make(map[string]map[string][2]int)
+		for source, months := range m.BySourceAndMonth {
+			canonical := normalize(source)
+			if merged[canonical] == nil {
+				merged[canonical] = make(map[string][2]int)
+			}
+			for month, counts := range months {
+				status := merged[canonical][month]
+				merged[canonical][month] = [2]int{status[0] + counts[0], status[1] + counts[1]}
+			}
+		}
+		m.BySourceAndMonth = merged
+	}
+
+	if m.SourceBacklog != nil {
+		merged := make(map[string][]schema.ArticleMeta)
+		for source, articles := range m.SourceBacklog {
+			canonical := normalize(source)
+			for _, article := range articles {
+				article.Category = canonical
+				merged[canonical] = append(merged[canonical], article)
+			}
+		}
+		// Merged sources interleave; keep the oldest first
+		for source, articles := range merged {
+			sort.SliceStable(articles, func(i, j int) bool {
+				return articles[i].Date < articles[j].Date
+			})
+			if len(articles) > SourceBacklogCount {
+				merged[source] = articles[:SourceBacklogCount]
+			}
+		}
+		m.SourceBacklog = merged
+	}
+
+	if m.SourceHealth != nil {
+		merged := make(map[string]schema.SourceHealth)
+		for _, name := range mergeOrder(slices.Collect(maps.Keys(m.SourceHealth)), normalize) {
+			canonical := normalize(name)
+			existing, exists := merged[canonical]
+			if !exists {
+				merged[canonical] = m.SourceHealth[name]
+				continue
+			}
+			health := mergeSourceHealth(existing, m.SourceHealth[name])
+			// Rate and class follow the merged counts, which were summed above
+			counts := m.BySourceReadStatus[canonical]
+			total := counts[0] + counts[1]
+			if total > 0 {
+				health.ReadRate = math.Round(float64(counts[0])/float64(total)*1000) / 10
+			}
+			health.Class = classifySource(health, total, m.SourceMetadata[canonical].Retired != "")
+			merged[canonical] = health
+		}
+		m.SourceHealth = merged
+	}
+
+	if m.OldestUnreadArticle != nil {
+		m.OldestUnreadArticle.Category = normalize(m.OldestUnreadArticle.Category)
+	}
+	for i := range m.TopOldestUnreadArticles {
+		m.TopOldestUnreadArticles[i].Category = normalize(m.TopOldestUnreadArticles[i].Category)
+	}
+	for _, articles := range m.YearBacklog {
+		for i := range articles {
+			articles[i].Category = normalize(articles[i].Category)
+		}
+	}
+}
+
+// mergeOrder sorts source names into the order their entries are merged: names that are already
+// canonical first, then the aliases by name, so merges are deterministic
+func mergeOrder(names []string, normalize func(string) string) []string {
+	sort.Slice(names, func(i, j int) bool {
+		iCanonical, jCanonical := normalize(names[i]) == names[i], normalize(names[j]) == names[j]
+		if iCanonical != jCanonical {
+			return iCanonical
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// mergeSourceMeta combines the metadata of two sources merged into one: the earliest added date
+// ("initial" predates any date), the first entry's color unless it has none, and a retirement
+// date only when both were retired
+func mergeSourceMeta(a, b schema.SourceMeta) schema.SourceMeta {
+	merged := a
+	switch {
+	case a.Added == "initial" || b.Added == "":
+	case b.Added == "initial" || a.Added == "" || b.Added < a.Added:
+		merged.Added = b.Added
+	}
+	if merged.Color == "" {
+		merged.Color = b.Color
+	}
+	merged.Retired = ""
+	if a.Retired != "" && b.Retired != "" {
+		merged.Retired = max(a.Retired, b.Retired)
+	}
+	return merged
+}
+
+// mergeSourceHealth combines the health of two sources merged into one: the latest article,
+// summed recent articles and the first entry's read rate trend. The caller recomputes the read
+// rate and class from the merged counts.
+func mergeSourceHealth(a, b schema.SourceHealth) schema.SourceHealth {
+	merged := a
+	if b.LastArticle > a.LastArticle {
+		merged.LastArticle, merged.DaysSinceLastArticle = b.LastArticle, b.DaysSinceLastArticle
+	}
+	merged.RecentArticles += b.RecentArticles
+	merged.ArticlesPerMonth = math.Round((a.ArticlesPerMonth+b.ArticlesPerMonth)*100) / 100
+	return merged
+}
+
+// mergeIntBySource re-keys a source -> count map, summing merged sources
+func mergeIntBySource(in map[string]int, normalize func(string) string) map[string]int {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]int, len(in))
+	for name, count := range in {
+		out[normalize(name)] += count
+	}
+	return out
+}
+
+// mergePairBySource re-keys a source -> [read, unread] map, summing merged sources
+func mergePairBySource(in map[string][2]int, normalize func(string) string) map[string][2]int {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string][2]int, len(in))
+	for name, counts := range in {
+		canonical := normalize(name)
+		status := out[canonical]
+		out[canonical] = [2]int{status[0] + counts[0], status[1] + counts[1]}
+	}
+	return out
+}
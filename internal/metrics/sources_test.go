@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// ============================================================================
+// Source aliases: Config-driven canonical source names
+// ============================================================================
+
+func TestLoadSourceAliases(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	t.Run("parses aliases file", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "sources.yml")
+		content := `
+sources:
+  - name: freeCodeCamp
+    aliases: ["fcc"]
+  - name: GitHub
+    previous_names: ["GitHub Engineering"]
+    retired: "2026-01-01"
+`
+		os.WriteFile(path, []byte(content), 0644)
+
+		config, err := LoadSourceAliases(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(config.Sources) != 2 {
+			t.Fatalf("expected 2 sources, got %d", len(config.Sources))
+		}
+		if config.Sources[1].PreviousNames[0] != "GitHub Engineering" || config.Sources[1].Retired != "2026-01-01" {
+			t.Errorf("unexpected source: %+v", config.Sources[1])
+		}
+	})
+
+	t.Run("source without name", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "noname.yml")
+		os.WriteFile(path, []byte("sources:\n  - aliases: [\"x\"]\n"), 0644)
+		if _, err := LoadSourceAliases(path); err == nil {
+			t.Error("expected error for source without name, got nil")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := LoadSourceAliases(filepath.Join(tmpDir, "missing.yml")); err == nil {
+			t.Error("expected error for missing file, got nil")
+		}
+	})
+}
+
+func TestRetiredSources(t *testing.T) {
+	result := createTestSourceAliases().RetiredSources()
+	expected := map[string]string{"Stripe": "2026-01-01"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("RetiredSources() = %v, want %v", result, expected)
+	}
+}
+
+func TestMarkRetiredSources(t *testing.T) {
+	m := &schema.Metrics{
+		SourceMetadata: map[string]schema.SourceMeta{
+			"Stripe": {Added: "initial", Color: "#635bff"},
+			"GitHub": {Added: "initial"},
+		},
+	}
+
+	MarkRetiredSources(m, map[string]string{"Stripe": "2026-01-01", "Missing": "2025-01-01"})
+
+	if m.SourceMetadata["Stripe"].Retired != "2026-01-01" || m.SourceMetadata["Stripe"].Color != "#635bff" {
+		t.Errorf("expected Stripe retired with color kept, got %+v", m.SourceMetadata["Stripe"])
+	}
+	if m.SourceMetadata["GitHub"].Retired != "" {
+		t.Errorf("expected GitHub not retired, got %+v", m.SourceMetadata["GitHub"])
+	}
+	if _, exists := m.SourceMetadata["Missing"]; exists {
+		t.Error("expected no metadata created for unknown source")
+	}
+}
+
+func TestCanonicalizeSources(t *testing.T) {
+	m := &schema.Metrics{
+		BySource: map[string]int{"GitHub": 3, "GitHub Engineering": 2, "fcc": 1},
+		BySourceReadStatus: map[string][2]int{
+			"GitHub":                {2, 1},
+			"GitHub Engineering":    {1, 1},
+			"substack_author_count": {4, 0},
+		},
+		ByCategory:       map[string][2]int{"github engineering": {1, 1}},
+		UnreadBySource:   map[string]int{"GitHub": 1, "GitHub Engineering": 1},
+		UnreadByCategory: map[string]int{"GitHub Engineering": 1},
+		ByMonthAndSource: map[string]map[string][2]int{
+			"01": {"GitHub": {1, 0}, "GitHub Engineering": {0, 1}},
+		},
+		ByCategoryAndSource: map[string]map[string][2]int{
+			"GitHub Engineering": {"GitHub Engineering": {1, 1}},
+		},
+		SourceMetadata: map[string]schema.SourceMeta{
+			"GitHub":             {Added: "initial", Color: "#000"},
+			"GitHub Engineering": {Added: "old"},
+		},
+		BySourceAndMonth: map[string]map[string][2]int{
+			"GitHub":             {"2025-11": {1, 0}},
+			"GitHub Engineering": {"2025-11": {0, 1}, "2025-12": {1, 0}},
+		},
+		SourceBacklog: map[string][]schema.ArticleMeta{
+			"GitHub":             {{Date: "2025-11-20", Category: "GitHub"}},
+			"GitHub Engineering": {{Date: "2025-10-01", Category: "GitHub Engineering"}},
+		},
+		OldestUnreadArticle:     &schema.ArticleMeta{Category: "GitHub Engineering"},
+		TopOldestUnreadArticles: []schema.ArticleMeta{{Category: "fcc"}},
+	}
+
+	CanonicalizeSources(m, createTestSourceMap())
+
+	if !reflect.DeepEqual(m.BySource, map[string]int{"GitHub": 5, "freeCodeCamp": 1}) {
+		t.Errorf("unexpected BySource: %v", m.BySource)
+	}
+	if m.BySourceReadStatus["GitHub"] != [2]int{3, 2} {
+		t.Errorf("expected merged GitHub status [3 2], got %v", m.BySourceReadStatus["GitHub"])
+	}
+	if m.BySourceReadStatus["substack_author_count"] != [2]int{4, 0} {
+		t.Errorf("expected substack_author_count untouched, got %v", m.BySourceReadStatus["substack_author_count"])
+	}
+	if m.ByCategory["GitHub"] != [2]int{1, 1} || m.UnreadByCategory["GitHub"] != 1 {
+		t.Errorf("unexpected category maps: %v %v", m.ByCategory, m.UnreadByCategory)
+	}
+	if m.UnreadBySource["GitHub"] != 2 {
+		t.Errorf("expected 2 unread GitHub, got %d", m.UnreadBySource["GitHub"])
+	}
+	if m.ByMonthAndSource["01"]["GitHub"] != [2]int{1, 1} {
+		t.Errorf("unexpected month/source: %v", m.ByMonthAndSource["01"])
+	}
+	if m.ByCategoryAndSource["GitHub"]["GitHub"] != [2]int{1, 1} {
+		t.Errorf("unexpected category/source: %v", m.ByCategoryAndSource)
+	}
+	if len(m.SourceMetadata) != 1 || m.SourceMetadata["GitHub"].Color != "#000" {
+		t.Errorf("expected canonical metadata kept, got %v", m.SourceMetadata)
+	}
+	if !reflect.DeepEqual(m.BySourceAndMonth["GitHub"], map[string][2]int{"2025-11": {1, 1}, "2025-12": {1, 0}}) {
+		t.Errorf("unexpected source/month: %v", m.BySourceAndMonth)
+	}
+	if backlog := m.SourceBacklog["GitHub"]; len(backlog) != 2 || backlog[0].Date != "2025-10-01" || backlog[0].Category != "GitHub" {
+		t.Errorf("expected merged backlog oldest first, got %v", backlog)
+	}
+	if m.OldestUnreadArticle.Category != "GitHub" || m.TopOldestUnreadArticles[0].Category != "freeCodeCamp" {
+		t.Errorf("expected article categories canonicalized, got %q and %q", m.OldestUnreadArticle.Category, m.TopOldestUnreadArticles[0].Category)
+	}
+}
+
+func TestCanonicalizeSourcesMergesMetadata(t *testing.T) {
+	// Map iteration order varies between runs, so merge the same snapshot several times
+	for i := 0; i < 20; i++ {
+		m := &schema.Metrics{
+			BySourceReadStatus: map[string][2]int{"fcc": {9, 1}, "free code camp": {1, 9}},
+			SourceMetadata: map[string]schema.SourceMeta{
+				"fcc":            {Added: "2024-05-01", Color: "#111"},
+				"free code camp": {Added: "2023-02-01", Color: "#222"},
+			},
+			SourceHealth: map[string]schema.SourceHealth{
+				"fcc":            {LastArticle: "2025-12-01", DaysSinceLastArticle: 20, RecentArticles: 3, ArticlesPerMonth: 1, ReadRate: 90, ReadRateTrend: 5, TrendSince: "2025-09-01"},
+				"free code camp": {LastArticle: "2025-12-15", DaysSinceLastArticle: 6, RecentArticles: 2, ArticlesPerMonth: 0.67, ReadRate: 10},
+			},
+		}
+
+		CanonicalizeSources(m, createTestSourceMap())
+
+		meta := m.SourceMetadata["freeCodeCamp"]
+		if len(m.SourceMetadata) != 1 || meta.Added != "2023-02-01" || meta.Color != "#111" {
+			t.Fatalf("expected the earliest added date and the first alias' color, got %v", m.SourceMetadata)
+		}
+		expected := schema.SourceHealth{LastArticle: "2025-12-15", DaysSinceLastArticle: 6, RecentArticles: 5, ArticlesPerMonth: 1.67, ReadRate: 50, ReadRateTrend: 5, TrendSince: "2025-09-01", Class: HealthActive}
+		if len(m.SourceHealth) != 1 || m.SourceHealth["freeCodeCamp"] != expected {
+			t.Fatalf("expected merged health %+v, got %+v", expected, m.SourceHealth)
+		}
+	}
+}
+
+func TestCanonicalizeSourcesEmptyMap(t *testing.T) {
+	m := &schema.Metrics{BySource: map[string]int{"fcc": 1}}
+	CanonicalizeSources(m, nil)
+	if m.BySource["fcc"] != 1 {
+		t.Errorf("expected snapshot untouched without a source map, got %v", m.BySource)
+	}
+}
@@ -1,8 +1,6 @@
 package metrics
 
 import (
-	"time"
-
 	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
 )
 
@@ -40,10 +38,10 @@ func CalculateMostUnreadSource(metrics schema.Metrics) string {
 }
 
 // CalculateThisMonthArticles calculates articles read this month.
-// If currentMonth is empty, it uses the current system month.
+// If currentMonth is empty, it uses the month of the snapshot's LastUpdated date.
 func CalculateThisMonthArticles(metrics schema.Metrics, currentMonth string) int {
 	if currentMonth == "" {
-		currentMonth = time.Now().Format("01")
+		currentMonth = resolveAsOf(metrics.LastUpdated).Format("01")
 	}
 
 	// Sum all read articles from by_month_and_source_read_status for current month
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"testing"
+	"time"
 
 	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
 )
@@ -188,6 +189,18 @@ func TestCalculateThisMonthArticles(t *testing.T) {
 			month:         "01",
 			expectedCount: 0,
 		},
+		{
+			name: "empty month uses the snapshot month",
+			metrics: schema.Metrics{
+				ByMonthAndSource: map[string]map[string][2]int{
+					"02": {"SourceA": {4, 1}},
+					"03": {"SourceA": {9, 0}},
+				},
+				LastUpdated: time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC),
+			},
+			month:         "",
+			expectedCount: 4,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,242 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// ErrSnapshotExists is returned by SnapshotStore.Save when a snapshot of that name is already stored
+var ErrSnapshotExists = errors.New("snapshot already exists")
+
+// ErrNoPreviousSnapshot is returned by SnapshotStore.Previous for the oldest snapshot in the store
+var ErrNoPreviousSnapshot = errors.New("no previous snapshot")
+
+// SnapshotStore keeps the metrics snapshots and the files stored next to them, such as quality
+// reports. Snapshots are named like their files (YYYY-MM-DD.json or YYYY-MM-DDTHHMMSS.json), so
+// sorting the names sorts them by date. Snapshots compacted into the archive (see
+// ArchiveFilename) are listed and read like loose files.
+type SnapshotStore interface {
+	// List returns the snapshot names, oldest first. Other files in the store are skipped.
+	List(ctx context.Context) ([]string, error)
+	// Load reads one snapshot
+	Load(ctx context.Context, name string) (schema.Metrics, error)
+	// Save writes a snapshot; unless overwrite is set, an existing one fails with ErrSnapshotExists
+	Save(ctx context.Context, name string, m schema.Metrics, overwrite bool) error
+	// Latest returns the name of the newest snapshot
+	Latest(ctx context.Context) (string, error)
+	// Previous returns the name of the snapshot stored just before name; the oldest snapshot fails
+	// with ErrNoPreviousSnapshot
+	Previous(ctx context.Context, name string) (string, error)
+	// ReadFile reads any file in the store; a missing file's error wraps fs.ErrNotExist
+	ReadFile(ctx context.Context, name string) ([]byte, error)
+	// WriteFile writes any file in the store, replacing it if present
+	WriteFile(ctx context.Context, name string, data []byte) error
+	// Remove deletes a loose file from the store; a missing file is not an error
+	Remove(ctx context.Context, name string) error
+	// Location returns the path or URL of a file in the store for messages; "" gives the store itself
+	Location(name string) string
+}
+
+// OpenSnapshotStore returns the store at location: an s3://bucket/prefix URL selects the
+// S3-compatible backend (configured from the environment, see S3ConfigFromEnv), anything else
+// is a local directory.
+func OpenSnapshotStore(location string) (SnapshotStore, error) {
+	if rest, ok := strings.CutPrefix(location, "s3://"); ok {
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		return NewS3Store(S3ConfigFromEnv(bucket, prefix))
+	}
+	return NewFileStore(location), nil
+}
+
+// DecodeSnapshot parses the content of the snapshot file name. Older snapshots may lack a
+// timestamp, so the name fills in LastUpdated.
+func DecodeSnapshot(name string, content []byte) (schema.Metrics, error) {
+	var snapshot schema.Metrics
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("unable to parse snapshot %s: %w", name, err)
+	}
+	if snapshot.LastUpdated.IsZero() {
+		snapshot.LastUpdated, _ = ParseSnapshotFilename(name)
+	}
+	return snapshot, nil
+}
+
+// encodeSnapshot serializes a snapshot the way every snapshot file is written
+func encodeSnapshot(m schema.Metrics) ([]byte, error) {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+	return content, nil
+}
+
+// checkSnapshotAbsent fails with ErrSnapshotExists when name is stored, loose or archived. Other
+// read errors are left for the write that follows to report.
+func checkSnapshotAbsent(ctx context.Context, store SnapshotStore, name string) error {
+	if _, err := store.ReadFile(ctx, name); err == nil {
+		return fmt.Errorf("%w: %s", ErrSnapshotExists, store.Location(name))
+	}
+	return nil
+}
+
+// latestSnapshot returns the last of the listed snapshot names
+func latestSnapshot(store SnapshotStore, names []string) (string, error) {
+	if len(names) == 0 {
+		return "", fmt.Errorf("no snapshots in %s", store.Location(""))
+	}
+	return names[len(names)-1], nil
+}
+
+// previousSnapshot returns the listed snapshot name just before name, which must be listed itself
+func previousSnapshot(names []string, name string) (string, error) {
+	for i, listed := range names {
+		if listed != name {
+			continue
+		}
+		if i == 0 {
+			return "", fmt.Errorf("%w before %s", ErrNoPreviousSnapshot, name)
+		}
+		return names[i-1], nil
+	}
+	return "", fmt.Errorf("snapshot %s not found", name)
+}
+
+// FileStore keeps snapshots as files in a local directory
+type FileStore struct {
+	dir     string
+	archive archiveCache
+}
+
+// NewFileStore returns a store for the snapshots in dir; the directory is created on first write
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// List returns the snapshot filenames in the directory and its archive, oldest first
+func (s *FileStore) List(ctx context.Context) ([]string, error) {
+	loose, err := ListSnapshotFiles(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	return s.archive.list(ctx, loose, s.readFile)
+}
+
+// Load reads one snapshot file
+func (s *FileStore) Load(ctx context.Context, name string) (schema.Metrics, error) {
+	content, err := s.ReadFile(ctx, name)
+	if err != nil {
+		return schema.Metrics{}, err
+	}
+	return DecodeSnapshot(name, content)
+}
+
+// Save writes a snapshot file
+func (s *FileStore) Save(ctx context.Context, name string, m schema.Metrics, overwrite bool) error {
+	content, err := encodeSnapshot(m)
+	if err != nil {
+		return err
+	}
+	if !overwrite {
+		if err := checkSnapshotAbsent(ctx, s, name); err != nil {
+			return err
+		}
+	}
+	return s.WriteFile(ctx, name, content)
+}
+
+// Latest returns the newest snapshot filename
+func (s *FileStore) Latest(ctx context.Context) (string, error) {
+	names, err := s.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	return latestSnapshot(s, names)
+}
+
+// Previous returns the snapshot filename before name
+func (s *FileStore) Previous(ctx context.Context, name string) (string, error) {
+	names, err := s.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	return previousSnapshot(names, name)
+}
+
+// ReadFile reads a file from the directory, or an archived snapshot
+func (s *FileStore) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	return s.archive.read(ctx, name, s.readFile)
+}
+
+// readFile reads a loose file from the directory
+func (s *FileStore) readFile(ctx context.Context, name string) ([]byte, error) {
+	content, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", s.Location(name), err)
+	}
+	return content, nil
+}
+
+// WriteFile writes a file to the directory, creating the directory when needed. The content goes
+// to a temporary file that is renamed over name, so an interrupted write never leaves a
+// truncated file behind.
+func (s *FileStore) WriteFile(ctx context.Context, name string, data []byte) error {
+	defer s.archive.invalidate(name)
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, name), data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.Location(name), err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path with data via a synced temporary file in the same directory.
+// The temporary name starts with a dot, so a leftover from a crash is never listed as a snapshot.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Remove deletes a file from the directory
+func (s *FileStore) Remove(ctx context.Context, name string) error {
+	defer s.archive.invalidate(name)
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %w", s.Location(name), err)
+	}
+	return nil
+}
+
+// Location returns the path of a file in the directory
+func (s *FileStore) Location(name string) string {
+	if name == "" {
+		return s.dir
+	}
+	return filepath.Join(s.dir, name)
+}
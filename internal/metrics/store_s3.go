@@ -0,0 +1,350 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// s3RequestTimeout bounds every request to the object store
+const s3RequestTimeout = 30 * time.Second
+
+// S3Config locates a bucket on an S3-compatible object store such as AWS S3 or MinIO
+type S3Config struct {
+	Endpoint     string // base URL, e.g. http://localhost:9000 for MinIO; default https://s3.<region>.amazonaws.com
+	Region       string // signing region; default us-east-1
+	Bucket       string
+	Prefix       string // key prefix the snapshots are stored under, e.g. "metrics"
+	AccessKey    string
+	SecretKey    string
+	SessionToken string // optional, for temporary credentials
+}
+
+// S3ConfigFromEnv builds the configuration for bucket and prefix from S3_ENDPOINT, S3_REGION (or
+// AWS_REGION), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+func S3ConfigFromEnv(bucket, prefix string) S3Config {
+	region := os.Getenv("S3_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	return S3Config{
+		Endpoint:     os.Getenv("S3_ENDPOINT"),
+		Region:       region,
+		Bucket:       bucket,
+		Prefix:       prefix,
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// S3Store keeps snapshots as objects in an S3-compatible bucket, using path-style requests
+// signed with AWS Signature Version 4
+type S3Store struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+
+	archive archiveCache
+}
+
+// NewS3Store returns a store for the objects under cfg.Prefix in cfg.Bucket
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 snapshot store needs a bucket (s3://<bucket>/<prefix>)")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("S3 snapshot store needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	cfg.Prefix = strings.Trim(cfg.Prefix, "/")
+	if cfg.Prefix != "" {
+		cfg.Prefix += "/"
+	}
+
+	return &S3Store{cfg: cfg, client: &http.Client{Timeout: s3RequestTimeout}, now: time.Now}, nil
+}
+
+// List returns the snapshot names under the prefix, oldest first. Objects in nested
+// "directories" and other files are skipped.
+func (s *S3Store) List(ctx context.Context) ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.cfg.Prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		body, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list %s: %w", s.Location(""), err)
+		}
+
+		var page struct {
+			Contents              []struct{ Key string }
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		if err := xml.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("unable to parse the listing of %s: %w", s.Location(""), err)
+		}
+		for _, object := range page.Contents {
+			name := strings.TrimPrefix(object.Key, s.cfg.Prefix)
+			if !strings.Contains(name, "/") && IsSnapshotFilename(name) {
+				names = append(names, name)
+			}
+		}
+
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+
+	return s.archive.list(ctx, names, s.readObject)
+}
+
+// Load reads one snapshot object
+func (s *S3Store) Load(ctx context.Context, name string) (schema.Metrics, error) {
+	content, err := s.ReadFile(ctx, name)
+	if err != nil {
+		return schema.Metrics{}, err
+	}
+	return DecodeSnapshot(name, content)
+}
+
+// Save writes a snapshot object
+func (s *S3Store) Save(ctx context.Context, name string, m schema.Metrics, overwrite bool) error {
+	content, err := encodeSnapshot(m)
+	if err != nil {
+		return err
+	}
+	if !overwrite {
+		if err := checkSnapshotAbsent(ctx, s, name); err != nil {
+			return err
+		}
+	}
+	return s.WriteFile(ctx, name, content)
+}
+
+// Latest returns the newest snapshot name
+func (s *S3Store) Latest(ctx context.Context) (string, error) {
+	names, err := s.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	return latestSnapshot(s, names)
+}
+
+// Previous returns the snapshot name before name
+func (s *S3Store) Previous(ctx context.Context, name string) (string, error) {
+	names, err := s.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	return previousSnapshot(names, name)
+}
+
+// ReadFile reads an object under the prefix, or an archived snapshot
+func (s *S3Store) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	return s.archive.read(ctx, name, s.readObject)
+}
+
+// readObject reads a loose object under the prefix
+func (s *S3Store) readObject(ctx context.Context, name string) ([]byte, error) {
+	content, err := s.do(ctx, http.MethodGet, s.cfg.Prefix+name, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", s.Location(name), err)
+	}
+	return content, nil
+}
+
+// WriteFile writes an object under the prefix. A PUT replaces the object as a whole, so readers
+// never see a partial write.
+func (s *S3Store) WriteFile(ctx context.Context, name string, data []byte) error {
+	defer s.archive.invalidate(name)
+	if _, err := s.do(ctx, http.MethodPut, s.cfg.Prefix+name, nil, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.Location(name), err)
+	}
+	return nil
+}
+
+// Remove deletes an object under the prefix
+func (s *S3Store) Remove(ctx context.Context, name string) error {
+	defer s.archive.invalidate(name)
+	if _, err := s.do(ctx, http.MethodDelete, s.cfg.Prefix+name, nil, nil); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %w", s.Location(name), err)
+	}
+	return nil
+}
+
+// Location returns the s3:// URL of an object
+func (s *S3Store) Location(name string) string {
+	return "s3://" + s.cfg.Bucket + "/" + s.cfg.Prefix + name
+}
+
+// s3Error is the XML error body returned by S3-compatible stores
+type s3Error struct {
+	Code    string
+	Message string
+}
+
+// do sends a signed request for key in the bucket (the bucket itself when key is empty) and
+// returns the response body. A 404 is reported as an error wrapping fs.ErrNotExist.
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, body []byte) ([]byte, error) {
+	path := "/" + uriEncode(s.cfg.Bucket, false)
+	if key != "" {
+		path += "/" + uriEncode(key, false)
+	}
+	rawURL := s.cfg.Endpoint + path
+	if len(query) > 0 {
+		rawURL += "?" + canonicalQuery(query)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	signV4(req, path, query, body, s.cfg, s.now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fs.ErrNotExist
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr s3Error
+		if xml.Unmarshal(content, &apiErr) == nil && apiErr.Code != "" {
+			return nil, fmt.Errorf("%s %s: %s", resp.Status, apiErr.Code, apiErr.Message)
+		}
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return content, nil
+}
+
+// signV4 signs req with AWS Signature Version 4. path and query are the canonical (encoded)
+// request path and parameters; every header already set on req is signed along with the host.
+func signV4(req *http.Request, path string, query url.Values, body []byte, cfg S3Config, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method, path, canonicalQuery(query), canonicalHeaders.String(), signedHeaders, payloadHash,
+	}, "\n")
+
+	date := now.Format("20060102")
+	scope := date + "/" + cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretKey), date)
+	for _, part := range []string{cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name, as Signature Version 4 requires
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters; slashes are kept unless
+// encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// testSnapshotStore runs the behavior every SnapshotStore backend must share against an empty store
+func testSnapshotStore(t *testing.T, store SnapshotStore) {
+	ctx := context.Background()
+
+	if _, err := store.Latest(ctx); err == nil {
+		t.Error("expected an error for the latest snapshot of an empty store")
+	}
+
+	snapshots := map[string]int{"2026-01-08.json": 110, "2026-01-01.json": 100, "2026-01-08T183000.json": 112}
+	for name, total := range snapshots {
+		if err := store.Save(ctx, name, schema.Metrics{TotalArticles: total}, false); err != nil {
+			t.Fatalf("Save(%s) failed: %v", name, err)
+		}
+	}
+	if err := store.WriteFile(ctx, "2026-01-08.quality.json", []byte(`{"rows_scanned": 3}`)); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	names, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	expected := []string{"2026-01-01.json", "2026-01-08.json", "2026-01-08T183000.json"}
+	if len(names) != len(expected) || names[0] != expected[0] || names[1] != expected[1] || names[2] != expected[2] {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	if latest, err := store.Latest(ctx); err != nil || latest != "2026-01-08T183000.json" {
+		t.Errorf("expected the intra-day snapshot to be the latest, got %q, %v", latest, err)
+	}
+	if previous, err := store.Previous(ctx, "2026-01-08.json"); err != nil || previous != "2026-01-01.json" {
+		t.Errorf("expected 2026-01-01.json before 2026-01-08.json, got %q, %v", previous, err)
+	}
+	if _, err := store.Previous(ctx, "2026-01-01.json"); !errors.Is(err, ErrNoPreviousSnapshot) {
+		t.Errorf("expected ErrNoPreviousSnapshot for the first snapshot's predecessor, got %v", err)
+	}
+	if _, err := store.Previous(ctx, "2026-02-01.json"); err == nil || errors.Is(err, ErrNoPreviousSnapshot) {
+		t.Errorf("expected a not-found error for an unknown snapshot, got %v", err)
+	}
+
+	m, err := store.Load(ctx, "2026-01-08T183000.json")
+	if err != nil || m.TotalArticles != 112 {
+		t.Errorf("unexpected snapshot: %+v, %v", m, err)
+	}
+	if m.LastUpdated.Format(time.RFC3339) != "2026-01-08T18:30:00Z" {
+		t.Errorf("expected the missing timestamp to come from the name, got %v", m.LastUpdated)
+	}
+
+	if err := store.Save(ctx, "2026-01-01.json", schema.Metrics{TotalArticles: 1}, false); !errors.Is(err, ErrSnapshotExists) {
+		t.Errorf("expected ErrSnapshotExists, got %v", err)
+	}
+	if err := store.Save(ctx, "2026-01-01.json", schema.Metrics{TotalArticles: 101}, true); err != nil {
+		t.Errorf("overwrite failed: %v", err)
+	}
+	if m, _ := store.Load(ctx, "2026-01-01.json"); m.TotalArticles != 101 {
+		t.Errorf("expected the overwritten snapshot, got %d articles", m.TotalArticles)
+	}
+
+	if content, err := store.ReadFile(ctx, "2026-01-08.quality.json"); err != nil || string(content) != `{"rows_scanned": 3}` {
+		t.Errorf("unexpected quality report: %q, %v", content, err)
+	}
+	if _, err := store.ReadFile(ctx, "missing.json"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected a missing file to wrap fs.ErrNotExist, got %v", err)
+	}
+
+	// Compacted snapshots are listed and loaded like loose ones
+	if _, err := CompactSnapshots(ctx, store, false); err != nil {
+		t.Fatalf("CompactSnapshots failed: %v", err)
+	}
+	if names, err := store.List(ctx); err != nil || len(names) != len(expected) {
+		t.Errorf("expected %d archived snapshots, got %v, %v", len(expected), names, err)
+	}
+	if m, err := store.Load(ctx, "2026-01-01.json"); err != nil || m.TotalArticles != 101 {
+		t.Errorf("unexpected archived snapshot: %+v, %v", m, err)
+	}
+	if err := store.Save(ctx, "2026-01-08.json", schema.Metrics{}, false); !errors.Is(err, ErrSnapshotExists) {
+		t.Errorf("expected an archived snapshot to exist, got %v", err)
+	}
+	if _, err := store.ReadFile(ctx, "2026-01-08.quality.json"); err != nil {
+		t.Errorf("expected the quality report left in place: %v", err)
+	}
+
+	if err := store.WriteFile(ctx, "scratch.txt", []byte("x")); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := store.Remove(ctx, "scratch.txt"); err != nil {
+		t.Errorf("Remove failed: %v", err)
+	}
+	if _, err := store.ReadFile(ctx, "scratch.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected the removed file to be gone, got %v", err)
+	}
+	if err := store.Remove(ctx, "scratch.txt"); err != nil {
+		t.Errorf("expected removing a missing file to succeed, got %v", err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "metrics")
+	store := NewFileStore(dir)
+	testSnapshotStore(t, store)
+
+	if store.Location("2026-01-01.json") != filepath.Join(dir, "2026-01-01.json") || store.Location("") != dir {
+		t.Errorf("unexpected locations: %q, %q", store.Location("2026-01-01.json"), store.Location(""))
+	}
+	// Snapshots stay plain files next to the sidecars, readable without the store
+	if _, err := os.Stat(filepath.Join(dir, "2026-01-08.quality.json")); err != nil {
+		t.Errorf("expected the quality report on disk: %v", err)
+	}
+}
+
+func TestOpenSnapshotStore(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret")
+	t.Setenv("S3_ENDPOINT", "http://localhost:9000/")
+
+	store, err := OpenSnapshotStore("s3://reading/snapshots/weekly")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if location := store.Location("2026-01-01.json"); location != "s3://reading/snapshots/weekly/2026-01-01.json" {
+		t.Errorf("unexpected S3 location %q", location)
+	}
+
+	store, err = OpenSnapshotStore("metrics")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := store.(*FileStore); !ok {
+		t.Errorf("expected a FileStore for a plain path, got %T", store)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	if _, err := OpenSnapshotStore("s3://reading"); err == nil {
+		t.Error("expected an error without credentials")
+	}
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+const (
+	// substackDomain is the shared host suffix for Substack-hosted publications
+	substackDomain = "substack.com"
+
+	// UnknownPublication groups Substack articles whose link cannot be attributed
+	UnknownPublication = "unknown"
+)
+
+// normalizeHost lowercases a host and strips a leading "www."
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(host)), "www.")
+}
+
+// publicationFromURL derives a publication name from a Substack URL.
+// It understands <name>.substack.com, substack.com/@<name> and open.substack.com/pub/<name>,
+// and falls back to the bare host for publications on a custom domain.
+func publicationFromURL(parsed *url.URL) string {
+	host := normalizeHost(parsed.Hostname())
+	if host == "" {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+
+	if host == substackDomain || host == "open."+substackDomain {
+		if len(segments) >= 2 && segments[0] == "pub" {
+			return strings.ToLower(segments[1])
+		}
+		if len(segments) >= 1 && strings.HasPrefix(segments[0], "@") {
+			return strings.ToLower(strings.TrimPrefix(segments[0], "@"))
+		}
+		return ""
+	}
+
+	if strings.HasSuffix(host, "."+substackDomain) {
+		return strings.TrimSuffix(host, "."+substackDomain)
+	}
+
+	return host
+}
+
+// BuildSubstackPublicationMap maps provider hosts to publication names for every Substack row in the providers sheet
+func BuildSubstackPublicationMap(rows [][]interface{}) map[string]string {
+	publications := make(map[string]string)
+
+	// Skip header row and read the URL column of Substack providers
+	for i := 1; i < len(rows); i++ {
+		row := rows[i]
+		if len(row) <= ProvidersColURL {
+			continue
+		}
+		if !strings.EqualFold(fmt.Sprintf("%v", row[ProvidersColName]), SubstackProvider) {
+			continue
+		}
+
+		parsed, err := url.Parse(strings.TrimSpace(fmt.Sprintf("%v", row[ProvidersColURL])))
+		if err != nil {
+			continue
+		}
+		if publication := publicationFromURL(parsed); publication != "" {
+			publications[normalizeHost(parsed.Hostname())] = publication
+		}
+	}
+
+	return publications
+}
+
+// SubstackPublication resolves the publication an article link belongs to.
+// Hosts registered in the providers sheet take precedence over URL heuristics.
+func SubstackPublication(link string, publications map[string]string) string {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || parsed.Hostname() == "" {
+		return UnknownPublication
+	}
+
+	host := normalizeHost(parsed.Hostname())
+	if publication, exists := publications[host]; exists {
+		// substack.com itself hosts many publications, so only trust the map for dedicated hosts
+		if host != substackDomain && host != "open."+substackDomain {
+			return publication
+		}
+	}
+
+	if publication := publicationFromURL(parsed); publication != "" {
+		return publication
+	}
+	return UnknownPublication
+}
+
+// updateMetricsBySubstackPublication updates per-publication read/unread counts
+func updateMetricsBySubstackPublication(metrics *schema.Metrics, publication string, isRead bool) {
+	if metrics.BySubstackPublication == nil {
+		metrics.BySubstackPublication = make(map[string][2]int)
+	}
+
+	status := metrics.BySubstackPublication[publication]
+	if isRead {
+		status[0]++
+	} else {
+		status[1]++
+	}
+	metrics.BySubstackPublication[publication] = status
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+	"google.golang.org/api/sheets/v4"
+)
+
+// ============================================================================
+// Substack publications: Per-publication attribution of Substack articles
+// ============================================================================
+
+func TestBuildSubstackPublicationMap(t *testing.T) {
+	rows := [][]interface{}{
+		{"Name", "URL", "Element", "Strategy", "Color", "Added"},
+		{"Substack", "https://bytebytego.substack.com/feed", "", "rss"},
+		{"substack", "https://newsletter.pragmaticengineer.com/feed", "", "rss"},
+		{"GitHub", "https://github.blog/feed", "", "rss"},
+		{"Substack"},
+	}
+
+	expected := map[string]string{
+		"bytebytego.substack.com":          "bytebytego",
+		"newsletter.pragmaticengineer.com": "newsletter.pragmaticengineer.com",
+	}
+
+	result := BuildSubstackPublicationMap(rows)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("BuildSubstackPublicationMap() = %v, want %v", result, expected)
+	}
+}
+
+func TestSubstackPublication(t *testing.T) {
+	publications := map[string]string{
+		"newsletter.pragmaticengineer.com": "The Pragmatic Engineer",
+		"substack.com":                     "should-not-be-used",
+	}
+
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{"substack subdomain", "https://bytebytego.substack.com/p/some-post", "bytebytego"},
+		{"www prefix", "https://www.bytebytego.substack.com/p/some-post", "bytebytego"},
+		{"custom domain from providers", "https://newsletter.pragmaticengineer.com/p/post", "The Pragmatic Engineer"},
+		{"custom domain not in providers", "https://blog.example.com/p/post", "blog.example.com"},
+		{"substack profile path", "https://substack.com/@author/p/post", "author"},
+		{"open.substack pub path", "https://open.substack.com/pub/writer/p/post?r=abc", "writer"},
+		{"bare substack.com", "https://substack.com/home", UnknownPublication},
+		{"empty link", "", UnknownPublication},
+		{"not a url", "not a url", UnknownPublication},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SubstackPublication(tt.link, publications)
+			if result != tt.expected {
+				t.Errorf("SubstackPublication(%q) = %q, want %q", tt.link, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateMetricsBySubstackPublication(t *testing.T) {
+	metrics := &schema.Metrics{}
+
+	updateMetricsBySubstackPublication(metrics, "bytebytego", true)
+	updateMetricsBySubstackPublication(metrics, "bytebytego", false)
+	updateMetricsBySubstackPublication(metrics, "other", false)
+
+	if metrics.BySubstackPublication["bytebytego"] != [2]int{1, 1} {
+		t.Errorf("expected bytebytego [1 1], got %v", metrics.BySubstackPublication["bytebytego"])
+	}
+	if metrics.BySubstackPublication["other"] != [2]int{0, 1} {
+		t.Errorf("expected other [0 1], got %v", metrics.BySubstackPublication["other"])
+	}
+}
+
+func TestFetchMetricsWithSubstackPublications(t *testing.T) {
+	fetcher := &MockSheetsFetcher{
+		spreadsheet: &sheets.Spreadsheet{
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{Title: "Articles"}},
+				{Properties: &sheets.SheetProperties{Title: "Providers"}},
+			},
+		},
+		articleRows: [][]interface{}{
+			{"Date", "Title", "Link", "Category", "Read"},
+			{"2025-12-10", "A", "https://alpha.substack.com/p/a", "Substack", "TRUE"},
+			{"2025-12-11", "B", "https://alpha.substack.com/p/b", "Substack", "FALSE"},
+			{"2025-12-12", "C", "https://news.beta.dev/p/c", "substack", "FALSE"},
+			{"2025-12-12", "D", "https://github.blog/d", "GitHub", "FALSE"},
+		},
+		providerRows: [][]interface{}{
+			{"Name", "URL"},
+			{"Substack", "https://alpha.substack.com/feed"},
+			{"Substack", "https://news.beta.dev/feed"},
+		},
+	}
+
+	metrics, err := fetchMetricsWithFetcher("spreadsheetID", fetcher, time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string][2]int{
+		"alpha":         {1, 1},
+		"news.beta.dev": {0, 1},
+	}
+	if !reflect.DeepEqual(metrics.BySubstackPublication, expected) {
+		t.Errorf("BySubstackPublication = %v, want %v", metrics.BySubstackPublication, expected)
+	}
+}
@@ -3,98 +3,59 @@ package metrics
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"sort"
 	"strings"
 
 	"github.com/victoriacheng15/personal-reading-analytics/internal"
 	"github.com/victoriacheng15/personal-reading-analytics/internal/ai"
 )
 
-// GenerateAndSaveDeltaAnalysis generates an AI delta analysis comparing the current metrics with the previous week's.
-func GenerateAndSaveDeltaAnalysis(ctx context.Context, metricsDir string, currentFilename string, currentMetrics *internal.Metrics) error {
-	prevMetrics, err := loadPreviousMetrics(metricsDir, currentFilename)
-	if err != nil {
-		// Log warning but don't fail, just return.
-		// In a real logger we'd log this. For now printing to stderr is acceptable for CLI.
-		fmt.Fprintf(os.Stderr, "Warning: Could not load previous metrics for comparison: %v\n", err)
+// ErrDeltaAnalysisUnavailable wraps the errors of the AI step itself: the client could not be
+// created (see ai.ErrMissingAPIKey) or the model returned no analysis
+var ErrDeltaAnalysisUnavailable = errors.New("AI delta analysis unavailable")
+
+// GenerateAndSaveDeltaAnalysis generates an AI delta analysis comparing the current metrics with the previous week's
+// and saves it to the snapshot's analysis sidecar (see SaveAnalysis). The oldest snapshot is analyzed on its own;
+// any other failure is returned and nothing is saved.
+func GenerateAndSaveDeltaAnalysis(ctx context.Context, store SnapshotStore, currentFilename string, currentMetrics *internal.Metrics) error {
+	prevMetrics, err := loadPreviousMetrics(ctx, store, currentFilename)
+	if err != nil && !errors.Is(err, ErrNoPreviousSnapshot) {
+		return fmt.Errorf("unable to load the snapshot before %s for comparison: %w", currentFilename, err)
 	}
 
 	prompt := constructPrompt(currentMetrics, prevMetrics)
 
 	client, err := ai.NewClient(ctx)
 	if err != nil {
-		// If client init fails (e.g. no key), we silently skip delta analysis
-		fmt.Fprintf(os.Stderr, "Skipping AI delta analysis: %v\n", err)
-		return nil
+		return fmt.Errorf("%w: %w", ErrDeltaAnalysisUnavailable, err)
 	}
 	defer client.Close()
 
 	deltaAnalysis, err := client.GenerateContent(ctx, prompt)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating AI delta analysis: %v\n", err)
-		currentMetrics.AIDeltaAnalysis = "AI delta analysis unavailable at this time."
-	} else {
-		currentMetrics.AIDeltaAnalysis = deltaAnalysis
+		return fmt.Errorf("%w: %w", ErrDeltaAnalysisUnavailable, err)
 	}
+	currentMetrics.AIDeltaAnalysis = deltaAnalysis
 
-	// Save the updated metrics back to the file
-	return saveMetrics(metricsDir, currentFilename, currentMetrics)
+	// The analysis goes to a sidecar, so the snapshot written by fetch is never rewritten
+	return SaveAnalysis(ctx, store, currentFilename, currentMetrics.AIDeltaAnalysis)
 }
 
-func loadPreviousMetrics(dir, currentFilename string) (*internal.Metrics, error) {
-	files, err := os.ReadDir(dir)
+// loadPreviousMetrics loads the snapshot stored just before currentFilename
+func loadPreviousMetrics(ctx context.Context, store SnapshotStore, currentFilename string) (*internal.Metrics, error) {
+	prevFilename, err := store.Previous(ctx, currentFilename)
 	if err != nil {
 		return nil, err
 	}
 
-	var jsonFiles []string
-	for _, f := range files {
-		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") && f.Name() != ".gitkeep" {
-			jsonFiles = append(jsonFiles, f.Name())
-		}
-	}
-
-	sort.Strings(jsonFiles)
-
-	// Find the index of the current file
-	currentIndex := -1
-	for i, f := range jsonFiles {
-		if f == currentFilename {
-			currentIndex = i
-			break
-		}
-	}
-
-	if currentIndex <= 0 {
-		return nil, fmt.Errorf("no previous metrics file found before %s", currentFilename)
-	}
-
-	prevFilename := jsonFiles[currentIndex-1]
-	content, err := os.ReadFile(filepath.Join(dir, prevFilename))
+	metrics, err := store.Load(ctx, prevFilename)
 	if err != nil {
 		return nil, err
 	}
-
-	var metrics internal.Metrics
-	if err := json.Unmarshal(content, &metrics); err != nil {
-		return nil, err
-	}
-
 	return &metrics, nil
 }
 
-func saveMetrics(dir, filename string, metrics *internal.Metrics) error {
-	path := filepath.Join(dir, filename)
-	data, err := json.MarshalIndent(metrics, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal metrics: %w", err)
-	}
-	return os.WriteFile(path, data, 0644)
-}
-
 func constructPrompt(curr, prev *internal.Metrics) string {
 	currJSON, _ := json.MarshalIndent(curr, "", "  ")
 
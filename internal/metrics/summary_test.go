@@ -1,13 +1,15 @@
 package metrics
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
-	"time"
 
 	"github.com/victoriacheng15/personal-reading-analytics/internal"
+	"github.com/victoriacheng15/personal-reading-analytics/internal/ai"
 )
 
 func TestConstructPrompt(t *testing.T) {
@@ -62,8 +64,12 @@ func TestLoadPreviousMetrics(t *testing.T) {
 		os.WriteFile(path, bytes, 0644)
 	}
 
+	// Sidecar files in the same directory must not count as snapshots
+	os.WriteFile(filepath.Join(tmpDir, "2026-01-08.quality.json"), []byte(`{"rows_scanned": 1}`), 0644)
+	os.WriteFile(filepath.Join(tmpDir, ".gitkeep"), nil, 0644)
+
 	t.Run("find immediate predecessor", func(t *testing.T) {
-		prev, err := loadPreviousMetrics(tmpDir, "2026-01-15.json")
+		prev, err := loadPreviousMetrics(context.Background(), NewFileStore(tmpDir), "2026-01-15.json")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -73,32 +79,82 @@ func TestLoadPreviousMetrics(t *testing.T) {
 	})
 
 	t.Run("first file has no predecessor", func(t *testing.T) {
-		_, err := loadPreviousMetrics(tmpDir, "2026-01-01.json")
-		if err == nil {
-			t.Error("expected error for first file, got nil")
+		_, err := loadPreviousMetrics(context.Background(), NewFileStore(tmpDir), "2026-01-01.json")
+		if !errors.Is(err, ErrNoPreviousSnapshot) {
+			t.Errorf("expected ErrNoPreviousSnapshot for first file, got %v", err)
 		}
 	})
 
 	t.Run("file not in list", func(t *testing.T) {
-		_, err := loadPreviousMetrics(tmpDir, "2026-01-22.json")
+		_, err := loadPreviousMetrics(context.Background(), NewFileStore(tmpDir), "2026-01-22.json")
 		if err == nil {
 			t.Error("expected error for missing file, got nil")
 		}
 	})
+
+	t.Run("predecessor in the archive", func(t *testing.T) {
+		store := NewFileStore(tmpDir)
+		if _, err := CompactSnapshots(context.Background(), store, false); err != nil {
+			t.Fatalf("compaction failed: %v", err)
+		}
+		bytes, _ := json.Marshal(internal.Metrics{TotalArticles: 130})
+		os.WriteFile(filepath.Join(tmpDir, "2026-01-22.json"), bytes, 0644)
+
+		prev, err := loadPreviousMetrics(context.Background(), store, "2026-01-22.json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if prev.TotalArticles != 120 {
+			t.Errorf("expected 120 articles from the archived 2026-01-15.json, got %d", prev.TotalArticles)
+		}
+	})
+}
+
+func TestGenerateAndSaveDeltaAnalysisFailures(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+	os.WriteFile(filepath.Join(dir, "2026-01-01.json"), []byte(`{"total_articles": `), 0644)
+	os.WriteFile(filepath.Join(dir, "2026-01-08.json"), []byte(`{"total_articles": 110}`), 0644)
+
+	tests := []struct {
+		name        string
+		filename    string
+		unavailable bool // the AI step failed, rather than the store
+	}{
+		{name: "missing API key", filename: "2026-01-01.json", unavailable: true},
+		{name: "unreadable previous snapshot", filename: "2026-01-08.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := GenerateAndSaveDeltaAnalysis(ctx, store, tt.filename, &internal.Metrics{TotalArticles: 110})
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if errors.Is(err, ErrDeltaAnalysisUnavailable) != tt.unavailable || errors.Is(err, ai.ErrMissingAPIKey) != tt.unavailable {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, AnalysisFilename(tt.filename))); !os.IsNotExist(err) {
+				t.Error("expected no analysis saved after a failure")
+			}
+		})
+	}
 }
 
 func TestSaveUpdatedMetrics(t *testing.T) {
 	tmpDir := t.TempDir()
-	filename := "test.json"
-	m := &internal.Metrics{
-		TotalArticles:   10,
-		AIDeltaAnalysis: "Looks good!",
-		LastUpdated:     time.Now(),
+	filename := "2026-01-15.json"
+	store := NewFileStore(tmpDir)
+	if err := store.Save(context.Background(), filename, internal.Metrics{TotalArticles: 10}, false); err != nil {
+		t.Fatalf("Save failed: %v", err)
 	}
+	saved, _ := os.ReadFile(filepath.Join(tmpDir, filename))
 
-	err := saveMetrics(tmpDir, filename, m)
-	if err != nil {
-		t.Fatalf("saveMetrics failed: %v", err)
+	// The analysis goes to a sidecar; the snapshot saved by the fetch is left untouched
+	if err := SaveAnalysis(context.Background(), store, filename, "Looks good!"); err != nil {
+		t.Fatalf("SaveAnalysis failed: %v", err)
 	}
 
 	// Read back and verify
@@ -106,12 +162,19 @@ func TestSaveUpdatedMetrics(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to read back: %v", err)
 	}
+	if string(bytes) != string(saved) {
+		t.Errorf("expected the snapshot unchanged, got %s", bytes)
+	}
 
-	var result internal.Metrics
-	json.Unmarshal(bytes, &result)
+	var result internal.SnapshotAnalysis
+	sidecar, err := os.ReadFile(filepath.Join(tmpDir, "2026-01-15.analysis.json"))
+	if err != nil {
+		t.Fatalf("failed to read the sidecar: %v", err)
+	}
+	json.Unmarshal(sidecar, &result)
 
-	if result.AIDeltaAnalysis != "Looks good!" {
-		t.Errorf("AIDeltaAnalysis mismatch: got %s", result.AIDeltaAnalysis)
+	if result.AIDeltaAnalysis != "Looks good!" || result.Snapshot != filename || result.SnapshotSHA256 == "" {
+		t.Errorf("unexpected sidecar: %+v", result)
 	}
 }
 
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+// readRateTolerance is how far a stored read rate may drift from read/total before it is reported
+const readRateTolerance = 0.01
+
+// sumCounts adds up a breakdown map, skipping the Substack author counter stored next to the sources
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for key, count := range counts {
+		if key != "substack_author_count" {
+			total += count
+		}
+	}
+	return total
+}
+
+// ValidateSnapshot checks a snapshot loaded from filename for internal consistency: its date
+// matches the filename, counts are not negative, read and unread add up to the total, the read
+// rate matches the counts and the source and year breakdowns cover every article.
+// It returns one message per problem found.
+func ValidateSnapshot(filename string, m schema.Metrics) []string {
+	var problems []string
+	report := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	date := SnapshotFileDate(filename)
+	if !m.LastUpdated.IsZero() && m.LastUpdated.Format("2006-01-02") != date {
+		report("last_updated %s does not match the filename date %s", m.LastUpdated.Format("2006-01-02"), date)
+	}
+
+	if m.TotalArticles < 0 || m.ReadCount < 0 || m.UnreadCount < 0 {
+		report("negative counts: total %d, read %d, unread %d", m.TotalArticles, m.ReadCount, m.UnreadCount)
+	}
+	if m.ReadCount+m.UnreadCount != m.TotalArticles {
+		report("read %d + unread %d does not equal total %d", m.ReadCount, m.UnreadCount, m.TotalArticles)
+	}
+	if m.TotalArticles > 0 {
+		expected := float64(m.ReadCount) / float64(m.TotalArticles) * 100
+		if math.Abs(m.ReadRate-expected) > readRateTolerance {
+			report("read_rate %.2f does not match read/total (%.2f)", m.ReadRate, expected)
+		}
+	}
+
+	if len(m.BySource) > 0 {
+		if total := sumCounts(m.BySource); total != m.TotalArticles {
+			report("by_source adds up to %d, not the total %d", total, m.TotalArticles)
+		}
+	}
+	if len(m.BySourceReadStatus) > 0 {
+		read, unread := 0, 0
+		for source, counts := range m.BySourceReadStatus {
+			if source != "substack_author_count" {
+				read += counts[0]
+				unread += counts[1]
+			}
+		}
+		if read != m.ReadCount || unread != m.UnreadCount {
+			report("by_source_read_status adds up to %d read and %d unread, not %d and %d", read, unread, m.ReadCount, m.UnreadCount)
+		}
+	}
+	if len(m.ByYear) > 0 {
+		if total := sumCounts(m.ByYear); total != m.TotalArticles {
+			report("by_year adds up to %d, not the total %d", total, m.TotalArticles)
+		}
+	}
+
+	return problems
+}
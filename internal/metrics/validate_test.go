@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+)
+
+func TestValidateSnapshot(t *testing.T) {
+	valid := func() schema.Metrics {
+		return schema.Metrics{
+			LastUpdated:        time.Date(2026, 3, 6, 1, 0, 0, 0, time.UTC),
+			TotalArticles:      10,
+			ReadCount:          4,
+			UnreadCount:        6,
+			ReadRate:           40,
+			BySource:           map[string]int{"GitHub": 7, "Substack": 3, "substack_author_count": 2},
+			BySourceReadStatus: map[string][2]int{"GitHub": {3, 4}, "Substack": {1, 2}, "substack_author_count": {2, 0}},
+			ByYear:             map[string]int{"2025": 4, "2026": 6},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		modify   func(m *schema.Metrics)
+		expected []string
+	}{
+		{name: "consistent snapshot", filename: "2026-03-06.json", modify: func(m *schema.Metrics) {}},
+		{name: "missing timestamp is not a problem", filename: "2026-03-06.json", modify: func(m *schema.Metrics) { m.LastUpdated = time.Time{} }},
+		{name: "date mismatch", filename: "2026-03-07.json", modify: func(m *schema.Metrics) {}, expected: []string{"does not match the filename date 2026-03-07"}},
+		{
+			name:     "counts do not add up",
+			filename: "2026-03-06.json",
+			modify: func(m *schema.Metrics) {
+				m.UnreadCount = 5
+				m.ReadRate = 45
+			},
+			expected: []string{"read 4 + unread 5 does not equal total 10", "read_rate 45.00 does not match", "by_source_read_status adds up to 4 read and 6 unread, not 4 and 5"},
+		},
+		{
+			name:     "breakdowns miss articles",
+			filename: "2026-03-06.json",
+			modify: func(m *schema.Metrics) {
+				m.BySource["Substack"] = 2
+				delete(m.ByYear, "2025")
+			},
+			expected: []string{"by_source adds up to 9", "by_year adds up to 6"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid()
+			tt.modify(&m)
+			problems := ValidateSnapshot(tt.filename, m)
+			if len(problems) != len(tt.expected) {
+				t.Fatalf("expected %d problems, got %v", len(tt.expected), problems)
+			}
+			for i, expected := range tt.expected {
+				if !strings.Contains(problems[i], expected) {
+					t.Errorf("expected problem %d to contain %q, got %q", i, expected, problems[i])
+				}
+			}
+		})
+	}
+}
@@ -8,50 +8,200 @@ type Metrics struct {
 	BySourceReadStatus           map[string][2]int            `json:"by_source_read_status"`
 	ByYear                       map[string]int               `json:"by_year"`
 	ByMonth                      map[string]int               `json:"by_month"`
-	ByYearAndMonth               map[string]map[string]int    `json:"by_year_and_month"`               // year -> month -> count
-	ByMonthAndSource             map[string]map[string][2]int `json:"by_month_and_source_read_status"` // month -> source -> [read, unread]
-	ByCategory                   map[string][2]int            `json:"by_category"`                     // category -> [read, unread]
-	ByCategoryAndSource          map[string]map[string][2]int `json:"by_category_and_source"`          // category -> source -> [read, unread]
-	ReadUnreadTotals             [2]int                       `json:"read_unread_totals"`              // [read, unread]
+	ByYearAndMonth               map[string]map[string]int    `json:"by_year_and_month"`                 // year -> month -> count
+	ByMonthAndSource             map[string]map[string][2]int `json:"by_month_and_source_read_status"`   // month -> source -> [read, unread]
+	ByCategory                   map[string][2]int            `json:"by_category"`                       // category -> [read, unread]
+	ByCategoryAndSource          map[string]map[string][2]int `json:"by_category_and_source"`            // category -> source -> [read, unread]
+	ByTopic                      map[string][2]int            `json:"by_topic,omitempty"`                // topic -> [read, unread]
+	BySubstackPublication        map[string][2]int            `json:"by_substack_publication,omitempty"` // publication -> [read, unread]
+	SubstackBacklog              map[string][]ArticleMeta     `json:"substack_backlog,omitempty"`        // oldest unread articles per Substack publication
+	BySourceAndMonth             map[string]map[string][2]int `json:"by_source_and_month,omitempty"`     // source -> YYYY-MM -> [read, unread]
+	ReadUnreadTotals             [2]int                       `json:"read_unread_totals"`                // [read, unread]
 	UnreadByMonth                map[string]int               `json:"unread_by_month"`
 	UnreadByCategory             map[string]int               `json:"unread_by_category"`
 	UnreadBySource               map[string]int               `json:"unread_by_source"`
 	UnreadByYear                 map[string]int               `json:"unread_by_year"`
 	UnreadArticleAgeDistribution map[string]int               `json:"unread_article_age_distribution"`
+	AgeBuckets                   []AgeBucket                  `json:"age_buckets,omitempty"` // bucket scheme used for the age distribution
 	OldestUnreadArticle          *ArticleMeta                 `json:"oldest_unread_article,omitempty"`
 	TopOldestUnreadArticles      []ArticleMeta                `json:"top_oldest_unread_articles,omitempty"`
+	SourceBacklog                map[string][]ArticleMeta     `json:"source_backlog,omitempty"` // oldest unread articles per source
+	YearBacklog                  map[string][]ArticleMeta     `json:"year_backlog,omitempty"`   // oldest unread articles per publication year
 	SourceMetadata               map[string]SourceMeta        `json:"source_metadata"`
 	ReadCount                    int                          `json:"read_count"`
 	UnreadCount                  int                          `json:"unread_count"`
 	ReadRate                     float64                      `json:"read_rate"`
 	AvgArticlesPerMonth          float64                      `json:"avg_articles_per_month"`
+	DuplicatesMerged             int                          `json:"duplicates_merged,omitempty"` // duplicate rows counted once
 	LastUpdated                  time.Time                    `json:"last_updated"`
-	AIDeltaAnalysis              string                       `json:"ai_delta_analysis,omitempty"`
+	AIDeltaAnalysis              string                       `json:"ai_delta_analysis,omitempty"` // inline in older snapshots; now merged from <date>.analysis.json
+	AIDeltaAnalysisGeneratedAt   time.Time                    `json:"-"`                           // generated_at of the merged analysis sidecar
+	DataQuality                  *QualityReport               `json:"-"`                           // row-level audit, saved separately as <date>.quality.json
+	Reconstructed                *Reconstruction              `json:"reconstructed,omitempty"`     // set on backfilled snapshots
+	BacklogForecast              *BacklogForecast             `json:"backlog_forecast,omitempty"`
+	Goals                        []GoalResult                 `json:"goals,omitempty"`
+	Cadence                      *Cadence                     `json:"cadence,omitempty"`
+	SourceHealth                 map[string]SourceHealth      `json:"source_health,omitempty"`
+}
+
+// SourceHealth shows whether a source still publishes and whether its articles get read.
+// Recent figures cover the 90 days before the snapshot date.
+type SourceHealth struct {
+	LastArticle          string  `json:"last_article"`
+	DaysSinceLastArticle int     `json:"days_since_last_article"`
+	RecentArticles       int     `json:"recent_articles"`
+	ArticlesPerMonth     float64 `json:"articles_per_month"`
+	ReadRate             float64 `json:"read_rate"`
+	ReadRateTrend        float64 `json:"read_rate_trend"`       // percentage points since TrendSince
+	TrendSince           string  `json:"trend_since,omitempty"` // snapshot the trend compares against
+	Class                string  `json:"class"`                 // stale, noisy, valuable, active or retired
+}
+
+// Cadence summarizes weekly reading across the snapshot history.
+// Reads per week are the change in read count between the last snapshots of consecutive weeks.
+type Cadence struct {
+	WeeksObserved     int     `json:"weeks_observed"`
+	CurrentStreak     int     `json:"current_streak"` // consecutive weeks with at least one read, ending now
+	LongestStreak     int     `json:"longest_streak"`
+	ZeroReadWeeks     int     `json:"zero_read_weeks"`
+	BestWeek          string  `json:"best_week,omitempty"` // date of the best week's last snapshot
+	BestWeekReads     int     `json:"best_week_reads"`
+	RollingAvgReads   float64 `json:"rolling_avg_reads"` // average over the last RollingWeeks weeks
+	RollingWeeks      int     `json:"rolling_weeks"`
+	WeeklyReadsStdDev float64 `json:"weekly_reads_stddev"`
+}
+
+// GoalResult is one configured goal evaluated against a snapshot
+type GoalResult struct {
+	ID         string  `json:"id"`
+	Label      string  `json:"label"`
+	Kind       string  `json:"kind"`
+	Source     string  `json:"source,omitempty"`
+	Target     float64 `json:"target"`
+	Current    float64 `json:"current"`
+	Progress   float64 `json:"progress"` // percent of the way to the target, capped at 100
+	Met        bool    `json:"met"`
+	Streak     int     `json:"streak"`      // consecutive periods met, ending with this snapshot
+	StreakUnit string  `json:"streak_unit"` // "months" or "snapshots"
+}
+
+// BacklogForecast projects when the unread backlog reaches zero from the snapshot history.
+// Weekly rates are averaged over the observed span; negative changes mean the backlog is shrinking.
+type BacklogForecast struct {
+	TargetDate          string         `json:"target_date"`
+	WeeksObserved       float64        `json:"weeks_observed"`
+	CurrentUnread       int            `json:"current_unread"`
+	AvgWeeklyNetChange  float64        `json:"avg_weekly_net_change"`
+	TrendWeeklyChange   float64        `json:"trend_weekly_change"` // least-squares slope of unread per week
+	AvgWeeklyAdded      float64        `json:"avg_weekly_added"`
+	AvgWeeklyReads      float64        `json:"avg_weekly_reads"`
+	ProjectedZeroDate   string         `json:"projected_zero_date,omitempty"` // empty when the trend is not shrinking
+	RequiredWeeklyReads float64        `json:"required_weekly_reads"`         // to clear the backlog by TargetDate
+	History             []BacklogPoint `json:"history"`
+}
+
+// BacklogPoint is the unread count on one snapshot date
+type BacklogPoint struct {
+	Date   string `json:"date"`
+	Unread int    `json:"unread"`
+}
+
+// Reconstruction marks a snapshot rebuilt after the fact from the article ledger.
+// Read articles without a read date are assumed to have been read when they were added.
+type Reconstruction struct {
+	ReadDatesKnown   int `json:"read_dates_known"`
+	ReadDatesAssumed int `json:"read_dates_assumed"`
+}
+
+// QualityIssue describes one rejected or suspicious row in the articles sheet
+type QualityIssue struct {
+	Row      int    `json:"row"`  // 1-based sheet row number, header is row 1
+	Kind     string `json:"kind"` // e.g. invalid_date, duplicate_link
+	Detail   string `json:"detail"`
+	Rejected bool   `json:"rejected"` // true when the row was excluded from metrics
+	Title    string `json:"title,omitempty"`
+	Link     string `json:"link,omitempty"`
+}
+
+// SnapshotAnalysis is the AI delta analysis of a snapshot, saved separately as <snapshot>.analysis.json
+// so the snapshot itself is written once. SnapshotSHA256 ties it to the snapshot content it describes.
+type SnapshotAnalysis struct {
+	Snapshot        string    `json:"snapshot"`
+	SnapshotSHA256  string    `json:"snapshot_sha256"`
+	GeneratedAt     time.Time `json:"generated_at"`
+	AIDeltaAnalysis string    `json:"ai_delta_analysis"`
+}
+
+// QualityReport is the data-quality audit written next to each snapshot
+type QualityReport struct {
+	SnapshotDate string           `json:"snapshot_date"`
+	RowsScanned  int              `json:"rows_scanned"`
+	RowsRejected int              `json:"rows_rejected"`
+	ByKind       map[string]int   `json:"by_kind"`
+	Issues       []QualityIssue   `json:"issues"`             // rejected rows, left out of the snapshot
+	Findings     []QualityIssue   `json:"findings,omitempty"` // informational: rows counted but worth a look
+	Duplicates   []DuplicateMerge `json:"duplicates,omitempty"`
+}
+
+// DuplicateMerge records one sheet row folded into another copy of the same post
+type DuplicateMerge struct {
+	KeptRow       int     `json:"kept_row"`
+	DroppedRow    int     `json:"dropped_row"`
+	Reason        string  `json:"reason"`               // same_url or similar_title
+	Similarity    float64 `json:"similarity,omitempty"` // title word overlap for similar_title
+	KeptTitle     string  `json:"kept_title"`
+	DroppedTitle  string  `json:"dropped_title"`
+	KeptLink      string  `json:"kept_link"`
+	DroppedLink   string  `json:"dropped_link"`
+	KeptSource    string  `json:"kept_source"`
+	DroppedSource string  `json:"dropped_source"`
+}
+
+// AgeBucket declares one unread age bucket. Buckets are listed from youngest to oldest and
+// each starts at MinAge ("1m", "2y", ...); the first bucket has no MinAge.
+type AgeBucket struct {
+	Key    string `json:"key" yaml:"key"`
+	Label  string `json:"label" yaml:"label"`
+	MinAge string `json:"min_age,omitempty" yaml:"min_age,omitempty"`
 }
 
 // ArticleMeta holds minimal info for backlog/unread analysis
 type ArticleMeta struct {
-	Title    string `json:"title"`
-	Date     string `json:"date"`
-	Link     string `json:"link"`
-	Category string `json:"category"`
-	Read     bool   `json:"read"`
+	Title    string   `json:"title"`
+	Date     string   `json:"date"`
+	Link     string   `json:"link"`
+	Category string   `json:"category"`
+	Read     bool     `json:"read"`
+	Topics   []string `json:"topics,omitempty"`
 }
 
-// SourceMeta tracks when a source was added and its brand color
+// SourceMeta tracks when a source was added, its brand color and when it was retired
 type SourceMeta struct {
-	Added string `json:"added"`
-	Color string `json:"color"`
+	Added   string `json:"added"`
+	Color   string `json:"color"`
+	Retired string `json:"retired,omitempty"`
 }
 
 type SourceInfo struct {
-	Name        string
-	Count       int
-	Read        int
-	Unread      int
-	ReadPct     float64
-	AuthorCount int
-	Color       string
+	Name         string
+	Count        int
+	Read         int
+	Unread       int
+	ReadPct      float64
+	AuthorCount  int
+	Color        string
+	Retired      string
+	Publications []PublicationInfo
+}
+
+// PublicationInfo is the per-publication drill-down shown under the Substack source card
+type PublicationInfo struct {
+	Name    string
+	Count   int
+	Read    int
+	Unread  int
+	ReadPct float64
+	Backlog []ArticleMeta // oldest unread articles, oldest first
 }
 
 type MonthInfo struct {
@@ -77,6 +227,18 @@ type HightlightMetric struct {
 	Value string
 }
 
+type SourceHealthView struct {
+	Name string
+	SourceHealth
+	Warning string
+}
+
+type GoalView struct {
+	GoalResult
+	Summary    string
+	StreakText string
+}
+
 type EvolutionData struct {
 	Chapters []Chapter `yaml:"chapters"`
 }
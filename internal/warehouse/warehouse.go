@@ -0,0 +1,361 @@
+// Package warehouse loads metrics snapshots and the article ledger into a local SQLite
+// database so reading history can be queried with SQL.
+package warehouse
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+	metrics "github.com/victoriacheng15/personal-reading-analytics/internal/metrics"
+)
+
+// DefaultPath is where the warehouse database is written
+const DefaultPath = "warehouse/reading.db"
+
+// schemaVersion is stored in PRAGMA user_version; bump it when a table changes
+const schemaVersion = 2
+
+// dropSnapshotTablesSQL clears the snapshot tables of a database built by an older schema.
+// Both are derived from the snapshot files, so the next refresh simply reloads them.
+const dropSnapshotTablesSQL = `
+DROP VIEW IF EXISTS weekly_summary;
+DROP VIEW IF EXISTS source_history;
+DROP VIEW IF EXISTS backlog_age;
+DROP TABLE IF EXISTS snapshot_breakdowns;
+DROP TABLE IF EXISTS snapshots;
+`
+
+// tablesSQL creates the warehouse tables. Breakdowns reuse the dimensions of the CSV export.
+const tablesSQL = `
+CREATE TABLE IF NOT EXISTS snapshots (
+	snapshot_id            TEXT PRIMARY KEY, -- snapshot filename without .json: YYYY-MM-DD or YYYY-MM-DDTHHMMSS
+	snapshot_date          TEXT NOT NULL,    -- YYYY-MM-DD, from the snapshot filename
+	last_updated           TEXT NOT NULL,    -- RFC 3339 timestamp of the fetch
+	total_articles         INTEGER NOT NULL,
+	read_count             INTEGER NOT NULL,
+	unread_count           INTEGER NOT NULL,
+	read_rate              REAL NOT NULL,    -- percent
+	avg_articles_per_month REAL NOT NULL,
+	duplicates_merged      INTEGER NOT NULL,
+	reconstructed          INTEGER NOT NULL, -- 1 for backfilled snapshots
+	ai_delta_analysis      TEXT,
+	content_hash           TEXT NOT NULL     -- sha256 of the snapshot and its analysis sidecar as JSON documents, for incremental refresh
+);
+
+CREATE INDEX IF NOT EXISTS snapshots_date ON snapshots (snapshot_date);
+
+CREATE TABLE IF NOT EXISTS snapshot_breakdowns (
+	snapshot_id TEXT NOT NULL,
+	dimension   TEXT NOT NULL,    -- source, year, month, age_bucket or category
+	key         TEXT NOT NULL,
+	position    INTEGER NOT NULL, -- order within the dimension; age buckets keep the snapshot's order
+	read        INTEGER NOT NULL,
+	unread      INTEGER NOT NULL,
+	PRIMARY KEY (snapshot_id, dimension, key)
+);
+
+CREATE TABLE IF NOT EXISTS articles (
+	id             INTEGER PRIMARY KEY,
+	published_date TEXT NOT NULL,
+	title          TEXT NOT NULL,
+	link           TEXT NOT NULL,
+	source         TEXT NOT NULL,
+	read           INTEGER NOT NULL,
+	topics         TEXT NOT NULL -- comma-separated
+);
+
+CREATE INDEX IF NOT EXISTS articles_source ON articles (source);
+`
+
+// viewsSQL is recreated on every Open so view changes reach existing databases
+const viewsSQL = `
+DROP VIEW IF EXISTS weekly_summary;
+CREATE VIEW weekly_summary AS
+WITH ranked AS (
+	SELECT date(snapshot_date, 'weekday 0', '-6 days') AS week_start, snapshot_id, snapshot_date,
+		total_articles, read_count, unread_count, read_rate,
+		ROW_NUMBER() OVER (PARTITION BY date(snapshot_date, 'weekday 0', '-6 days') ORDER BY snapshot_id DESC) AS week_rank
+	FROM snapshots
+)
+SELECT week_start, snapshot_id, snapshot_date, total_articles, read_count, unread_count, read_rate,
+	read_count - LAG(read_count) OVER (ORDER BY week_start) AS reads,
+	total_articles - LAG(total_articles) OVER (ORDER BY week_start) AS added
+FROM ranked
+WHERE week_rank = 1;
+
+DROP VIEW IF EXISTS source_history;
+CREATE VIEW source_history AS
+SELECT b.snapshot_id, s.snapshot_date, b.key AS source, b.read, b.unread, b.read + b.unread AS total,
+	ROUND(100.0 * b.read / NULLIF(b.read + b.unread, 0), 2) AS read_rate
+FROM snapshot_breakdowns b
+JOIN snapshots s ON s.snapshot_id = b.snapshot_id
+WHERE b.dimension = 'source';
+
+DROP VIEW IF EXISTS backlog_age;
+CREATE VIEW backlog_age AS
+SELECT b.snapshot_id, s.snapshot_date, b.key AS bucket, b.position, b.unread,
+	ROUND(100.0 * b.unread / NULLIF(s.unread_count, 0), 2) AS share
+FROM snapshot_breakdowns b
+JOIN snapshots s ON s.snapshot_id = b.snapshot_id
+WHERE b.dimension = 'age_bucket';
+`
+
+// Warehouse is an open SQLite warehouse
+type Warehouse struct {
+	db *sql.DB
+}
+
+// RefreshStats counts what a refresh did to the snapshot tables
+type RefreshStats struct {
+	Loaded    int // new or changed snapshot files
+	Unchanged int // files whose content hash matched
+	Removed   int // snapshots whose file is gone
+}
+
+// Open opens or creates the warehouse at path and brings its schema up to date
+func Open(path string) (*Warehouse, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create warehouse directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open warehouse %s: %w", path, err)
+	}
+
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to read warehouse schema version: %w", err)
+	}
+
+	stmts := []string{tablesSQL, viewsSQL, fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)}
+	if version > 0 && version < schemaVersion {
+		stmts = append([]string{dropSnapshotTablesSQL}, stmts...)
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to apply warehouse schema: %w", err)
+		}
+	}
+
+	return &Warehouse{db: db}, nil
+}
+
+// DB returns the underlying database for ad-hoc queries
+func (w *Warehouse) DB() *sql.DB {
+	return w.db
+}
+
+// Close closes the database
+func (w *Warehouse) Close() error {
+	return w.db.Close()
+}
+
+// storedHashes returns snapshot ID -> content hash of every loaded snapshot
+func (w *Warehouse) storedHashes() (map[string]string, error) {
+	rows, err := w.db.Query("SELECT snapshot_id, content_hash FROM snapshots")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read loaded snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var id, hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			return nil, fmt.Errorf("unable to read loaded snapshots: %w", err)
+		}
+		hashes[id] = hash
+	}
+	return hashes, rows.Err()
+}
+
+// Refresh loads the snapshots in store whose content changed since the last refresh and drops
+// snapshots whose file was deleted. normalize, when set, is applied to each snapshot before it
+// is stored (e.g. to canonicalize source names). rebuild reloads every snapshot regardless of
+// its hash, which is needed after normalize itself changes.
+func (w *Warehouse) Refresh(ctx context.Context, store metrics.SnapshotStore, normalize func(*schema.Metrics), rebuild bool) (RefreshStats, error) {
+	var stats RefreshStats
+
+	names, err := store.List(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	stored, err := w.storedHashes()
+	if err != nil {
+		return stats, err
+	}
+
+	tx, err := w.db.Begin()
+	if err != nil {
+		return stats, fmt.Errorf("failed to start refresh: %w", err)
+	}
+	defer tx.Rollback()
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		id := strings.TrimSuffix(name, ".json")
+		seen[id] = true
+
+		content, err := store.ReadFile(ctx, name)
+		if err != nil {
+			return stats, err
+		}
+		sidecar, err := metrics.ReadAnalysisFile(ctx, store, name)
+		if err != nil {
+			return stats, err
+		}
+		hash, err := contentHash(name, content, sidecar)
+		if err != nil {
+			return stats, err
+		}
+		if !rebuild && stored[id] == hash {
+			stats.Unchanged++
+			continue
+		}
+
+		snapshot, err := metrics.DecodeSnapshot(name, content)
+		if err != nil {
+			return stats, err
+		}
+		// A stale analysis sidecar is ignored, keeping the analysis stored in the snapshot itself
+		if err := metrics.ApplyAnalysis(name, content, sidecar, &snapshot); err != nil && !errors.Is(err, metrics.ErrStaleAnalysis) {
+			return stats, err
+		}
+		if normalize != nil {
+			normalize(&snapshot)
+		}
+
+		if err := replaceSnapshot(tx, id, hash, snapshot); err != nil {
+			return stats, err
+		}
+		stats.Loaded++
+	}
+
+	for id := range stored {
+		if seen[id] {
+			continue
+		}
+		if err := deleteSnapshot(tx, id); err != nil {
+			return stats, err
+		}
+		stats.Removed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return stats, fmt.Errorf("failed to commit refresh: %w", err)
+	}
+	return stats, nil
+}
+
+// contentHash identifies a snapshot and its analysis sidecar by their JSON documents rather than
+// their bytes, so reformatting a snapshot or compacting it into the archive does not reload it
+func contentHash(name string, content, sidecar []byte) (string, error) {
+	hash, err := metrics.SnapshotHash(content)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse snapshot %s: %w", name, err)
+	}
+	if sidecar == nil {
+		return hash, nil
+	}
+	sidecarHash, err := metrics.SnapshotHash(sidecar)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse %s: %w", metrics.AnalysisFilename(name), err)
+	}
+	sum := sha256.Sum256([]byte(hash + sidecarHash))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// deleteSnapshot removes a snapshot and its breakdown rows
+func deleteSnapshot(tx *sql.Tx, id string) error {
+	for _, table := range []string{"snapshot_breakdowns", "snapshots"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE snapshot_id = ?", id); err != nil {
+			return fmt.Errorf("failed to remove snapshot %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// replaceSnapshot stores a snapshot's headline figures and breakdowns, replacing any earlier load
+func replaceSnapshot(tx *sql.Tx, id, hash string, m schema.Metrics) error {
+	if err := deleteSnapshot(tx, id); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`INSERT INTO snapshots (snapshot_id, snapshot_date, last_updated, total_articles, read_count, unread_count,
+		read_rate, avg_articles_per_month, duplicates_merged, reconstructed, ai_delta_analysis, content_hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, metrics.SnapshotFileDate(id), m.LastUpdated.Format(time.RFC3339), m.TotalArticles, m.ReadCount, m.UnreadCount,
+		m.ReadRate, m.AvgArticlesPerMonth, m.DuplicatesMerged, m.Reconstructed != nil, nullString(m.AIDeltaAnalysis), hash)
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot %s: %w", id, err)
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO snapshot_breakdowns (snapshot_id, dimension, key, position, read, unread)
+		VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to load breakdowns of %s: %w", id, err)
+	}
+	defer stmt.Close()
+
+	position, dimension := 0, ""
+	for _, row := range metrics.SnapshotBreakdown(m) {
+		if row.Dimension != dimension {
+			position, dimension = 0, row.Dimension
+		}
+		if _, err := stmt.Exec(id, row.Dimension, row.Key, position, row.Read, row.Unread); err != nil {
+			return fmt.Errorf("failed to load breakdowns of %s: %w", id, err)
+		}
+		position++
+	}
+	return nil
+}
+
+// nullString stores empty text as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// LoadArticles replaces the articles table with the given ledger
+func (w *Warehouse) LoadArticles(articles []schema.ArticleMeta) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start article load: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM articles"); err != nil {
+		return fmt.Errorf("failed to clear articles: %w", err)
+	}
+
+	stmt, err := tx.Prepare("INSERT INTO articles (published_date, title, link, source, read, topics) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to load articles: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, article := range articles {
+		if _, err := stmt.Exec(article.Date, article.Title, article.Link, article.Category, article.Read, strings.Join(article.Topics, ",")); err != nil {
+			return fmt.Errorf("failed to load article %q: %w", article.Title, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit article load: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+	metrics "github.com/victoriacheng15/personal-reading-analytics/internal/metrics"
+)
+
+func writeSnapshot(t *testing.T, dir, date string, total, read int, bySource map[string][2]int) {
+	t.Helper()
+	day, _ := time.Parse("2006-01-02", date)
+	m := schema.Metrics{
+		LastUpdated:        day,
+		TotalArticles:      total,
+		ReadCount:          read,
+		UnreadCount:        total - read,
+		ReadRate:           float64(read) / float64(total) * 100,
+		BySourceReadStatus: bySource,
+		UnreadArticleAgeDistribution: map[string]int{
+			"less_than_1_month": total - read - 2,
+			"1_to_3_months":     2,
+		},
+	}
+	content, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed to marshal snapshot: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, date+".json"), content, 0644); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+}
+
+func openTestWarehouse(t *testing.T) *Warehouse {
+	t.Helper()
+	w, err := Open(filepath.Join(t.TempDir(), "nested", "reading.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func TestRefresh(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "2026-03-02", 100, 40, map[string][2]int{"GitHub": {30, 30}, "Stripe": {10, 30}})
+	writeSnapshot(t, dir, "2026-03-04", 104, 44, map[string][2]int{"GitHub": {33, 31}, "Stripe": {11, 29}})
+	writeSnapshot(t, dir, "2026-03-10", 110, 50, map[string][2]int{"GitHub": {36, 32}, "Stripe": {14, 28}})
+	os.WriteFile(filepath.Join(dir, "2026-03-10.quality.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(dir, ".gitkeep"), nil, 0644)
+
+	w := openTestWarehouse(t)
+	renames := 0
+	normalize := func(m *schema.Metrics) { renames++ }
+
+	stats, err := w.Refresh(context.Background(), metrics.NewFileStore(dir), normalize, false)
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if stats != (RefreshStats{Loaded: 3}) || renames != 3 {
+		t.Errorf("unexpected first refresh: %+v, %d normalized", stats, renames)
+	}
+
+	t.Run("weekly summary keeps the last snapshot of each week", func(t *testing.T) {
+		rows, err := w.DB().Query("SELECT week_start, snapshot_date, reads, added FROM weekly_summary ORDER BY week_start")
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		defer rows.Close()
+
+		type week struct {
+			start, date string
+			reads, add  *int
+		}
+		var weeks []week
+		for rows.Next() {
+			var wk week
+			if err := rows.Scan(&wk.start, &wk.date, &wk.reads, &wk.add); err != nil {
+				t.Fatalf("scan failed: %v", err)
+			}
+			weeks = append(weeks, wk)
+		}
+		if len(weeks) != 2 {
+			t.Fatalf("expected 2 weeks, got %d", len(weeks))
+		}
+		if weeks[0].start != "2026-03-02" || weeks[0].date != "2026-03-04" || weeks[0].reads != nil {
+			t.Errorf("unexpected first week: %+v", weeks[0])
+		}
+		if weeks[1].start != "2026-03-09" || weeks[1].reads == nil || *weeks[1].reads != 6 || *weeks[1].add != 6 {
+			t.Errorf("unexpected second week: %+v", weeks[1])
+		}
+	})
+
+	t.Run("source history and backlog age", func(t *testing.T) {
+		var total int
+		var rate float64
+		err := w.DB().QueryRow("SELECT total, read_rate FROM source_history WHERE snapshot_date = '2026-03-10' AND source = 'Stripe'").Scan(&total, &rate)
+		if err != nil || total != 42 || rate != 33.33 {
+			t.Errorf("unexpected Stripe history: %d, %v, %v", total, rate, err)
+		}
+
+		var unread int
+		var share float64
+		err = w.DB().QueryRow("SELECT unread, share FROM backlog_age WHERE snapshot_date = '2026-03-10' AND bucket = '1_to_3_months'").Scan(&unread, &share)
+		if err != nil || unread != 2 || share != 3.33 {
+			t.Errorf("unexpected backlog age: %d, %v, %v", unread, share, err)
+		}
+	})
+
+	t.Run("incremental refresh", func(t *testing.T) {
+		writeSnapshot(t, dir, "2026-03-10", 110, 52, map[string][2]int{"GitHub": {38, 30}, "Stripe": {14, 28}})
+		os.Remove(filepath.Join(dir, "2026-03-02.json"))
+
+		stats, err := w.Refresh(context.Background(), metrics.NewFileStore(dir), nil, false)
+		if err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		if stats != (RefreshStats{Loaded: 1, Unchanged: 1, Removed: 1}) {
+			t.Errorf("unexpected incremental refresh: %+v", stats)
+		}
+
+		var read, breakdowns int
+		w.DB().QueryRow("SELECT read_count FROM snapshots WHERE snapshot_date = '2026-03-10'").Scan(&read)
+		w.DB().QueryRow("SELECT COUNT(*) FROM snapshot_breakdowns WHERE snapshot_id = '2026-03-02'").Scan(&breakdowns)
+		if read != 52 || breakdowns != 0 {
+			t.Errorf("expected the changed snapshot reloaded and the deleted one dropped, got read %d and %d breakdowns", read, breakdowns)
+		}
+
+		stats, _ = w.Refresh(context.Background(), metrics.NewFileStore(dir), nil, true)
+		if stats != (RefreshStats{Loaded: 2}) {
+			t.Errorf("expected rebuild to reload every snapshot, got %+v", stats)
+		}
+	})
+
+	t.Run("analysis sidecar", func(t *testing.T) {
+		store := metrics.NewFileStore(dir)
+		if err := metrics.SaveAnalysis(context.Background(), store, "2026-03-10.json", "Two more reads."); err != nil {
+			t.Fatalf("SaveAnalysis failed: %v", err)
+		}
+
+		// A new sidecar changes the hash, so only its snapshot is reloaded
+		stats, err := w.Refresh(context.Background(), store, nil, false)
+		if err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		if stats != (RefreshStats{Loaded: 1, Unchanged: 1}) {
+			t.Errorf("expected the analyzed snapshot reloaded, got %+v", stats)
+		}
+
+		var analysis string
+		w.DB().QueryRow("SELECT ai_delta_analysis FROM snapshots WHERE snapshot_id = '2026-03-10'").Scan(&analysis)
+		if analysis != "Two more reads." {
+			t.Errorf("expected the sidecar analysis loaded, got %q", analysis)
+		}
+	})
+
+	t.Run("compaction keeps hashes", func(t *testing.T) {
+		store := metrics.NewFileStore(dir)
+		if _, err := metrics.CompactSnapshots(context.Background(), store, false); err != nil {
+			t.Fatalf("CompactSnapshots failed: %v", err)
+		}
+
+		// The archive rebuilds the snapshots with other formatting, which must not count as a change
+		stats, err := w.Refresh(context.Background(), store, nil, false)
+		if err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		if stats != (RefreshStats{Unchanged: 2}) {
+			t.Errorf("expected archived snapshots unchanged, got %+v", stats)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		if _, err := w.Refresh(context.Background(), metrics.NewFileStore(filepath.Join(dir, "missing")), nil, false); err == nil {
+			t.Error("expected an error for a missing metrics directory")
+		}
+	})
+}
+
+func TestRefreshIntraday(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "2026-03-10", 110, 50, map[string][2]int{"GitHub": {36, 32}, "Stripe": {14, 28}})
+	writeSnapshot(t, dir, "2026-03-10T183000", 110, 53, map[string][2]int{"GitHub": {39, 29}, "Stripe": {14, 28}})
+
+	w := openTestWarehouse(t)
+	if _, err := w.Refresh(context.Background(), metrics.NewFileStore(dir), nil, false); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	var snapshots int
+	w.DB().QueryRow("SELECT COUNT(*) FROM snapshots WHERE snapshot_date = '2026-03-10'").Scan(&snapshots)
+	if snapshots != 2 {
+		t.Errorf("expected both snapshots of the day to be kept, got %d", snapshots)
+	}
+
+	var id string
+	var read int
+	w.DB().QueryRow("SELECT snapshot_id, read_count FROM weekly_summary").Scan(&id, &read)
+	if id != "2026-03-10T183000" || read != 53 {
+		t.Errorf("expected the week to end on the later snapshot, got %s with %d read", id, read)
+	}
+}
+
+func TestOpenDropsOutdatedSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reading.db")
+	w, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	// Simulate a database built before snapshots were keyed by ID
+	for _, stmt := range []string{dropSnapshotTablesSQL, "CREATE TABLE snapshots (snapshot_date TEXT PRIMARY KEY)", "PRAGMA user_version = 1"} {
+		if _, err := w.DB().Exec(stmt); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+	}
+	w.Close()
+
+	w, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open failed on an outdated schema: %v", err)
+	}
+	defer w.Close()
+
+	var version int
+	w.DB().QueryRow("PRAGMA user_version").Scan(&version)
+	if _, err := w.DB().Exec("SELECT snapshot_id FROM snapshots"); err != nil || version != schemaVersion {
+		t.Errorf("expected the snapshot tables recreated at version %d, got version %d: %v", schemaVersion, version, err)
+	}
+}
+
+func TestLoadArticles(t *testing.T) {
+	w := openTestWarehouse(t)
+
+	articles := []schema.ArticleMeta{
+		{Date: "2026-01-05", Title: "Go tips", Link: "https://a.example/1", Category: "GitHub", Read: true, Topics: []string{"go", "tooling"}},
+		{Date: "2026-02-10", Title: "Payments", Link: "https://a.example/2", Category: "Stripe"},
+	}
+	if err := w.LoadArticles(articles); err != nil {
+		t.Fatalf("LoadArticles failed: %v", err)
+	}
+	// A second load replaces the ledger instead of appending to it
+	if err := w.LoadArticles(articles); err != nil {
+		t.Fatalf("LoadArticles failed: %v", err)
+	}
+
+	var count, read int
+	var topics string
+	w.DB().QueryRow("SELECT COUNT(*), SUM(read) FROM articles").Scan(&count, &read)
+	w.DB().QueryRow("SELECT topics FROM articles WHERE source = 'GitHub'").Scan(&topics)
+	if count != 2 || read != 1 || topics != "go,tooling" {
+		t.Errorf("unexpected articles: %d rows, %d read, topics %q", count, read, topics)
+	}
+}
@@ -1,11 +1,16 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"html/template"
 	"io"
 	"log"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -53,7 +58,83 @@ func (s *AnalyticsService) GenerateFullSite(m schema.Metrics, config GenConfig)
 		log.Printf("⚠️ Warning: Failed to generate evolution registry: %v", err)
 	}
 
-	return s.render(vm, config.OutputDir, pages, true)
+	// Generate the OpenMetrics scrape file
+	if err := s.generateOpenMetrics(m, config.OutputDir); err != nil {
+		log.Printf("⚠️ Warning: Failed to generate metrics.txt: %v", err)
+	}
+
+	if err := s.render(vm, config.OutputDir, pages, true); err != nil {
+		return err
+	}
+
+	if err := s.generateSourcePages(vm, m, config); err != nil {
+		return err
+	}
+	return s.generateYearPages(vm, m, config)
+}
+
+// generateSourcePages writes sources/<slug>.html for every source in the snapshot
+func (s *AnalyticsService) generateSourcePages(vm ViewModel, m schema.Metrics, config GenConfig) error {
+	tmplDir, err := GetTemplatesDir()
+	if err != nil {
+		return fmt.Errorf("failed to get templates directory: %w", err)
+	}
+
+	outputDir := filepath.Join(config.OutputDir, "sources")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create sources directory: %w", err)
+	}
+
+	// Source pages sit one level below the site root
+	vm.BaseURL = config.BaseURL + "../"
+	for _, source := range vm.Sources {
+		page := PrepareSourcePage(m, source, config.SourceHistory[source.Name])
+		vm.Source = &page
+		vm.PageTitle = "📌 " + source.Name
+
+		if err := renderPage(vm, tmplDir, "source.html", filepath.Join(outputDir, page.Slug+".html")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateYearPages writes years/<YYYY>.html for every publication year in the snapshot
+func (s *AnalyticsService) generateYearPages(vm ViewModel, m schema.Metrics, config GenConfig) error {
+	tmplDir, err := GetTemplatesDir()
+	if err != nil {
+		return fmt.Errorf("failed to get templates directory: %w", err)
+	}
+
+	outputDir := filepath.Join(config.OutputDir, "years")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create years directory: %w", err)
+	}
+
+	colors := make(map[string]string, len(vm.Sources))
+	for _, source := range vm.Sources {
+		colors[source.Name] = source.Color
+	}
+
+	// Year pages sit one level below the site root; AllYears is newest first
+	vm.BaseURL = config.BaseURL + "../"
+	vm.Source = nil
+	for i, year := range vm.AllYears {
+		page := PrepareYearPage(m, year, colors)
+		if i > 0 {
+			page.NextYear = vm.AllYears[i-1]
+		}
+		if i < len(vm.AllYears)-1 {
+			page.PreviousYear = vm.AllYears[i+1]
+		}
+		vm.Year = &page
+		vm.PageTitle = "🗓️ " + year + " in Reading"
+
+		if err := renderPage(vm, tmplDir, "year.html", filepath.Join(outputDir, year+".html")); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GenerateAnalyticsOnly generates only the analytics.html page
@@ -86,23 +167,29 @@ func (s *AnalyticsService) prepareViewModel(m schema.Metrics, config GenConfig)
 		}
 
 		authorCount := 0
+		var publications []schema.PublicationInfo
 		if name == "Substack" {
-			authorCount = m.BySourceReadStatus["substack_author_count"][0]
+			publications = PrepareSubstackPublications(m)
+			authorCount = substackAuthorCount(m, publications)
 		}
 
 		color := ""
+		retired := ""
 		if meta, exists := m.SourceMetadata[name]; exists {
 			color = meta.Color
+			retired = meta.Retired
 		}
 
 		sources = append(sources, schema.SourceInfo{
-			Name:        name,
-			Count:       count,
-			Read:        read,
-			Unread:      unread,
-			ReadPct:     readPct,
-			AuthorCount: authorCount,
-			Color:       color,
+			Name:         name,
+			Count:        count,
+			Read:         read,
+			Unread:       unread,
+			ReadPct:      readPct,
+			AuthorCount:  authorCount,
+			Color:        color,
+			Retired:      retired,
+			Publications: publications,
 		})
 	}
 
@@ -164,10 +251,10 @@ func (s *AnalyticsService) prepareViewModel(m schema.Metrics, config GenConfig)
 	}
 
 	// Determine current month (MM format) for badge calculation
-	now := time.Now()
+	now := referenceTime(m, config)
 	currentMonth := now.Format("01")
 
-	// If the current month (from system time) has no data,
+	// If the current month (as of the snapshot) has no data,
 	// fall back to the latest month available in the metrics to provide
 	// a better "latest snapshot" view.
 	if _, exists := m.ByMonth[currentMonth]; !exists {
@@ -200,20 +287,14 @@ func (s *AnalyticsService) prepareViewModel(m schema.Metrics, config GenConfig)
 	allYearsJSON, _ := json.Marshal(allYears)
 	allSourcesJSON, _ := json.Marshal(allSources)
 
-	// Prepare key metrics
-	keyMetrics := []schema.KeyMetric{
-		{Title: "Total Articles", Value: fmt.Sprintf("%d", m.TotalArticles)},
-		{Title: "Read Rate", Value: fmt.Sprintf("%.1f%%", m.ReadRate)},
-		{Title: "Read", Value: fmt.Sprintf("%d", m.ReadCount)},
-		{Title: "Unread", Value: fmt.Sprintf("%d", m.UnreadCount)},
-		{Title: "Avg/Month", Value: fmt.Sprintf("%.0f", m.AvgArticlesPerMonth)},
-	}
+	keyMetrics := PrepareKeyMetrics(m)
 
 	highlightMetrics := []schema.HightlightMetric{
 		{Title: "🎯 Top Read Rate Source", Value: topReadRateSource},
 		{Title: "📚 Most Unread Source", Value: mostUnreadSource},
 		{Title: "✅ This Month's Articles", Value: fmt.Sprintf("%d", thisMonthArticles)},
 	}
+	highlightMetrics = append(highlightMetrics, PrepareCadenceHighlights(m.Cadence)...)
 
 	// Load evolution data
 	evolutionData, err := LoadEvolutionData()
@@ -254,6 +335,7 @@ func (s *AnalyticsService) prepareViewModel(m schema.Metrics, config GenConfig)
 		LastUpdated:                      m.LastUpdated,
 		AIDeltaAnalysis:                  m.AIDeltaAnalysis,
 		Sources:                          sources,
+		SourceSlugs:                      SourceSlugs(m),
 		Months:                           monthlyAggregated,
 		Years:                            years,
 		AllYears:                         allYears,
@@ -270,15 +352,20 @@ func (s *AnalyticsService) prepareViewModel(m schema.Metrics, config GenConfig)
 		ReadUnreadByYearJSON:             readUnreadByYearJSON,
 		UnreadArticleAgeDistributionJSON: unreadArticleAgeDistributionJSON,
 		UnreadByYearJSON:                 unreadByYearJSON,
+		Goals:                            PrepareGoals(m),
+		SourceHealth:                     PrepareSourceHealth(m),
+		BacklogForecast:                  m.BacklogForecast,
+		BacklogForecastJSON:              PrepareBacklogForecast(m),
 		TopOldestUnreadArticles:          m.TopOldestUnreadArticles,
 		EvolutionData:                    evolutionData,
 		Landing:                          landing,
 
 		// New fields from config
-		BaseURL:      config.BaseURL,
-		IsHistorical: config.IsHistorical,
-		HistoryDates: config.HistoryDates,
-		ReportDate:   config.ReportDate,
+		BaseURL:       config.BaseURL,
+		IsHistorical:  config.IsHistorical,
+		HistoryDates:  config.HistoryDates,
+		ReportDate:    config.ReportDate,
+		Reconstructed: m.Reconstructed,
 	}, nil
 }
 
@@ -292,19 +379,6 @@ func (s *AnalyticsService) render(vm ViewModel, outputDir string, pages []struct
 		return fmt.Errorf("failed to get templates directory: %w", err)
 	}
 
-	// Common function map
-	funcMap := template.FuncMap{
-		"divideFloat": func(a, b int) float64 {
-			if b == 0 {
-				return 0
-			}
-			return float64(a) / float64(b)
-		},
-		"sub": func(a, b int) int {
-			return a - b
-		},
-	}
-
 	// Create output directory
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -320,39 +394,54 @@ func (s *AnalyticsService) render(vm ViewModel, outputDir string, pages []struct
 
 	// Loop and generate each page
 	for _, page := range pages {
-		// Create new template instance for this page
-		tmpl := template.New("").Funcs(funcMap)
+		// Update PageTitle in ViewModel for this page
+		vm.PageTitle = page.Title
 
-		// Parse shared templates and the specific page template
-		files := []string{
-			filepath.Join(tmplDir, "base.html"),
-			filepath.Join(tmplDir, page.Filename),
+		if err := renderPage(vm, tmplDir, page.Filename, filepath.Join(outputDir, page.Filename)); err != nil {
+			return err
 		}
+	}
 
-		// Parse files
-		tmpl, err = tmpl.ParseFiles(files...)
-		if err != nil {
-			return fmt.Errorf("failed to parse templates for %s: %w", page.Filename, err)
-		}
+	return nil
+}
 
-		// Create output file
-		outPath := filepath.Join(outputDir, page.Filename)
-		f, err := os.Create(outPath)
-		if err != nil {
-			return fmt.Errorf("failed to create %s: %w", outPath, err)
-		}
-		defer f.Close()
+// templateFuncs is the function map shared by every page template
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"divideFloat": func(a, b int) float64 {
+			if b == 0 {
+				return 0
+			}
+			return float64(a) / float64(b)
+		},
+		"sub": func(a, b int) int {
+			return a - b
+		},
+	}
+}
 
-		// Update PageTitle in ViewModel for this page
-		vm.PageTitle = page.Title
+// renderPage executes the named page template with the base layout and writes it to outPath
+func renderPage(vm ViewModel, tmplDir, templateName, outPath string) error {
+	// Parse shared templates and the specific page template
+	tmpl, err := template.New("").Funcs(templateFuncs()).ParseFiles(
+		filepath.Join(tmplDir, "base.html"),
+		filepath.Join(tmplDir, templateName),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to parse templates for %s: %w", templateName, err)
+	}
 
-		// Execute the template matching the filename
-		err = tmpl.ExecuteTemplate(f, page.Filename, vm)
-		if err != nil {
-			return fmt.Errorf("failed to execute template for %s: %w", page.Filename, err)
-		}
+	// Create output file
+	f, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", outPath, err)
 	}
+	defer f.Close()
 
+	// Execute the template matching the filename
+	if err := tmpl.ExecuteTemplate(f, templateName, vm); err != nil {
+		return fmt.Errorf("failed to execute template for %s: %w", templateName, err)
+	}
 	return nil
 }
 
@@ -513,6 +602,41 @@ func (s *AnalyticsService) generateRegistry(vm ViewModel, outputDir string) erro
 	return nil
 }
 
+// generateOpenMetrics writes the snapshot in OpenMetrics text format to metrics.txt
+func (s *AnalyticsService) generateOpenMetrics(m schema.Metrics, outputDir string) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	f, err := os.Create(filepath.Join(outputDir, "metrics.txt"))
+	if err != nil {
+		return fmt.Errorf("failed to create metrics.txt: %w", err)
+	}
+	defer f.Close()
+
+	return metrics.WriteOpenMetrics(f, m)
+}
+
+// NewSiteHandler serves the generated site from distDir and the latest snapshot as OpenMetrics at
+// /metrics. The snapshot is loaded on every scrape, so a refreshed metrics/ folder shows up without a restart.
+func NewSiteHandler(distDir string, latest func() (schema.Metrics, error)) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.Dir(distDir)))
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		m, err := latest()
+		if err != nil {
+			log.Printf("⚠️ Warning: Failed to load the latest snapshot: %v", err)
+			http.Error(w, "failed to load metrics", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", metrics.OpenMetricsContentType)
+		if err := metrics.WriteOpenMetrics(w, m); err != nil {
+			log.Printf("⚠️ Warning: Failed to write metrics: %v", err)
+		}
+	})
+	return mux
+}
+
 // ==============================================================================
 // CHART PREPARATION HELPERS
 // ==============================================================================
@@ -734,6 +858,17 @@ func LoadLanding() (schema.Landing, error) {
 
 var shortMonthNames = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
 
+// PrepareKeyMetrics formats the headline numbers shown on the dashboard and in the feeds
+func PrepareKeyMetrics(m schema.Metrics) []schema.KeyMetric {
+	return []schema.KeyMetric{
+		{Title: "Total Articles", Value: fmt.Sprintf("%d", m.TotalArticles)},
+		{Title: "Read Rate", Value: fmt.Sprintf("%.1f%%", m.ReadRate)},
+		{Title: "Read", Value: fmt.Sprintf("%d", m.ReadCount)},
+		{Title: "Unread", Value: fmt.Sprintf("%d", m.UnreadCount)},
+		{Title: "Avg/Month", Value: fmt.Sprintf("%.0f", m.AvgArticlesPerMonth)},
+	}
+}
+
 // PrepareReadUnreadByYear creates JSON data for read/unread yearly breakdown chart
 func PrepareReadUnreadByYear(metrics schema.Metrics) template.JS {
 	// Get sorted years in descending order (latest first)
@@ -823,27 +958,27 @@ func PrepareReadUnreadBySource(sources []schema.SourceInfo) template.JS {
 	return template.JS(jsonData)
 }
 
-// PrepareUnreadArticleAgeDistribution creates JSON data for unread articles by age chart
-func PrepareUnreadArticleAgeDistribution(metrics schema.Metrics) template.JS {
-	// Define age bucket labels in display order
-	bucketLabels := []struct {
-		key   string
-		label string
-	}{
-		{"less_than_1_month", "Less than 1 month"},
-		{"1_to_3_months", "1-3 months"},
-		{"3_to_6_months", "3-6 months"},
-		{"6_to_12_months", "6-12 months"},
-		{"older_than_1year", "Older than 1 year"},
+// ageBucketsFor returns the buckets a snapshot declares, or the default scheme for older snapshots
+func ageBucketsFor(m schema.Metrics) []schema.AgeBucket {
+	if len(m.AgeBuckets) > 0 {
+		return m.AgeBuckets
 	}
+	return metrics.DefaultAgeBuckets()
+}
 
+// PrepareUnreadArticleAgeDistribution creates JSON data for unread articles by age chart,
+// in the bucket order the snapshot declares
+func PrepareUnreadArticleAgeDistribution(metrics schema.Metrics) template.JS {
 	labels := make([]string, 0)
 	data := make([]int, 0)
 
-	for _, bucket := range bucketLabels {
-		labels = append(labels, bucket.label)
-		count := metrics.UnreadArticleAgeDistribution[bucket.key]
-		data = append(data, count)
+	for _, bucket := range ageBucketsFor(metrics) {
+		label := bucket.Label
+		if label == "" {
+			label = bucket.Key
+		}
+		labels = append(labels, label)
+		data = append(data, metrics.UnreadArticleAgeDistribution[bucket.Key])
 	}
 
 	chartData := map[string]interface{}{
@@ -854,6 +989,464 @@ func PrepareUnreadArticleAgeDistribution(metrics schema.Metrics) template.JS {
 	return template.JS(jsonData)
 }
 
+// PrepareCadenceHighlights turns the snapshot's weekly reading cadence into highlight cards.
+// Snapshots without cadence data get none.
+func PrepareCadenceHighlights(cadence *schema.Cadence) []schema.HightlightMetric {
+	if cadence == nil {
+		return nil
+	}
+
+	highlights := []schema.HightlightMetric{
+		{Title: "🔥 Weekly Streak", Value: fmt.Sprintf("%d wk (best %d)", cadence.CurrentStreak, cadence.LongestStreak)},
+		{Title: "📈 Reads/Week", Value: fmt.Sprintf("%.1f (last %d wk)", cadence.RollingAvgReads, cadence.RollingWeeks)},
+	}
+	if cadence.BestWeek != "" {
+		highlights = append(highlights, schema.HightlightMetric{
+			Title: "🏅 Best Week", Value: fmt.Sprintf("%d (%s)", cadence.BestWeekReads, cadence.BestWeek),
+		})
+	}
+	highlights = append(highlights,
+		schema.HightlightMetric{Title: "💤 Zero-Read Weeks", Value: fmt.Sprintf("%d of %d", cadence.ZeroReadWeeks, cadence.WeeksObserved)},
+		schema.HightlightMetric{Title: "📊 Weekly Variability", Value: fmt.Sprintf("±%.1f reads", cadence.WeeklyReadsStdDev)},
+	)
+	return highlights
+}
+
+// sourceHealthOrder puts the classes that need attention first
+var sourceHealthOrder = map[string]int{
+	metrics.HealthStale:    0,
+	metrics.HealthNoisy:    1,
+	metrics.HealthActive:   2,
+	metrics.HealthValuable: 3,
+	metrics.HealthRetired:  4,
+}
+
+// PrepareSourceHealth builds the source health table rows, stale and noisy sources first,
+// with a warning for every source that has gone silent
+func PrepareSourceHealth(m schema.Metrics) []schema.SourceHealthView {
+	views := make([]schema.SourceHealthView, 0, len(m.SourceHealth))
+	for name, health := range m.SourceHealth {
+		view := schema.SourceHealthView{Name: name, SourceHealth: health}
+		if health.Class == metrics.HealthStale {
+			if health.LastArticle == "" {
+				view.Warning = fmt.Sprintf("%s has no articles yet", name)
+			} else {
+				view.Warning = fmt.Sprintf("%s has published nothing since %s (%d days)", name, health.LastArticle, health.DaysSinceLastArticle)
+			}
+		}
+		views = append(views, view)
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].Class != views[j].Class {
+			return sourceHealthOrder[views[i].Class] < sourceHealthOrder[views[j].Class]
+		}
+		return views[i].Name < views[j].Name
+	})
+	return views
+}
+
+// PrepareGoals formats the snapshot's evaluated goals for the goals section
+func PrepareGoals(m schema.Metrics) []schema.GoalView {
+	views := make([]schema.GoalView, 0, len(m.Goals))
+	for _, goal := range m.Goals {
+		view := schema.GoalView{GoalResult: goal}
+
+		switch goal.Kind {
+		case metrics.GoalMonthlyReads:
+			view.Summary = fmt.Sprintf("%.0f of %.0f read this month", goal.Current, goal.Target)
+		case metrics.GoalMaxUnreadOlderThan:
+			view.Summary = fmt.Sprintf("%.0f unread, target below %.0f", goal.Current, goal.Target)
+		case metrics.GoalReadRate:
+			view.Summary = fmt.Sprintf("%.1f%% read, target %.0f%%", goal.Current, goal.Target)
+		default:
+			view.Summary = fmt.Sprintf("%.1f, target %.1f", goal.Current, goal.Target)
+		}
+
+		if goal.Streak > 0 {
+			unit := "snapshot"
+			if goal.StreakUnit == metrics.StreakMonths {
+				unit = "month"
+			}
+			if goal.Streak > 1 {
+				unit += "s"
+			}
+			view.StreakText = fmt.Sprintf("🔥 %d %s in a row", goal.Streak, unit)
+		}
+
+		views = append(views, view)
+	}
+	return views
+}
+
+// SourceSlug turns a source name into a file-safe slug: lowercase letters and digits joined by hyphens
+func SourceSlug(name string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			pendingHyphen = false
+		} else {
+			pendingHyphen = true
+		}
+	}
+	if b.Len() == 0 {
+		return "source"
+	}
+	return b.String()
+}
+
+// SourceSlugs assigns every source in the snapshot a unique page slug. Names are taken in sorted
+// order and a name whose slug is already used gets the next free numeric suffix, so "C Weekly"
+// and "C++ Weekly" become c-weekly and c-weekly-2, and names without ASCII letters or digits
+// become source, source-2 and so on.
+func SourceSlugs(m schema.Metrics) map[string]string {
+	// Year pages also list sources that only appear in the per-month breakdown
+	seen := make(map[string]bool, len(m.BySource))
+	for name := range m.BySource {
+		seen[name] = true
+	}
+	for name := range m.BySourceAndMonth {
+		seen[name] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	slugs := make(map[string]string, len(names))
+	used := make(map[string]bool, len(names))
+	for _, name := range names {
+		base := SourceSlug(name)
+		slug := base
+		for n := 2; used[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[slug] = true
+		slugs[name] = slug
+	}
+	return slugs
+}
+
+// sourceSlugFor returns the unique slug of a source, or its plain slug when it is not in slugs
+func sourceSlugFor(slugs map[string]string, name string) string {
+	if slug, ok := slugs[name]; ok {
+		return slug
+	}
+	return SourceSlug(name)
+}
+
+// BuildSourceReadRateHistory collects each source's read rate from every snapshot, oldest first
+func BuildSourceReadRateHistory(snapshots []schema.Metrics) map[string][]ReadRatePoint {
+	history := make(map[string][]ReadRatePoint)
+	for _, snapshot := range snapshots {
+		date := snapshot.LastUpdated.Format("2006-01-02")
+		for name, counts := range snapshot.BySourceReadStatus {
+			total := counts[0] + counts[1]
+			if name == "substack_author_count" || total == 0 {
+				continue
+			}
+			rate := math.Round(float64(counts[0])/float64(total)*1000) / 10
+			history[name] = append(history[name], ReadRatePoint{Date: date, ReadRate: rate})
+		}
+	}
+
+	for name := range history {
+		points := history[name]
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Date < points[j].Date
+		})
+	}
+	return history
+}
+
+// PrepareSourcePage builds the charts and backlog for one source's page
+func PrepareSourcePage(m schema.Metrics, source schema.SourceInfo, history []ReadRatePoint) SourcePage {
+	page := SourcePage{
+		Name:    source.Name,
+		Slug:    sourceSlugFor(SourceSlugs(m), source.Name),
+		Color:   source.Color,
+		Added:   m.SourceMetadata[source.Name].Added,
+		Retired: source.Retired,
+		Total:   source.Count,
+		Read:    source.Read,
+		Unread:  source.Unread,
+		ReadPct: source.ReadPct,
+		Backlog: m.SourceBacklog[source.Name],
+	}
+	if page.Color == "" {
+		page.Color = "#0369a1"
+	}
+	if health, exists := m.SourceHealth[source.Name]; exists {
+		page.Health = &health
+	}
+
+	// Month and year series, oldest first
+	byMonth := m.BySourceAndMonth[source.Name]
+	months := make([]string, 0, len(byMonth))
+	byYear := make(map[string][2]int)
+	for month, counts := range byMonth {
+		months = append(months, month)
+		year := month[:4]
+		status := byYear[year]
+		byYear[year] = [2]int{status[0] + counts[0], status[1] + counts[1]}
+	}
+	sort.Strings(months)
+
+	years := make([]string, 0, len(byYear))
+	for year := range byYear {
+		years = append(years, year)
+	}
+	sort.Strings(years)
+
+	page.YearChartJSON = readUnreadSeriesJSON(years, byYear)
+	page.MonthChartJSON = readUnreadSeriesJSON(months, byMonth)
+
+	labels := make([]string, 0, len(history))
+	rates := make([]float64, 0, len(history))
+	for _, point := range history {
+		labels = append(labels, point.Date)
+		rates = append(rates, point.ReadRate)
+	}
+	rateJSON, _ := json.Marshal(map[string]interface{}{"labels": labels, "data": rates})
+	page.ReadRateHistoryJSON = template.JS(rateJSON)
+
+	return page
+}
+
+// readUnreadSeriesJSON creates {labels, readData, unreadData} chart JSON for the given keys
+func readUnreadSeriesJSON(keys []string, counts map[string][2]int) template.JS {
+	readData := make([]int, 0, len(keys))
+	unreadData := make([]int, 0, len(keys))
+	for _, key := range keys {
+		readData = append(readData, counts[key][0])
+		unreadData = append(unreadData, counts[key][1])
+	}
+
+	data := map[string]interface{}{
+		"labels":     keys,
+		"readData":   readData,
+		"unreadData": unreadData,
+	}
+	jsonData, _ := json.Marshal(data)
+	return template.JS(jsonData)
+}
+
+// yearTopSourcesCount is how many most-read sources a year page lists
+const yearTopSourcesCount = 5
+
+// PrepareYearPage builds the charts, rankings and summary for one publication year. The read/unread
+// split per month and the source mix come from by_source_and_month, so snapshots without it only
+// chart monthly volume.
+func PrepareYearPage(m schema.Metrics, year string, colors map[string]string) YearPage {
+	page := YearPage{
+		Year:    year,
+		Total:   m.ByYear[year],
+		Unread:  m.UnreadByYear[year],
+		Backlog: m.YearBacklog[year],
+	}
+	page.Read = page.Total - page.Unread
+	if page.Total > 0 {
+		page.ReadPct = float64(page.Read) / float64(page.Total) * 100
+	}
+
+	// Monthly volume, with the read/unread split when the snapshot has it
+	totalData := make([]int, 12)
+	monthStatus := make([][2]int, 12)
+	bySource := make(map[string][2]int)
+	for month := 1; month <= 12; month++ {
+		key := fmt.Sprintf("%02d", month)
+		totalData[month-1] = m.ByYearAndMonth[year][key]
+		if totalData[month-1] > page.BusiestMonthCount {
+			page.BusiestMonthCount = totalData[month-1]
+			page.BusiestMonth = time.Month(month).String()
+		}
+
+		for source, months := range m.BySourceAndMonth {
+			counts, exists := months[year+"-"+key]
+			if !exists {
+				continue
+			}
+			monthStatus[month-1] = [2]int{monthStatus[month-1][0] + counts[0], monthStatus[month-1][1] + counts[1]}
+			status := bySource[source]
+			bySource[source] = [2]int{status[0] + counts[0], status[1] + counts[1]}
+		}
+	}
+
+	monthData := map[string]interface{}{
+		"labels":     shortMonthNames,
+		"totalData":  totalData,
+		"readData":   nil,
+		"unreadData": nil,
+	}
+	if len(bySource) > 0 {
+		readData := make([]int, 12)
+		unreadData := make([]int, 12)
+		for i, status := range monthStatus {
+			readData[i], unreadData[i] = status[0], status[1]
+		}
+		monthData["readData"] = readData
+		monthData["unreadData"] = unreadData
+	}
+	monthJSON, _ := json.Marshal(monthData)
+	page.MonthChartJSON = template.JS(monthJSON)
+
+	// Source mix by article count, and the most-read sources
+	slugs := SourceSlugs(m)
+	sources := make([]YearSource, 0, len(bySource))
+	for name, status := range bySource {
+		source := YearSource{Name: name, Slug: sourceSlugFor(slugs, name), Read: status[0], Total: status[0] + status[1]}
+		if source.Total > 0 {
+			source.ReadPct = float64(source.Read) / float64(source.Total) * 100
+		}
+		sources = append(sources, source)
+	}
+	page.SourceCount = len(sources)
+
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Total != sources[j].Total {
+			return sources[i].Total > sources[j].Total
+		}
+		return sources[i].Name < sources[j].Name
+	})
+	labels := make([]string, 0, len(sources))
+	data := make([]int, 0, len(sources))
+	sourceColors := make([]string, 0, len(sources))
+	for _, source := range sources {
+		labels = append(labels, source.Name)
+		data = append(data, source.Total)
+		color := colors[source.Name]
+		if color == "" {
+			color = "#0369a1"
+		}
+		sourceColors = append(sourceColors, color)
+	}
+	mixJSON, _ := json.Marshal(map[string]interface{}{"labels": labels, "data": data, "colors": sourceColors})
+	page.SourceMixJSON = template.JS(mixJSON)
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Read > sources[j].Read
+	})
+	for _, source := range sources {
+		if source.Read == 0 || len(page.TopSources) == yearTopSourcesCount {
+			break
+		}
+		page.TopSources = append(page.TopSources, source)
+	}
+
+	summary := fmt.Sprintf("%s in reading: %d articles saved, %d read (%.1f%%).", year, page.Total, page.Read, page.ReadPct)
+	if len(page.TopSources) > 0 {
+		summary += fmt.Sprintf(" Most read source: %s (%d read).", page.TopSources[0].Name, page.TopSources[0].Read)
+	}
+	if page.BusiestMonth != "" {
+		summary += fmt.Sprintf(" Busiest month: %s (%d articles).", page.BusiestMonth, page.BusiestMonthCount)
+	}
+	page.Summary = summary
+
+	return page
+}
+
+// maxForecastWeeks caps how many weeks the backlog projection line extends
+const maxForecastWeeks = 104
+
+// PrepareBacklogForecast creates JSON data for the backlog burn-down chart: observed unread counts
+// followed by a weekly projection along the trend until the backlog clears, the target date passes
+// or maxForecastWeeks is reached. Returns an empty value when the snapshot has no forecast.
+func PrepareBacklogForecast(metrics schema.Metrics) template.JS {
+	forecast := metrics.BacklogForecast
+	if forecast == nil || len(forecast.History) == 0 {
+		return ""
+	}
+
+	labels := make([]string, 0, len(forecast.History))
+	actual := make([]interface{}, 0, len(forecast.History))
+	projected := make([]interface{}, 0, len(forecast.History))
+	for _, point := range forecast.History {
+		labels = append(labels, point.Date)
+		actual = append(actual, point.Unread)
+		projected = append(projected, nil)
+	}
+
+	// The projection starts at the last observed point so the two lines join
+	last := forecast.History[len(forecast.History)-1]
+	projected[len(projected)-1] = last.Unread
+
+	lastDate, err := time.Parse("2006-01-02", last.Date)
+	if err == nil {
+		targetDate, _ := time.Parse("2006-01-02", forecast.TargetDate)
+		for week := 1; week <= maxForecastWeeks; week++ {
+			date := lastDate.AddDate(0, 0, 7*week)
+			unread := math.Max(0, float64(last.Unread)+forecast.TrendWeeklyChange*float64(week))
+
+			labels = append(labels, date.Format("2006-01-02"))
+			actual = append(actual, nil)
+			projected = append(projected, math.Round(unread))
+
+			if unread == 0 || (!targetDate.IsZero() && !date.Before(targetDate)) {
+				break
+			}
+		}
+	}
+
+	data := map[string]interface{}{
+		"labels":    labels,
+		"actual":    actual,
+		"projected": projected,
+	}
+	jsonData, _ := json.Marshal(data)
+	return template.JS(jsonData)
+}
+
+// substackAuthorCount returns the number of Substack authors for the "per author" average. The
+// publications actually seen in article links win over the providers sheet count; articles that
+// could not be attributed to a publication are not an author of their own.
+func substackAuthorCount(m schema.Metrics, publications []schema.PublicationInfo) int {
+	known := 0
+	for _, publication := range publications {
+		if publication.Name != metrics.UnknownPublication {
+			known++
+		}
+	}
+	if known > 0 {
+		return known
+	}
+	return m.BySourceReadStatus["substack_author_count"][0]
+}
+
+// PrepareSubstackPublications builds the per-publication breakdown for the Substack source card, sorted by count
+func PrepareSubstackPublications(metrics schema.Metrics) []schema.PublicationInfo {
+	publications := make([]schema.PublicationInfo, 0, len(metrics.BySubstackPublication))
+	for name, counts := range metrics.BySubstackPublication {
+		total := counts[0] + counts[1]
+		readPct := 0.0
+		if total > 0 {
+			readPct = (float64(counts[0]) / float64(total)) * 100
+		}
+		publications = append(publications, schema.PublicationInfo{
+			Name:    name,
+			Count:   total,
+			Read:    counts[0],
+			Unread:  counts[1],
+			ReadPct: readPct,
+			Backlog: metrics.SubstackBacklog[name],
+		})
+	}
+
+	sort.Slice(publications, func(i, j int) bool {
+		if publications[i].Count != publications[j].Count {
+			return publications[i].Count > publications[j].Count
+		}
+		return publications[i].Name < publications[j].Name
+	})
+
+	return publications
+}
+
 // PrepareUnreadByYear creates JSON data for unread articles by year chart
 func PrepareUnreadByYear(metrics schema.Metrics) template.JS {
 	// Get sorted years in descending order (latest first)
@@ -876,21 +1469,208 @@ func PrepareUnreadByYear(metrics schema.Metrics) template.JS {
 	return template.JS(jsonData)
 }
 
+// ==============================================================================
+// FEED HELPERS
+// ==============================================================================
+
+// PrepareFeedEntry captures the headline numbers and AI analysis of one snapshot for the feeds.
+// An analysis generated after the snapshot moves the entry's updated time forward.
+func PrepareFeedEntry(date string, m schema.Metrics) FeedEntry {
+	published := m.LastUpdated
+	if published.IsZero() {
+		published, _ = time.Parse("2006-01-02", date)
+	}
+	updated := published
+	if m.AIDeltaAnalysis != "" && m.AIDeltaAnalysisGeneratedAt.After(updated) {
+		updated = m.AIDeltaAnalysisGeneratedAt
+	}
+	return FeedEntry{
+		Date:       date,
+		Published:  published.UTC(),
+		Updated:    updated.UTC(),
+		KeyMetrics: PrepareKeyMetrics(m),
+		Analysis:   strings.TrimSpace(m.AIDeltaAnalysis),
+	}
+}
+
+// feedEntryText renders an entry's key metrics as "Title: Value" pairs
+func feedEntryText(entry FeedEntry) string {
+	parts := make([]string, 0, len(entry.KeyMetrics))
+	for _, metric := range entry.KeyMetrics {
+		parts = append(parts, metric.Title+": "+metric.Value)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// feedEntryHTML renders an entry's key metrics and analysis as escaped HTML
+func feedEntryHTML(entry FeedEntry) string {
+	var b strings.Builder
+	b.WriteString("<ul>")
+	for _, metric := range entry.KeyMetrics {
+		fmt.Fprintf(&b, "<li><strong>%s:</strong> %s</li>", template.HTMLEscapeString(metric.Title), template.HTMLEscapeString(metric.Value))
+	}
+	b.WriteString("</ul>")
+	if entry.Analysis != "" {
+		fmt.Fprintf(&b, "<p>%s</p>", template.HTMLEscapeString(entry.Analysis))
+	}
+	return b.String()
+}
+
+// GenerateFeeds writes feed.xml (Atom) and feed.json (JSON Feed) to the output directory, one entry
+// per snapshot (newest first) linking to its archived analytics page. Feed and entry ids must be
+// absolute URLs, so nothing is written without an absolute site_url in landing.yml.
+func (s *AnalyticsService) GenerateFeeds(entries []FeedEntry) error {
+	landing, err := LoadLanding()
+	if err != nil {
+		return fmt.Errorf("feeds need the site URL from landing content: %w", err)
+	}
+	siteURL := strings.TrimSuffix(strings.TrimSpace(landing.Header.SiteURL), "/")
+	if parsed, err := url.Parse(siteURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("feeds need an absolute http(s) site_url in landing.yml, got %q", landing.Header.SiteURL)
+	}
+	title := landing.Header.ProjectName
+	if title == "" {
+		title = strings.TrimSpace(strings.TrimPrefix(AnalyticsTitle, "📚"))
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date > entries[j].Date
+	})
+
+	atom := AtomFeed{
+		Title: title,
+		ID:    siteURL + "/feed.xml",
+		Links: []AtomLink{
+			{Href: siteURL + "/feed.xml", Rel: "self", Type: "application/atom+xml"},
+			{Href: siteURL + "/analytics.html", Rel: "alternate", Type: "text/html"},
+		},
+		Author: AtomAuthor{Name: landing.Footer.Author},
+	}
+	jsonFeed := JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		HomePageURL: siteURL + "/analytics.html",
+		FeedURL:     siteURL + "/feed.json",
+		Items:       []JSONFeedItem{},
+	}
+	if landing.Footer.Author != "" {
+		jsonFeed.Authors = []JSONFeedAuthor{{Name: landing.Footer.Author}}
+	}
+
+	// The feed was last updated by whichever entry changed last, which may be an older
+	// snapshot whose analysis was regenerated
+	var feedUpdated time.Time
+	for _, entry := range entries {
+		pageURL := siteURL + "/history/" + entry.Date + "/analytics.html"
+		entryTitle := "Reading report for " + entry.Date
+		summary := feedEntryText(entry)
+		contentHTML := feedEntryHTML(entry)
+		published := entry.Published.Format(time.RFC3339)
+		updated := entry.Updated.Format(time.RFC3339)
+		if entry.Updated.After(feedUpdated) {
+			feedUpdated = entry.Updated
+		}
+
+		atom.Entries = append(atom.Entries, AtomEntry{
+			Title:     entryTitle,
+			ID:        pageURL,
+			Published: published,
+			Updated:   updated,
+			Link:      AtomLink{Href: pageURL, Rel: "alternate", Type: "text/html"},
+			Summary:   summary,
+			Content:   AtomContent{Type: "html", Body: contentHTML},
+		})
+
+		contentText := summary
+		if entry.Analysis != "" {
+			contentText += "\n\n" + entry.Analysis
+		}
+		jsonFeed.Items = append(jsonFeed.Items, JSONFeedItem{
+			ID:            pageURL,
+			URL:           pageURL,
+			Title:         entryTitle,
+			Summary:       summary,
+			ContentHTML:   contentHTML,
+			ContentText:   contentText,
+			DatePublished: published,
+			DateModified:  updated,
+		})
+	}
+	if feedUpdated.IsZero() {
+		feedUpdated = time.Now()
+	}
+	atom.Updated = feedUpdated.UTC().Format(time.RFC3339)
+
+	if err := os.MkdirAll(s.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	atomXML, err := xml.MarshalIndent(atom, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Atom feed: %w", err)
+	}
+	atomXML = append([]byte(xml.Header), atomXML...)
+	if err := os.WriteFile(filepath.Join(s.outputDir, "feed.xml"), atomXML, 0644); err != nil {
+		return fmt.Errorf("failed to write feed.xml: %w", err)
+	}
+
+	feedJSON, err := json.MarshalIndent(jsonFeed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON feed: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.outputDir, "feed.json"), feedJSON, 0644); err != nil {
+		return fmt.Errorf("failed to write feed.json: %w", err)
+	}
+
+	return nil
+}
+
 // ==============================================================================
 // METRICS IO HELPERS
 // ==============================================================================
 
-// GetMetricsDates returns all YYYY-MM-DD dates from JSON files in metrics/ folder, sorted descending
-func GetMetricsDates() ([]string, error) {
-	entries, err := os.ReadDir("metrics")
+// referenceTime is "now" for a generation pass: the configured as-of date, else the
+// snapshot's LastUpdated, else the system clock
+func referenceTime(m schema.Metrics, config GenConfig) time.Time {
+	if !config.AsOf.IsZero() {
+		return config.AsOf
+	}
+	if !m.LastUpdated.IsZero() {
+		return m.LastUpdated
+	}
+	return time.Now()
+}
+
+// FilterDatesAsOf keeps snapshot dates (YYYY-MM-DD) on or before asOf; a zero asOf keeps all
+func FilterDatesAsOf(dates []string, asOf time.Time) []string {
+	if asOf.IsZero() {
+		return dates
+	}
+
+	cutoff := asOf.Format("2006-01-02")
+	var filtered []string
+	for _, date := range dates {
+		if date <= cutoff {
+			filtered = append(filtered, date)
+		}
+	}
+	return filtered
+}
+
+// GetMetricsDates returns the YYYY-MM-DD dates of the snapshots in store, sorted descending.
+// A date with several intra-day snapshots is listed once.
+func GetMetricsDates(ctx context.Context, store metrics.SnapshotStore) ([]string, error) {
+	filenames, err := store.List(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read metrics directory: %w", err)
+		return nil, err
 	}
 
 	var dates []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
-			date := strings.TrimSuffix(entry.Name(), ".json")
+	seen := make(map[string]bool)
+	for _, filename := range filenames {
+		date := metrics.SnapshotFileDate(filename)
+		if !seen[date] {
+			seen[date] = true
 			dates = append(dates, date)
 		}
 	}
@@ -903,19 +1683,18 @@ func GetMetricsDates() ([]string, error) {
 	return dates, nil
 }
 
-// LoadMetricsByDate reads a specific metrics JSON file from metrics/ folder
-func LoadMetricsByDate(date string) (schema.Metrics, error) {
-	filename := fmt.Sprintf("metrics/%s.json", date)
-	data, err := os.ReadFile(filename)
+// LoadMetricsByDate reads the snapshot of a date from store; when the date has intra-day
+// snapshots the latest one wins
+func LoadMetricsByDate(ctx context.Context, store metrics.SnapshotStore, date string) (schema.Metrics, error) {
+	filenames, err := store.List(ctx)
 	if err != nil {
-		return schema.Metrics{}, fmt.Errorf("unable to read metrics file %s: %w", filename, err)
+		return schema.Metrics{}, err
 	}
 
-	var metrics schema.Metrics
-	err = json.Unmarshal(data, &metrics)
-	if err != nil {
-		return schema.Metrics{}, fmt.Errorf("unable to parse metrics JSON from %s: %w", filename, err)
+	for i := len(filenames) - 1; i >= 0; i-- {
+		if metrics.SnapshotFileDate(filenames[i]) == date {
+			return metrics.LoadSnapshotWithAnalysis(ctx, store, filenames[i])
+		}
 	}
-
-	return metrics, nil
+	return schema.Metrics{}, fmt.Errorf("no metrics snapshot dated %s in %s", date, store.Location(""))
 }
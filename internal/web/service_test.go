@@ -1,13 +1,21 @@
 package web
 
 import (
+	"context"
 	"encoding/json"
+	"encoding/xml"
 	"html/template"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	schema "github.com/victoriacheng15/personal-reading-analytics/internal"
+	"github.com/victoriacheng15/personal-reading-analytics/internal/metrics"
 )
 
 func TestAnalyticsService_Generate(t *testing.T) {
@@ -64,12 +72,16 @@ func TestAnalyticsService_Generate(t *testing.T) {
 			indexTmpl := `{{define "content"}}<h1>Home</h1>{{end}}{{template "base" .}}`
 			webTmpl := `{{define "content"}}<h1>Analytics</h1>{{end}}{{template "base" .}}`
 			evolutionTmpl := `{{define "content"}}<h1>Evolution</h1>{{end}}{{template "base" .}}`
+			sourceTmpl := `{{define "content"}}<h1>{{.Source.Name}}</h1>{{end}}{{template "base" .}}`
+			yearTmpl := `{{define "content"}}<h1>{{.Year.Year}}</h1>{{end}}{{template "base" .}}`
 
 			templates := map[string]string{
 				"base.html":      baseTmpl,
 				"index.html":     indexTmpl,
 				"analytics.html": webTmpl,
 				"evolution.html": evolutionTmpl,
+				"source.html":    sourceTmpl,
+				"year.html":      yearTmpl,
 			}
 
 			for name, content := range templates {
@@ -134,6 +146,15 @@ engineering_principles_section:
 			if _, err := os.Stat("dist/index.html"); os.IsNotExist(err) {
 				t.Error("dist/index.html was not created")
 			}
+			if _, err := os.Stat("dist/sources/sourcea.html"); os.IsNotExist(err) {
+				t.Error("dist/sources/sourcea.html was not created")
+			}
+			if _, err := os.Stat("dist/years/2024.html"); os.IsNotExist(err) {
+				t.Error("dist/years/2024.html was not created")
+			}
+			if _, err := os.Stat("dist/metrics.txt"); os.IsNotExist(err) {
+				t.Error("dist/metrics.txt was not created")
+			}
 
 			// Test Analytics Only Generation
 			config.IsHistorical = true
@@ -1277,6 +1298,32 @@ func TestPrepareUnreadArticleAgeDistribution(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "snapshot declares its own buckets",
+			metrics: &schema.Metrics{
+				UnreadArticleAgeDistribution: map[string]int{"recent": 4, "older_than_2_years": 7, "older_than_1year": 99},
+				AgeBuckets: []schema.AgeBucket{
+					{Key: "recent", Label: "Recent"},
+					{Key: "older_than_2_years", Label: "Older than 2 years", MinAge: "2y"},
+				},
+			},
+			validate: func(t *testing.T, jsonStr template.JS) {
+				var chartData struct {
+					Labels []string `json:"labels"`
+					Data   []int    `json:"data"`
+				}
+				if err := json.Unmarshal([]byte(jsonStr), &chartData); err != nil {
+					t.Fatalf("failed to unmarshal JSON: %v", err)
+				}
+
+				if !reflect.DeepEqual(chartData.Labels, []string{"Recent", "Older than 2 years"}) {
+					t.Errorf("unexpected labels: %v", chartData.Labels)
+				}
+				if !reflect.DeepEqual(chartData.Data, []int{4, 7}) {
+					t.Errorf("unexpected data: %v", chartData.Data)
+				}
+			},
+		},
 		{
 			name: "empty age distribution",
 			metrics: &schema.Metrics{
@@ -1554,6 +1601,40 @@ func TestPrepareUnreadByYearDataValidity(t *testing.T) {
 // METRICS IO TEST SUITE
 // ==============================================================================
 
+func TestFilterDatesAsOf(t *testing.T) {
+	dates := []string{"2026-01-15", "2026-01-08", "2026-01-01"}
+
+	tests := []struct {
+		name     string
+		asOf     time.Time
+		expected []string
+	}{
+		{"zero keeps all", time.Time{}, dates},
+		{"inclusive cutoff", time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC), []string{"2026-01-08", "2026-01-01"}},
+		{"before every snapshot", time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := FilterDatesAsOf(dates, tt.asOf); !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("FilterDatesAsOf() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReferenceTime(t *testing.T) {
+	lastUpdated := time.Date(2025, 11, 28, 9, 0, 0, 0, time.UTC)
+	asOf := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	if result := referenceTime(schema.Metrics{LastUpdated: lastUpdated}, GenConfig{AsOf: asOf}); !result.Equal(asOf) {
+		t.Errorf("expected as-of date to win, got %v", result)
+	}
+	if result := referenceTime(schema.Metrics{LastUpdated: lastUpdated}, GenConfig{}); !result.Equal(lastUpdated) {
+		t.Errorf("expected snapshot date without as-of, got %v", result)
+	}
+}
+
 func TestGetMetricsDates(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -1567,6 +1648,18 @@ func TestGetMetricsDates(t *testing.T) {
 			expectedDates: []string{"2025-01-01", "2024-01-01"},
 			expectError:   false,
 		},
+		{
+			name:          "ignores quality reports",
+			fileNames:     []string{"2025-01-01.json", "2025-01-01.quality.json"},
+			expectedDates: []string{"2025-01-01"},
+			expectError:   false,
+		},
+		{
+			name:          "lists a date with intra-day snapshots once",
+			fileNames:     []string{"2025-01-01.json", "2025-01-01T093000.json", "2025-01-01T183000.json", "2025-01-02T080000.json"},
+			expectedDates: []string{"2025-01-02", "2025-01-01"},
+			expectError:   false,
+		},
 		{
 			name:          "no valid metrics files",
 			fileNames:     []string{"not-a-date.txt"},
@@ -1600,7 +1693,7 @@ func TestGetMetricsDates(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			dates, err := GetMetricsDates()
+			dates, err := GetMetricsDates(context.Background(), metrics.NewFileStore("metrics"))
 			if (err != nil) != tt.expectError {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -1622,21 +1715,61 @@ func TestLoadMetricsByDate(t *testing.T) {
 	tests := []struct {
 		name             string
 		date             string
-		fileContent      string
+		files            map[string]string
+		archived         map[string]string
+		analysis         map[string]string
 		expectedArticles int
+		expectedAnalysis string
 		expectError      bool
 	}{
 		{
 			name:             "loads metrics for specific date",
 			date:             "2025-01-01",
-			fileContent:      `{"total_articles": 100}`,
+			files:            map[string]string{"2025-01-01.json": `{"total_articles": 100}`},
 			expectedArticles: 100,
 			expectError:      false,
 		},
+		{
+			name: "latest intra-day snapshot wins",
+			date: "2025-01-01",
+			files: map[string]string{
+				"2025-01-01.json":        `{"total_articles": 100}`,
+				"2025-01-01T183000.json": `{"total_articles": 102}`,
+				"2025-01-02.json":        `{"total_articles": 105}`,
+			},
+			expectedArticles: 102,
+			expectError:      false,
+		},
+		{
+			name: "reads compacted snapshots",
+			date: "2025-01-01",
+			archived: map[string]string{
+				"2024-12-25.json": `{"total_articles": 95}`,
+				"2025-01-01.json": `{"total_articles": 100}`,
+			},
+			expectedArticles: 100,
+			expectError:      false,
+		},
+		{
+			name:             "loose snapshot wins over its archived copy",
+			date:             "2025-01-01",
+			files:            map[string]string{"2025-01-01.json": `{"total_articles": 101}`},
+			archived:         map[string]string{"2025-01-01.json": `{"total_articles": 100}`},
+			expectedArticles: 101,
+			expectError:      false,
+		},
+		{
+			name:             "merges the analysis sidecar",
+			date:             "2025-01-01",
+			files:            map[string]string{"2025-01-01.json": `{"total_articles": 100, "ai_delta_analysis": "inline"}`},
+			analysis:         map[string]string{"2025-01-01.json": "From the sidecar."},
+			expectedArticles: 100,
+			expectedAnalysis: "From the sidecar.",
+			expectError:      false,
+		},
 		{
 			name:             "non-existent date",
 			date:             "2000-01-01",
-			fileContent:      "",
 			expectedArticles: 0,
 			expectError:      true,
 		},
@@ -1655,8 +1788,27 @@ func TestLoadMetricsByDate(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			if tt.fileContent != "" {
-				if err := os.WriteFile(filepath.Join(metricsDir, tt.date+".json"), []byte(tt.fileContent), 0644); err != nil {
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(metricsDir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(tt.archived) > 0 {
+				var snapshots []metrics.ArchivedSnapshot
+				for name, content := range tt.archived {
+					snapshots = append(snapshots, metrics.ArchivedSnapshot{Name: name, Content: []byte(content)})
+				}
+				archive, err := metrics.EncodeArchive(snapshots)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(metricsDir, metrics.ArchiveFilename), archive, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for name, analysis := range tt.analysis {
+				if err := metrics.SaveAnalysis(context.Background(), metrics.NewFileStore(metricsDir), name, analysis); err != nil {
 					t.Fatal(err)
 				}
 			}
@@ -1667,13 +1819,626 @@ func TestLoadMetricsByDate(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			metrics, err := LoadMetricsByDate(tt.date)
+			snapshot, err := LoadMetricsByDate(context.Background(), metrics.NewFileStore("metrics"), tt.date)
 			if (err != nil) != tt.expectError {
 				t.Errorf("unexpected error: %v", err)
 			}
-			if metrics.TotalArticles != tt.expectedArticles {
-				t.Errorf("expected %d articles, got %d", tt.expectedArticles, metrics.TotalArticles)
+			if snapshot.TotalArticles != tt.expectedArticles {
+				t.Errorf("expected %d articles, got %d", tt.expectedArticles, snapshot.TotalArticles)
+			}
+			if tt.expectedAnalysis != "" && snapshot.AIDeltaAnalysis != tt.expectedAnalysis {
+				t.Errorf("expected analysis %q, got %q", tt.expectedAnalysis, snapshot.AIDeltaAnalysis)
+			}
+		})
+	}
+}
+
+func TestPrepareSubstackPublications(t *testing.T) {
+	tests := []struct {
+		name     string
+		metrics  schema.Metrics
+		expected []schema.PublicationInfo
+	}{
+		{
+			name: "sorted by count with read rate",
+			metrics: schema.Metrics{
+				BySubstackPublication: map[string][2]int{
+					"alpha": {1, 3},
+					"beta":  {6, 2},
+					"gamma": {2, 2},
+				},
+				SubstackBacklog: map[string][]schema.ArticleMeta{
+					"alpha": {{Title: "Oldest", Date: "2024-01-02", Category: "Substack"}},
+				},
+			},
+			expected: []schema.PublicationInfo{
+				{Name: "beta", Count: 8, Read: 6, Unread: 2, ReadPct: 75},
+				{Name: "alpha", Count: 4, Read: 1, Unread: 3, ReadPct: 25, Backlog: []schema.ArticleMeta{{Title: "Oldest", Date: "2024-01-02", Category: "Substack"}}},
+				{Name: "gamma", Count: 4, Read: 2, Unread: 2, ReadPct: 50},
+			},
+		},
+		{
+			name:     "no publications",
+			metrics:  schema.Metrics{},
+			expected: []schema.PublicationInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PrepareSubstackPublications(tt.metrics)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("expected %d publications, got %d", len(tt.expected), len(result))
+			}
+			for i := range result {
+				if !reflect.DeepEqual(result[i], tt.expected[i]) {
+					t.Errorf("publication %d = %+v, want %+v", i, result[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSubstackAuthorCount(t *testing.T) {
+	sheetCount := schema.Metrics{BySourceReadStatus: map[string][2]int{"substack_author_count": {7, 0}}}
+
+	tests := []struct {
+		name         string
+		publications []schema.PublicationInfo
+		expected     int
+	}{
+		{"publications from links", []schema.PublicationInfo{{Name: "alpha"}, {Name: "beta"}}, 2},
+		{"unattributed articles are not an author", []schema.PublicationInfo{{Name: "alpha"}, {Name: "unknown"}}, 1},
+		{"only unattributed articles fall back to the sheet", []schema.PublicationInfo{{Name: "unknown"}}, 7},
+		{"no publications fall back to the sheet", nil, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := substackAuthorCount(sheetCount, tt.publications); got != tt.expected {
+				t.Errorf("expected %d authors, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+// ============================================================================
+// PrepareBacklogForecast: Builds the observed and projected backlog series
+// ============================================================================
+
+func TestPrepareBacklogForecast(t *testing.T) {
+	history := []schema.BacklogPoint{
+		{Date: "2026-01-02", Unread: 40},
+		{Date: "2026-01-09", Unread: 30},
+		{Date: "2026-01-16", Unread: 20},
+	}
+
+	tests := []struct {
+		name              string
+		forecast          *schema.BacklogForecast
+		expectedLabels    int
+		expectedLastLabel string
+		expectedLastValue float64
+	}{
+		{
+			name:              "shrinking backlog projects to zero",
+			forecast:          &schema.BacklogForecast{TargetDate: "2026-12-31", TrendWeeklyChange: -10, History: history},
+			expectedLabels:    5,
+			expectedLastLabel: "2026-01-30",
+			expectedLastValue: 0,
+		},
+		{
+			name:              "growing backlog stops at the target date",
+			forecast:          &schema.BacklogForecast{TargetDate: "2026-02-06", TrendWeeklyChange: 2, History: history},
+			expectedLabels:    6,
+			expectedLastLabel: "2026-02-06",
+			expectedLastValue: 26,
+		},
+		{
+			name:              "flat backlog is capped",
+			forecast:          &schema.BacklogForecast{TargetDate: "2030-12-31", History: history},
+			expectedLabels:    3 + maxForecastWeeks,
+			expectedLastLabel: "2028-01-14",
+			expectedLastValue: 20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonStr := PrepareBacklogForecast(schema.Metrics{BacklogForecast: tt.forecast})
+
+			var chartData struct {
+				Labels    []string   `json:"labels"`
+				Actual    []*float64 `json:"actual"`
+				Projected []*float64 `json:"projected"`
+			}
+			if err := json.Unmarshal([]byte(jsonStr), &chartData); err != nil {
+				t.Fatalf("JSON unmarshaling failed: %v", err)
+			}
+
+			if len(chartData.Labels) != tt.expectedLabels {
+				t.Fatalf("expected %d labels, got %d", tt.expectedLabels, len(chartData.Labels))
+			}
+			if last := chartData.Labels[len(chartData.Labels)-1]; last != tt.expectedLastLabel {
+				t.Errorf("expected last label %s, got %s", tt.expectedLastLabel, last)
+			}
+			if last := chartData.Projected[len(chartData.Projected)-1]; last == nil || *last != tt.expectedLastValue {
+				t.Errorf("expected last projection %.0f, got %v", tt.expectedLastValue, last)
+			}
+
+			// Observed points end where the projection begins
+			if chartData.Actual[2] == nil || chartData.Projected[2] == nil || *chartData.Projected[2] != 20 {
+				t.Error("expected projection to start at the last observed point")
+			}
+			if chartData.Projected[0] != nil || chartData.Actual[3] != nil {
+				t.Error("expected observed and projected series not to overlap")
+			}
+		})
+	}
+
+	t.Run("no forecast", func(t *testing.T) {
+		if jsonStr := PrepareBacklogForecast(schema.Metrics{}); jsonStr != "" {
+			t.Errorf("expected empty chart data, got %s", jsonStr)
+		}
+	})
+}
+
+// ============================================================================
+// PrepareGoals: Formats evaluated goals for the goals section
+// ============================================================================
+
+func TestPrepareGoals(t *testing.T) {
+	m := schema.Metrics{Goals: []schema.GoalResult{
+		{ID: "monthly", Kind: "monthly_reads", Target: 40, Current: 32, Streak: 3, StreakUnit: "months"},
+		{ID: "old", Kind: "max_unread_older_than", Target: 200, Current: 180, Met: true, Streak: 1, StreakUnit: "snapshots"},
+		{ID: "substack", Kind: "read_rate", Target: 60, Current: 55.25},
+	}}
+
+	views := PrepareGoals(m)
+	expected := []struct {
+		summary string
+		streak  string
+	}{
+		{"32 of 40 read this month", "🔥 3 months in a row"},
+		{"180 unread, target below 200", "🔥 1 snapshot in a row"},
+		{"55.2% read, target 60%", ""},
+	}
+
+	if len(views) != len(expected) {
+		t.Fatalf("expected %d views, got %d", len(expected), len(views))
+	}
+	for i, want := range expected {
+		if views[i].Summary != want.summary || views[i].StreakText != want.streak {
+			t.Errorf("goal %d: expected %q / %q, got %q / %q", i, want.summary, want.streak, views[i].Summary, views[i].StreakText)
+		}
+	}
+
+	if views := PrepareGoals(schema.Metrics{}); len(views) != 0 {
+		t.Errorf("expected no views without goals, got %d", len(views))
+	}
+}
+
+// ============================================================================
+// PrepareCadenceHighlights: Builds highlight cards from the weekly cadence
+// ============================================================================
+
+func TestPrepareCadenceHighlights(t *testing.T) {
+	cadence := &schema.Cadence{
+		WeeksObserved:     20,
+		CurrentStreak:     3,
+		LongestStreak:     8,
+		ZeroReadWeeks:     2,
+		BestWeek:          "2026-01-16",
+		BestWeekReads:     14,
+		RollingAvgReads:   5.25,
+		RollingWeeks:      4,
+		WeeklyReadsStdDev: 3.14,
+	}
+
+	expected := map[string]string{
+		"🔥 Weekly Streak":      "3 wk (best 8)",
+		"📈 Reads/Week":         "5.2 (last 4 wk)",
+		"🏅 Best Week":          "14 (2026-01-16)",
+		"💤 Zero-Read Weeks":    "2 of 20",
+		"📊 Weekly Variability": "±3.1 reads",
+	}
+
+	highlights := PrepareCadenceHighlights(cadence)
+	if len(highlights) != len(expected) {
+		t.Fatalf("expected %d highlights, got %d", len(expected), len(highlights))
+	}
+	for _, highlight := range highlights {
+		if want, ok := expected[highlight.Title]; !ok || highlight.Value != want {
+			t.Errorf("%s: expected %q, got %q", highlight.Title, want, highlight.Value)
+		}
+	}
+
+	if highlights := PrepareCadenceHighlights(nil); len(highlights) != 0 {
+		t.Errorf("expected no highlights without cadence, got %d", len(highlights))
+	}
+}
+
+// ============================================================================
+// PrepareSourceHealth: Orders the health table and warns about silent sources
+// ============================================================================
+
+func TestPrepareSourceHealth(t *testing.T) {
+	m := schema.Metrics{SourceHealth: map[string]schema.SourceHealth{
+		"Valuable": {Class: "valuable", LastArticle: "2026-03-01"},
+		"Silent":   {Class: "stale", LastArticle: "2025-11-01", DaysSinceLastArticle: 150},
+		"Empty":    {Class: "stale"},
+		"Noisy":    {Class: "noisy", LastArticle: "2026-03-20"},
+	}}
+
+	views := PrepareSourceHealth(m)
+	order := []string{"Empty", "Silent", "Noisy", "Valuable"}
+	if len(views) != len(order) {
+		t.Fatalf("expected %d rows, got %d", len(order), len(views))
+	}
+	for i, name := range order {
+		if views[i].Name != name {
+			t.Errorf("row %d: expected %s, got %s", i, name, views[i].Name)
+		}
+	}
+
+	if views[0].Warning != "Empty has no articles yet" {
+		t.Errorf("unexpected warning: %q", views[0].Warning)
+	}
+	if views[1].Warning != "Silent has published nothing since 2025-11-01 (150 days)" {
+		t.Errorf("unexpected warning: %q", views[1].Warning)
+	}
+	if views[2].Warning != "" || views[3].Warning != "" {
+		t.Error("expected no warnings for active sources")
+	}
+}
+
+// ============================================================================
+// Source pages: Slugs, read rate history and per-source charts
+// ============================================================================
+
+func TestSourceSlug(t *testing.T) {
+	tests := map[string]string{
+		"GitHub":            "github",
+		"Netflix Tech Blog": "netflix-tech-blog",
+		"  freeCodeCamp! ":  "freecodecamp",
+		"C++ / Rust":        "c-rust",
+		"!!!":               "source",
+	}
+	for name, expected := range tests {
+		if got := SourceSlug(name); got != expected {
+			t.Errorf("SourceSlug(%q) = %q, want %q", name, got, expected)
+		}
+	}
+}
+
+func TestSourceSlugs(t *testing.T) {
+	m := schema.Metrics{
+		BySource: map[string]int{"C Weekly": 3, "C++ Weekly": 2, "c-weekly-2": 1, "GitHub": 4, "日本語ブログ": 1, "Блог": 1},
+		BySourceAndMonth: map[string]map[string][2]int{
+			"Retired Feed": {"2024-01": {1, 0}},
+		},
+	}
+
+	expected := map[string]string{
+		"C Weekly":     "c-weekly",
+		"C++ Weekly":   "c-weekly-2",
+		"c-weekly-2":   "c-weekly-2-2",
+		"GitHub":       "github",
+		"Retired Feed": "retired-feed",
+		"Блог":         "source",
+		"日本語ブログ":       "source-2",
+	}
+	slugs := SourceSlugs(m)
+	if !reflect.DeepEqual(slugs, expected) {
+		t.Errorf("SourceSlugs() = %v, want %v", slugs, expected)
+	}
+
+	// Pages and links use the same unique slug
+	if page := PrepareSourcePage(m, schema.SourceInfo{Name: "C++ Weekly"}, nil); page.Slug != "c-weekly-2" {
+		t.Errorf("expected source page slug c-weekly-2, got %q", page.Slug)
+	}
+}
+
+func TestBuildSourceReadRateHistory(t *testing.T) {
+	snapshots := []schema.Metrics{
+		{
+			LastUpdated:        time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC),
+			BySourceReadStatus: map[string][2]int{"GitHub": {3, 1}, "substack_author_count": {5, 0}},
+		},
+		{
+			LastUpdated:        time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			BySourceReadStatus: map[string][2]int{"GitHub": {1, 2}, "Empty": {0, 0}},
+		},
+	}
+
+	history := BuildSourceReadRateHistory(snapshots)
+	expected := []ReadRatePoint{{Date: "2026-01-02", ReadRate: 33.3}, {Date: "2026-01-09", ReadRate: 75}}
+	if !reflect.DeepEqual(history["GitHub"], expected) {
+		t.Errorf("expected %v, got %v", expected, history["GitHub"])
+	}
+	if _, exists := history["substack_author_count"]; exists {
+		t.Error("expected the Substack author count to be skipped")
+	}
+	if _, exists := history["Empty"]; exists {
+		t.Error("expected sources without articles to be skipped")
+	}
+}
+
+func TestPrepareSourcePage(t *testing.T) {
+	m := schema.Metrics{
+		BySourceAndMonth: map[string]map[string][2]int{
+			"GitHub": {"2025-12": {1, 1}, "2025-11": {2, 0}, "2026-01": {0, 3}},
+		},
+		SourceMetadata: map[string]schema.SourceMeta{"GitHub": {Added: "2024-05-01"}},
+		SourceHealth:   map[string]schema.SourceHealth{"GitHub": {Class: "active"}},
+		SourceBacklog:  map[string][]schema.ArticleMeta{"GitHub": {{Title: "Old post", Date: "2025-12-01"}}},
+	}
+	source := schema.SourceInfo{Name: "GitHub", Count: 7, Read: 3, Unread: 4}
+	history := []ReadRatePoint{{Date: "2026-01-02", ReadRate: 40}}
+
+	page := PrepareSourcePage(m, source, history)
+
+	if page.Slug != "github" || page.Added != "2024-05-01" || page.Color != "#0369a1" {
+		t.Errorf("unexpected page header: %+v", page)
+	}
+	if page.Health == nil || page.Health.Class != "active" || len(page.Backlog) != 1 {
+		t.Errorf("expected health and backlog to be attached, got %+v / %v", page.Health, page.Backlog)
+	}
+
+	var years, months struct {
+		Labels     []string `json:"labels"`
+		ReadData   []int    `json:"readData"`
+		UnreadData []int    `json:"unreadData"`
+	}
+	json.Unmarshal([]byte(page.YearChartJSON), &years)
+	json.Unmarshal([]byte(page.MonthChartJSON), &months)
+
+	if !reflect.DeepEqual(years.Labels, []string{"2025", "2026"}) || !reflect.DeepEqual(years.ReadData, []int{3, 0}) || !reflect.DeepEqual(years.UnreadData, []int{1, 3}) {
+		t.Errorf("unexpected yearly series: %+v", years)
+	}
+	if !reflect.DeepEqual(months.Labels, []string{"2025-11", "2025-12", "2026-01"}) {
+		t.Errorf("expected months oldest first, got %v", months.Labels)
+	}
+	if !strings.Contains(string(page.ReadRateHistoryJSON), `"labels":["2026-01-02"]`) {
+		t.Errorf("unexpected read rate history: %s", page.ReadRateHistoryJSON)
+	}
+}
+
+// ============================================================================
+// Year pages: Year in review
+// ============================================================================
+
+func TestPrepareYearPage(t *testing.T) {
+	m := schema.Metrics{
+		ByYear:         map[string]int{"2025": 10, "2024": 2},
+		UnreadByYear:   map[string]int{"2025": 4},
+		ByYearAndMonth: map[string]map[string]int{"2025": {"01": 3, "03": 7}},
+		BySourceAndMonth: map[string]map[string][2]int{
+			"GitHub": {"2025-01": {2, 1}, "2025-03": {1, 0}, "2024-06": {2, 0}},
+			"Stripe": {"2025-03": {3, 3}},
+		},
+		YearBacklog: map[string][]schema.ArticleMeta{"2025": {{Title: "Old post", Date: "2025-01-04"}}},
+	}
+
+	page := PrepareYearPage(m, "2025", map[string]string{"GitHub": "#000"})
+
+	if page.Total != 10 || page.Read != 6 || page.Unread != 4 || page.ReadPct != 60 {
+		t.Errorf("unexpected totals: %+v", page)
+	}
+	if page.BusiestMonth != "March" || page.BusiestMonthCount != 7 || page.SourceCount != 2 || len(page.Backlog) != 1 {
+		t.Errorf("unexpected busiest month, source count or backlog: %+v", page)
+	}
+
+	// Ties on reads keep the larger source first
+	expectedTop := []YearSource{
+		{Name: "Stripe", Slug: "stripe", Read: 3, Total: 6, ReadPct: 50},
+		{Name: "GitHub", Slug: "github", Read: 3, Total: 4, ReadPct: 75},
+	}
+	if !reflect.DeepEqual(page.TopSources, expectedTop) {
+		t.Errorf("expected top sources %v, got %v", expectedTop, page.TopSources)
+	}
+
+	var months struct {
+		TotalData  []int `json:"totalData"`
+		ReadData   []int `json:"readData"`
+		UnreadData []int `json:"unreadData"`
+	}
+	json.Unmarshal([]byte(page.MonthChartJSON), &months)
+	if months.TotalData[2] != 7 || months.ReadData[0] != 2 || months.UnreadData[2] != 3 {
+		t.Errorf("unexpected monthly series: %+v", months)
+	}
+
+	var mix struct {
+		Labels []string `json:"labels"`
+		Colors []string `json:"colors"`
+	}
+	json.Unmarshal([]byte(page.SourceMixJSON), &mix)
+	if !reflect.DeepEqual(mix.Labels, []string{"Stripe", "GitHub"}) || !reflect.DeepEqual(mix.Colors, []string{"#0369a1", "#000"}) {
+		t.Errorf("expected the source mix by count with fallback colors, got %+v", mix)
+	}
+
+	expectedSummary := "2025 in reading: 10 articles saved, 6 read (60.0%). Most read source: Stripe (3 read). Busiest month: March (7 articles)."
+	if page.Summary != expectedSummary {
+		t.Errorf("expected summary %q, got %q", expectedSummary, page.Summary)
+	}
+}
+
+func TestPrepareYearPageWithoutSourceMonths(t *testing.T) {
+	m := schema.Metrics{
+		ByYear:         map[string]int{"2023": 2},
+		ByYearAndMonth: map[string]map[string]int{"2023": {"05": 2}},
+	}
+
+	page := PrepareYearPage(m, "2023", nil)
+
+	if page.SourceCount != 0 || len(page.TopSources) != 0 {
+		t.Errorf("expected no source breakdown, got %+v", page)
+	}
+	if !strings.Contains(string(page.MonthChartJSON), `"readData":null`) {
+		t.Errorf("expected only monthly totals, got %s", page.MonthChartJSON)
+	}
+	if page.Summary != "2023 in reading: 2 articles saved, 2 read (100.0%). Busiest month: May (2 articles)." {
+		t.Errorf("unexpected summary: %q", page.Summary)
+	}
+}
+
+// ============================================================================
+// Feeds: Atom and JSON Feed of snapshot reports
+// ============================================================================
+
+func TestPrepareFeedEntry(t *testing.T) {
+	m := schema.Metrics{TotalArticles: 10, ReadCount: 4, UnreadCount: 6, ReadRate: 40, AIDeltaAnalysis: "  Reading slowed.\n"}
+
+	entry := PrepareFeedEntry("2026-01-09", m)
+
+	if entry.Updated.Format("2006-01-02") != "2026-01-09" || !entry.Published.Equal(entry.Updated) {
+		t.Errorf("expected a missing timestamp to fall back to the snapshot date, got %v", entry.Updated)
+	}
+
+	// An analysis generated after the snapshot updates the entry, not its publication
+	m.LastUpdated = time.Date(2026, 1, 9, 8, 0, 0, 0, time.UTC)
+	m.AIDeltaAnalysisGeneratedAt = time.Date(2026, 1, 12, 18, 30, 0, 0, time.UTC)
+	if regenerated := PrepareFeedEntry("2026-01-09", m); !regenerated.Published.Equal(m.LastUpdated) || !regenerated.Updated.Equal(m.AIDeltaAnalysisGeneratedAt) {
+		t.Errorf("expected published %v and updated %v, got %+v", m.LastUpdated, m.AIDeltaAnalysisGeneratedAt, regenerated)
+	}
+	if entry.Analysis != "Reading slowed." || len(entry.KeyMetrics) != 5 {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if text := feedEntryText(entry); !strings.HasPrefix(text, "Total Articles: 10 · Read Rate: 40.0%") {
+		t.Errorf("unexpected entry text: %q", text)
+	}
+}
+
+func TestGenerateFeeds(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(originalDir)
+
+	contentDir := filepath.Join("internal", "web", "content")
+	os.MkdirAll(contentDir, 0755)
+	os.WriteFile(filepath.Join(contentDir, "landing.yml"), []byte("header:\n  project_name: \"Reading\"\n  site_url: \"https://example.com/site/\"\nfooter:\n  author: \"Jane\"\n"), 0644)
+
+	entries := []FeedEntry{
+		PrepareFeedEntry("2026-01-02", schema.Metrics{LastUpdated: time.Date(2026, 1, 2, 8, 0, 0, 0, time.UTC), TotalArticles: 5, AIDeltaAnalysis: "Rewritten.", AIDeltaAnalysisGeneratedAt: time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)}),
+		PrepareFeedEntry("2026-01-09", schema.Metrics{LastUpdated: time.Date(2026, 1, 9, 8, 0, 0, 0, time.UTC), TotalArticles: 7, AIDeltaAnalysis: "Backlog <shrank>."}),
+	}
+
+	service := NewAnalyticsService("dist")
+	if err := service.GenerateFeeds(entries); err != nil {
+		t.Fatalf("GenerateFeeds() error = %v", err)
+	}
+
+	var atom AtomFeed
+	content, err := os.ReadFile(filepath.Join("dist", "feed.xml"))
+	if err != nil {
+		t.Fatalf("feed.xml was not created: %v", err)
+	}
+	if err := xml.Unmarshal(content, &atom); err != nil {
+		t.Fatalf("feed.xml is not valid XML: %v", err)
+	}
+	if atom.Title != "Reading" || atom.Updated != "2026-01-10T09:00:00Z" || len(atom.Entries) != 2 {
+		t.Fatalf("unexpected Atom feed: %+v", atom)
+	}
+	newest := atom.Entries[0]
+	if newest.Link.Href != "https://example.com/site/history/2026-01-09/analytics.html" || newest.ID != newest.Link.Href {
+		t.Errorf("expected the newest entry to link to its archived page, got %+v", newest.Link)
+	}
+	if !strings.Contains(newest.Content.Body, "<p>Backlog &lt;shrank&gt;.</p>") || !strings.Contains(newest.Content.Body, "Total Articles:</strong> 7") {
+		t.Errorf("unexpected entry content: %s", newest.Content.Body)
+	}
+
+	var feed JSONFeed
+	content, err = os.ReadFile(filepath.Join("dist", "feed.json"))
+	if err != nil {
+		t.Fatalf("feed.json was not created: %v", err)
+	}
+	if err := json.Unmarshal(content, &feed); err != nil {
+		t.Fatalf("feed.json is not valid JSON: %v", err)
+	}
+	if feed.Version != "https://jsonfeed.org/version/1.1" || len(feed.Items) != 2 || feed.Authors[0].Name != "Jane" {
+		t.Fatalf("unexpected JSON feed: %+v", feed)
+	}
+	if feed.Items[1].URL != "https://example.com/site/history/2026-01-02/analytics.html" || feed.Items[1].DatePublished != "2026-01-02T08:00:00Z" || feed.Items[1].DateModified != "2026-01-10T09:00:00Z" {
+		t.Errorf("unexpected oldest item: %+v", feed.Items[1])
+	}
+	if !strings.HasSuffix(feed.Items[0].ContentText, "\n\nBacklog <shrank>.") {
+		t.Errorf("expected the analysis in the text content, got %q", feed.Items[0].ContentText)
+	}
+}
+
+func TestGenerateFeedsRequiresAbsoluteSiteURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		landing string // landing.yml content; empty means no file
+	}{
+		{name: "no landing content"},
+		{name: "no site url", landing: "header:\n  project_name: \"Reading\"\n"},
+		{name: "relative site url", landing: "header:\n  site_url: \"/site\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			originalDir, _ := os.Getwd()
+			if err := os.Chdir(tmpDir); err != nil {
+				t.Fatal(err)
+			}
+			defer os.Chdir(originalDir)
+
+			if tt.landing != "" {
+				contentDir := filepath.Join("internal", "web", "content")
+				os.MkdirAll(contentDir, 0755)
+				os.WriteFile(filepath.Join(contentDir, "landing.yml"), []byte(tt.landing), 0644)
+			}
+
+			entries := []FeedEntry{PrepareFeedEntry("2026-01-02", schema.Metrics{TotalArticles: 5})}
+			if err := NewAnalyticsService("dist").GenerateFeeds(entries); err == nil {
+				t.Error("expected an error without an absolute site URL")
+			}
+			for _, name := range []string{"feed.xml", "feed.json"} {
+				if _, err := os.Stat(filepath.Join("dist", name)); !os.IsNotExist(err) {
+					t.Errorf("expected no %s to be written", name)
+				}
 			}
 		})
 	}
 }
+
+// ============================================================================
+// NewSiteHandler: Serves the site and live OpenMetrics
+// ============================================================================
+
+func TestNewSiteHandler(t *testing.T) {
+	distDir := t.TempDir()
+	os.WriteFile(filepath.Join(distDir, "index.html"), []byte("<h1>Home</h1>"), 0644)
+
+	var loadErr error
+	handler := NewSiteHandler(distDir, func() (schema.Metrics, error) {
+		return schema.Metrics{TotalArticles: 12}, loadErr
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/openmetrics-text") {
+		t.Fatalf("unexpected /metrics response: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "reading_articles 12\n") {
+		t.Errorf("expected the latest snapshot in /metrics, got:\n%s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Home") {
+		t.Errorf("expected the site to be served, got %d", rec.Code)
+	}
+
+	loadErr = os.ErrNotExist
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected a server error when the snapshot fails to load, got %d", rec.Code)
+	}
+}
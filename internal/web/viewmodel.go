@@ -2,6 +2,7 @@ package web
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"html/template"
 	"time"
 
@@ -19,6 +20,10 @@ type GenConfig struct {
 	IsHistorical bool
 	HistoryDates []string
 	ReportDate   string
+	AsOf         time.Time // "today" for month badges; zero uses the snapshot's LastUpdated
+
+	// SourceHistory is each source's read rate across snapshots, charted on the source pages
+	SourceHistory map[string][]ReadRatePoint
 }
 
 // ==============================================================================
@@ -40,6 +45,12 @@ type YearChartData struct {
 	DataJSON   json.RawMessage
 }
 
+// ReadRatePoint is a source's read rate on one snapshot date
+type ReadRatePoint struct {
+	Date     string  `json:"date"`
+	ReadRate float64 `json:"read_rate"`
+}
+
 // MonthChartData holds prepared month chart data
 type MonthChartData struct {
 	LabelsJSON    json.RawMessage
@@ -47,6 +58,86 @@ type MonthChartData struct {
 	TotalDataJSON json.RawMessage
 }
 
+// ==============================================================================
+// FEED MODELS
+// ==============================================================================
+
+// FeedEntry is one snapshot's report in the site feeds
+type FeedEntry struct {
+	Date       string    // snapshot date, YYYY-MM-DD
+	Published  time.Time // when the snapshot was taken
+	Updated    time.Time // the later of Published and when its AI analysis was generated
+	KeyMetrics []schema.KeyMetric
+	Analysis   string // AI delta analysis, when the snapshot has one
+}
+
+// AtomFeed is the root element of dist/feed.xml
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []AtomLink  `xml:"link"`
+	Author  AtomAuthor  `xml:"author"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomLink is an Atom link element
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// AtomAuthor is an Atom author element
+type AtomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// AtomEntry is one snapshot in the Atom feed
+type AtomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Link      AtomLink    `xml:"link"`
+	Summary   string      `xml:"summary"`
+	Content   AtomContent `xml:"content"`
+}
+
+// AtomContent is an entry's HTML body
+type AtomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// JSONFeed is the root object of dist/feed.json (JSON Feed 1.1)
+type JSONFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	FeedURL     string           `json:"feed_url"`
+	Authors     []JSONFeedAuthor `json:"authors,omitempty"`
+	Items       []JSONFeedItem   `json:"items"`
+}
+
+// JSONFeedAuthor is a JSON Feed author object
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// JSONFeedItem is one snapshot in the JSON feed
+type JSONFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	Summary       string `json:"summary"`
+	ContentHTML   string `json:"content_html"`
+	ContentText   string `json:"content_text"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified"`
+}
+
 // ==============================================================================
 // VIEW MODELS
 // ==============================================================================
@@ -65,6 +156,7 @@ type ViewModel struct {
 	LastUpdated                      time.Time
 	AIDeltaAnalysis                  string
 	Sources                          []schema.SourceInfo
+	SourceSlugs                      map[string]string // source name -> unique sources/<slug>.html page
 	Months                           []schema.MonthInfo
 	Years                            []schema.YearInfo
 	AllYears                         []string
@@ -81,13 +173,70 @@ type ViewModel struct {
 	ReadUnreadByYearJSON             template.JS
 	UnreadArticleAgeDistributionJSON template.JS
 	UnreadByYearJSON                 template.JS
+	Goals                            []schema.GoalView
+	SourceHealth                     []schema.SourceHealthView
+	BacklogForecast                  *schema.BacklogForecast
+	BacklogForecastJSON              template.JS
 	TopOldestUnreadArticles          []schema.ArticleMeta
 	EvolutionData                    schema.EvolutionData
 	Landing                          schema.Landing
 
 	// Historical Metrics context
-	BaseURL      string
-	IsHistorical bool
-	HistoryDates []string
-	ReportDate   string
+	BaseURL       string
+	IsHistorical  bool
+	HistoryDates  []string
+	ReportDate    string
+	Reconstructed *schema.Reconstruction // set when the snapshot was backfilled from the ledger
+
+	// Source page context, set only when rendering sources/<slug>.html
+	Source *SourcePage
+
+	// Year page context, set only when rendering years/<YYYY>.html
+	Year *YearPage
+}
+
+// SourcePage holds the data for a single source's page
+type SourcePage struct {
+	Name                string
+	Slug                string
+	Color               string
+	Added               string
+	Retired             string
+	Total               int
+	Read                int
+	Unread              int
+	ReadPct             float64
+	Health              *schema.SourceHealth
+	YearChartJSON       template.JS
+	MonthChartJSON      template.JS
+	ReadRateHistoryJSON template.JS
+	Backlog             []schema.ArticleMeta
+}
+
+// YearPage holds the data for a single year-in-review page. Articles belong to the year they were published.
+type YearPage struct {
+	Year              string
+	PreviousYear      string
+	NextYear          string
+	Total             int
+	Read              int
+	Unread            int
+	ReadPct           float64
+	BusiestMonth      string
+	BusiestMonthCount int
+	SourceCount       int
+	TopSources        []YearSource
+	Backlog           []schema.ArticleMeta
+	MonthChartJSON    template.JS
+	SourceMixJSON     template.JS
+	Summary           string // plain-text version of the summary card, for sharing
+}
+
+// YearSource is one source's articles within a year
+type YearSource struct {
+	Name    string
+	Slug    string
+	Read    int
+	Total   int
+	ReadPct float64
 }
@@ -1,7 +1,7 @@
 # === Go Variables ===
 BIN_DIR = bin
 
-.PHONY: lint-go fmt-go test-go cov-go go-check go-update metrics-build setup-tailwind web-build
+.PHONY: lint-go fmt-go test-go cov-go go-check go-update metrics-build metrics-compact minio-up test-s3 setup-tailwind web-build
 
 # ==============================================================================
 # GO DEVELOPMENT TARGETS
@@ -32,7 +32,18 @@ go-update: ## Update Go dependencies and run go mod tidy
 metrics-build: ## Build and run the metrics calculator
 	mkdir -p $(BIN_DIR)
 	go build -o $(BIN_DIR)/metricsjson ./cmd/metrics
-	./$(BIN_DIR)/metricsjson
+	./$(BIN_DIR)/metricsjson fetch
+	@# Exit 4 (AI analysis unavailable) is only a warning, so the new snapshot still ships
+	./$(BIN_DIR)/metricsjson summarize -skip-without-key || { status=$$?; [ $$status -eq 4 ] && echo "Warning: AI delta analysis unavailable, snapshot published without it"; }
+
+metrics-compact: ## Fold the loose snapshots in metrics/ into the compressed archive
+	go run ./cmd/metrics compact
+
+minio-up: ## Start a local MinIO server for the S3 snapshot store
+	$(DOCKER) run -d --rm --name reading-minio -p 9000:9000 -p 9001:9001 docker.io/minio/minio server /data --console-address :9001
+
+test-s3: ## Run the S3 snapshot store tests against the local MinIO server
+	S3_TEST_ENDPOINT=http://localhost:9000 go test -v -run S3 ./internal/metrics
 
 setup-tailwind: ## Set up Tailwind CSS CLI
 	@mkdir -p $(BIN_DIR)